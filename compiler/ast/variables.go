@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-// Variable interface represents assignable nodes in Goby, currently are Identifier, InstanceVariable and Constant
+// Variable interface represents assignable nodes in Goby, currently are Identifier, InstanceVariable, GlobalVariable and Constant
 type Variable interface {
 	variableNode()
 	ReturnValue() string
@@ -81,6 +81,28 @@ func (iv *InstanceVariable) String() string {
 	return iv.Value
 }
 
+// GlobalVariable represents a global variable like `$stdout`
+type GlobalVariable struct {
+	*BaseNode
+	Value string
+}
+
+func (gv *GlobalVariable) variableNode() {}
+
+// ReturnValue is a polymorphic method for returning a value
+func (gv *GlobalVariable) ReturnValue() string {
+	return gv.Value
+}
+func (gv *GlobalVariable) expressionNode() {}
+
+// TokenLiteral returns the global variable's token literal
+func (gv *GlobalVariable) TokenLiteral() string {
+	return gv.Token.Literal
+}
+func (gv *GlobalVariable) String() string {
+	return gv.Value
+}
+
 // Constant represents a constant that may include namespace
 type Constant struct {
 	*BaseNode
@@ -16,6 +16,8 @@ func (g *Generator) compileExpression(is *InstructionSet, exp ast.Expression, sc
 		is.define(GetConstant, sourceLine, exp.Value, exp.IsNamespace)
 	case *ast.InstanceVariable:
 		is.define(GetInstanceVariable, sourceLine, exp.Value)
+	case *ast.GlobalVariable:
+		is.define(GetGlobalVariable, sourceLine, exp.Value)
 	case *ast.IntegerLiteral:
 		is.define(PutObject, sourceLine, exp.Value)
 	case *ast.FloatLiteral:
@@ -162,6 +164,8 @@ func (g *Generator) compileAssignExpression(is *InstructionSet, exp *ast.AssignE
 				is.define(SetLocal, exp.Line(), depth, index)
 			case *ast.InstanceVariable:
 				is.define(SetInstanceVariable, exp.Line(), name.Value)
+			case *ast.GlobalVariable:
+				is.define(SetGlobalVariable, exp.Line(), name.Value)
 			case *ast.Constant:
 				is.define(SetConstant, exp.Line(), name.Value)
 			}
@@ -235,7 +239,7 @@ func (g *Generator) compileIfExpression(is *InstructionSet, exp *ast.IfExpressio
 
 func (g *Generator) compilePrefixExpression(is *InstructionSet, exp *ast.PrefixExpression, scope *scope, table *localTable) {
 	switch exp.Operator {
-	case "!":
+	case "!", "~":
 		g.compileExpression(is, exp.Right, scope, table)
 		is.define(Send, exp.Line(), exp.Operator, 0, "", initArgSet(0))
 	case "*":
@@ -45,6 +45,8 @@ const (
 	Pop
 	Dup
 	Leave
+	GetGlobalVariable
+	SetGlobalVariable
 	InstructionCount
 )
 
@@ -80,6 +82,8 @@ var InstructionNameTable = []string{
 	Pop:                 "pop",
 	Dup:                 "dup",
 	Leave:               "leave",
+	GetGlobalVariable:   "getglobalvariable",
+	SetGlobalVariable:   "setglobalvariable",
 }
 
 // Instruction represents compiled bytecode instruction
@@ -89,6 +93,8 @@ type Instruction struct {
 	line       int
 	anchor     *anchor
 	sourceLine int
+	// definedSet is the instruction set defined by a def_method, def_singleton_method or def_class instruction
+	definedSet *InstructionSet
 }
 
 // Inspect is for inspecting the instruction's content
@@ -126,6 +132,12 @@ func (i *Instruction) SourceLine() int {
 	return i.sourceLine
 }
 
+// DefinedSet returns the instruction set defined by a def_method, def_singleton_method or def_class instruction.
+// It returns nil for other instructions.
+func (i *Instruction) DefinedSet() *InstructionSet {
+	return i.definedSet
+}
+
 type anchor struct {
 	line int
 }
@@ -139,13 +139,15 @@ func (g *Generator) compileBreakStatement(is *InstructionSet, stmt ast.Statement
 }
 
 func (g *Generator) compileClassStmt(is *InstructionSet, stmt *ast.ClassStatement, scope *scope, table *localTable) {
+	var def *Instruction
+
 	is.define(PutSelf, stmt.Line())
 
 	if stmt.SuperClass != nil {
 		g.compileExpression(is, stmt.SuperClass, scope, table)
-		is.define(DefClass, stmt.Line(), "class:"+stmt.Name.Value, stmt.SuperClassName)
+		def = is.define(DefClass, stmt.Line(), "class:"+stmt.Name.Value, stmt.SuperClassName)
 	} else {
-		is.define(DefClass, stmt.Line(), "class:"+stmt.Name.Value)
+		def = is.define(DefClass, stmt.Line(), "class:"+stmt.Name.Value)
 	}
 
 	is.define(Pop, stmt.Line())
@@ -156,6 +158,7 @@ func (g *Generator) compileClassStmt(is *InstructionSet, stmt *ast.ClassStatemen
 	newIS := &InstructionSet{}
 	newIS.name = stmt.Name.Value
 	newIS.isType = ClassDef
+	def.definedSet = newIS
 
 	g.compileCodeBlock(newIS, stmt.Body, scope, scope.localTable)
 	newIS.define(Leave, stmt.Line())
@@ -164,13 +167,14 @@ func (g *Generator) compileClassStmt(is *InstructionSet, stmt *ast.ClassStatemen
 
 func (g *Generator) compileModuleStmt(is *InstructionSet, stmt *ast.ModuleStatement, scope *scope) {
 	is.define(PutSelf, stmt.Line())
-	is.define(DefClass, stmt.Line(), "module:"+stmt.Name.Value)
+	def := is.define(DefClass, stmt.Line(), "module:"+stmt.Name.Value)
 	is.define(Pop, stmt.Line())
 
 	scope = newScope()
 	newIS := &InstructionSet{}
 	newIS.name = stmt.Name.Value
 	newIS.isType = ClassDef
+	def.definedSet = newIS
 
 	g.compileCodeBlock(newIS, stmt.Body, scope, scope.localTable)
 	newIS.define(Leave, stmt.Line())
@@ -178,25 +182,28 @@ func (g *Generator) compileModuleStmt(is *InstructionSet, stmt *ast.ModuleStatem
 }
 
 func (g *Generator) compileDefStmt(is *InstructionSet, stmt *ast.DefStatement, scope *scope) {
+	var def *Instruction
+
 	switch stmt.Receiver.(type) {
 	case nil:
 		is.define(PutSelf, stmt.Line())
 		is.define(PutString, stmt.Line(), stmt.Name.Value)
-		is.define(DefMethod, stmt.Line(), len(stmt.Parameters))
+		def = is.define(DefMethod, stmt.Line(), len(stmt.Parameters))
 	default:
 		g.compileExpression(is, stmt.Receiver, scope, scope.localTable)
 		is.define(PutString, stmt.Line(), stmt.Name.Value)
-		is.define(DefSingletonMethod, stmt.Line(), len(stmt.Parameters))
+		def = is.define(DefSingletonMethod, stmt.Line(), len(stmt.Parameters))
 	}
 
 	scope = newScope()
 
 	// compile method definition's content
 	newIS := &InstructionSet{
-		name:   stmt.Name.Value,
-		isType: MethodDef,
+		name:     stmt.Name.Value,
+		isType:   MethodDef,
 		argTypes: initArgSet(len(stmt.Parameters)),
 	}
+	def.definedSet = newIS
 
 	for i := 0; i < len(stmt.Parameters); i++ {
 		switch exp := stmt.Parameters[i].(type) {
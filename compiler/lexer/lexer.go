@@ -50,6 +50,11 @@ func (l *Lexer) NextToken() token.Token {
 		tok.Type = token.String
 		tok.Line = l.line
 		return tok
+	case '`':
+		tok.Literal = l.readString(l.ch)
+		tok.Type = token.Command
+		tok.Line = l.line
+		return tok
 	case '=':
 		if l.peekChar() == '=' {
 			l.readChar()
@@ -90,6 +95,9 @@ func (l *Lexer) NextToken() token.Token {
 			} else {
 				tok = token.CreateOperator("<=", l.line)
 			}
+		} else if l.peekChar() == '<' {
+			l.readChar()
+			tok = token.CreateOperator("<<", l.line)
 		} else {
 			tok = token.CreateOperator("<", l.line)
 		}
@@ -97,6 +105,9 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '=' {
 			l.readChar()
 			tok = token.CreateOperator(">=", l.line)
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.CreateOperator(">>", l.line)
 		} else {
 			tok = token.CreateOperator(">", l.line)
 		}
@@ -156,7 +167,13 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '&' {
 			l.readChar()
 			tok = token.CreateOperator("&&", l.line)
+		} else {
+			tok = token.CreateOperator("&", l.line)
 		}
+	case '^':
+		tok = token.CreateOperator("^", l.line)
+	case '~':
+		tok = token.CreateOperator("~", l.line)
 	case '%':
 		tok = token.CreateOperator("%", l.line)
 	case '#':
@@ -207,6 +224,15 @@ func (l *Lexer) NextToken() token.Token {
 				return tok
 			}
 
+			return token.Token{Type: token.Illegal, Literal: string(l.ch), Line: l.line}
+		} else if isGlobalVariable(l.ch) {
+			if isLetter(l.peekChar()) {
+				tok.Literal = string(l.readGlobalVariable())
+				tok.Type = token.GlobalVariable
+				tok.Line = l.line
+				return tok
+			}
+
 			return token.Token{Type: token.Illegal, Literal: string(l.ch), Line: l.line}
 		} else if isDigit(l.ch) {
 			tok.Literal = string(l.readNumber())
@@ -254,7 +280,8 @@ func (l *Lexer) readIdentifier() []rune {
 		l.readChar()
 	}
 
-	if l.ch == '?' {
+	// Method names can end with `?` or `!`, but `foo!=bar` means `foo != bar`
+	if l.ch == '?' || (l.ch == '!' && l.peekChar() != '=') {
 		l.readChar()
 	}
 
@@ -277,6 +304,15 @@ func (l *Lexer) readInstanceVariable() []rune {
 	return l.input[position:l.position]
 }
 
+func (l *Lexer) readGlobalVariable() []rune {
+	position := l.position
+	l.readChar()
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	return l.input[position:l.position]
+}
+
 func (l *Lexer) readString(ch rune) string {
 	l.readChar()
 
@@ -363,12 +399,16 @@ func isInstanceVariable(ch rune) bool {
 	return ch == '@'
 }
 
+func isGlobalVariable(ch rune) bool {
+	return ch == '$'
+}
+
 func isEscapedChar(ch rune) bool {
 	return ch == '\\'
 }
 
 func escapedCharResult(quotedChar rune, peeked rune) string {
-	if quotedChar == '"' {
+	if quotedChar == '"' || quotedChar == '`' {
 		switch peeked {
 		case 'n':
 			return "\n"
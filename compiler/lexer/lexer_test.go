@@ -39,6 +39,67 @@ func TestNextToken(t *testing.T) {
 				{token.Int, "15", 3},
 			},
 		},
+		{
+			`
+				a & b && c
+				a | b || c ^ ~d
+				a << 2 >> 1 <= b
+			`,
+			[]struct {
+				expectedType    token.Type
+				expectedLiteral string
+				expectedLine    int
+			}{
+				{token.Ident, "a", 1},
+				{token.Ampersand, "&", 1},
+				{token.Ident, "b", 1},
+				{token.And, "&&", 1},
+				{token.Ident, "c", 1},
+				{token.Ident, "a", 2},
+				{token.Bar, "|", 2},
+				{token.Ident, "b", 2},
+				{token.Or, "||", 2},
+				{token.Ident, "c", 2},
+				{token.Caret, "^", 2},
+				{token.Tilde, "~", 2},
+				{token.Ident, "d", 2},
+				{token.Ident, "a", 3},
+				{token.LShift, "<<", 3},
+				{token.Int, "2", 3},
+				{token.RShift, ">>", 3},
+				{token.Int, "1", 3},
+				{token.LTE, "<=", 3},
+				{token.Ident, "b", 3},
+			},
+		},
+		{
+			`
+				$stdout = $out_2
+				$
+			`,
+			[]struct {
+				expectedType    token.Type
+				expectedLiteral string
+				expectedLine    int
+			}{
+				{token.GlobalVariable, "$stdout", 1},
+				{token.Assign, "=", 1},
+				{token.GlobalVariable, "$out_2", 1},
+				{token.Illegal, "$", 2},
+			},
+		},
+		{
+			"out = `ls \\\"$HOME\\\"`",
+			[]struct {
+				expectedType    token.Type
+				expectedLiteral string
+				expectedLine    int
+			}{
+				{token.Ident, "out", 0},
+				{token.Assign, "=", 0},
+				{token.Command, `ls "$HOME"`, 0},
+			},
+		},
 		{
 			`
 	class Person
@@ -613,6 +674,32 @@ func TestNextToken(t *testing.T) {
 				{token.EOF, "", 15},
 			},
 		},
+		{
+			`a.thread_safe!
+			def save!; end
+			a!=b
+			empty? !c`,
+			[]struct {
+				expectedType    token.Type
+				expectedLiteral string
+				expectedLine    int
+			}{
+				{token.Ident, "a", 0},
+				{token.Dot, ".", 0},
+				{token.Ident, "thread_safe!", 0},
+				{token.Def, "def", 1},
+				{token.Ident, "save!", 1},
+				{token.Semicolon, ";", 1},
+				{token.End, "end", 1},
+				{token.Ident, "a", 2},
+				{token.NotEq, "!=", 2},
+				{token.Ident, "b", 2},
+				{token.Ident, "empty?", 3},
+				{token.Bang, "!", 3},
+				{token.Ident, "c", 3},
+				{token.EOF, "", 3},
+			},
+		},
 	}
 
 	for i, tt := range tests {
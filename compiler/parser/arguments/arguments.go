@@ -24,10 +24,12 @@ var Types = map[int]string{
 var Tokens = map[token.Type]bool{
 	token.Int:              true,
 	token.String:           true,
+	token.Command:          true,
 	token.True:             true,
 	token.False:            true,
 	token.Null:             true,
 	token.InstanceVariable: true,
+	token.GlobalVariable:   true,
 	token.Ident:            true,
 	token.Constant:         true,
 }
@@ -23,6 +23,20 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+// parseCommandExpression turns a backtick command like `ls -l` into a call of the "`" method, with the command as its argument
+func (p *Parser) parseCommandExpression() ast.Expression {
+	selfTok := token.Token{Type: token.Self, Literal: "self", Line: p.curToken.Line}
+	self := &ast.SelfExpression{BaseNode: &ast.BaseNode{Token: selfTok}}
+	command := &ast.StringLiteral{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+
+	return &ast.CallExpression{
+		BaseNode:  &ast.BaseNode{Token: p.curToken},
+		Receiver:  self,
+		Method:    "`",
+		Arguments: []ast.Expression{command},
+	}
+}
+
 func (p *Parser) parseFloatLiteral(integerPart ast.Expression) ast.Expression {
 	// Get the fractional part of the token
 	p.nextToken()
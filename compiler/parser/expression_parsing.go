@@ -285,6 +285,10 @@ func (p *Parser) parseInstanceVariable() ast.Expression {
 	return &ast.InstanceVariable{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
 }
 
+func (p *Parser) parseGlobalVariable() ast.Expression {
+	return &ast.GlobalVariable{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+}
+
 func (p *Parser) parseMultiVariables(left ast.Expression) ast.Expression {
 	var1, ok := left.(ast.Variable)
 
@@ -272,6 +272,27 @@ func TestCallExpression(t *testing.T) {
 	infix2.TestableRightExpression().IsIntegerLiteral(t).ShouldEqualTo(5)
 }
 
+func TestCommandExpression(t *testing.T) {
+	input := "puts(`git log -n 1 \"--format=%H\"`)"
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	callExpression := program.FirstStmt().IsExpression(t).IsCallExpression(t)
+	callExpression.ShouldHaveMethodName("puts")
+
+	command := callExpression.NthArgument(1).IsCallExpression(t)
+	command.TestableReceiver().IsSelfExpression(t)
+	command.ShouldHaveMethodName("`")
+	command.ShouldHaveNumbersOfArguments(1)
+	command.NthArgument(1).IsStringLiteral(t).ShouldEqualTo(`git log -n 1 "--format=%H"`)
+}
+
 func TestCallExpressionWithBlock(t *testing.T) {
 	input := `
 	[1, 2, 3, 4].each do |i|
@@ -549,6 +570,11 @@ func TestInfixExpression(t *testing.T) {
 	}{
 		{"4 + 1;", 4, "+", 1},
 		{"3 - 2;", 3, "-", 2},
+		{"12 & 10;", 12, "&", 10},
+		{"12 | 10;", 12, "|", 10},
+		{"12 ^ 10;", 12, "^", 10},
+		{"1 << 4;", 1, "<<", 4},
+		{"16 >> 2;", 16, ">>", 2},
 	}
 
 	for _, tt := range infixTests {
@@ -64,8 +64,10 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.Ident, p.parseIdentifier)
 	p.registerPrefix(token.Constant, p.parseConstant)
 	p.registerPrefix(token.InstanceVariable, p.parseInstanceVariable)
+	p.registerPrefix(token.GlobalVariable, p.parseGlobalVariable)
 	p.registerPrefix(token.Int, p.parseIntegerLiteral)
 	p.registerPrefix(token.String, p.parseStringLiteral)
+	p.registerPrefix(token.Command, p.parseCommandExpression)
 	p.registerPrefix(token.True, p.parseBooleanLiteral)
 	p.registerPrefix(token.False, p.parseBooleanLiteral)
 	p.registerPrefix(token.Null, p.parseNilExpression)
@@ -73,6 +75,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.Plus, p.parsePrefixExpression)
 	p.registerPrefix(token.Asterisk, p.parsePrefixExpression)
 	p.registerPrefix(token.Bang, p.parsePrefixExpression)
+	p.registerPrefix(token.Tilde, p.parsePrefixExpression)
 	p.registerPrefix(token.LParen, p.parseGroupedExpression)
 	p.registerPrefix(token.If, p.parseIfExpression)
 	p.registerPrefix(token.Case, p.parseCaseExpression)
@@ -98,6 +101,11 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.GTE, p.parseInfixExpression)
 	p.registerInfix(token.COMP, p.parseInfixExpression)
+	p.registerInfix(token.Ampersand, p.parseInfixExpression)
+	p.registerInfix(token.Bar, p.parseInfixExpression)
+	p.registerInfix(token.Caret, p.parseInfixExpression)
+	p.registerInfix(token.LShift, p.parseInfixExpression)
+	p.registerInfix(token.RShift, p.parseInfixExpression)
 	p.registerInfix(token.And, p.parseInfixExpression)
 	p.registerInfix(token.Or, p.parseInfixExpression)
 	p.registerInfix(token.OrEq, p.parseAssignExpression)
@@ -240,9 +248,10 @@ func (p *Parser) IsNotDefMethodToken() bool {
 	return p.curToken.Type != token.Ident && !(p.peekToken.Type == token.Dot && (p.curToken.Type == token.InstanceVariable || p.curToken.Type == token.Constant || p.curToken.Type == token.Self))
 }
 
-// Token type InstanceVariable and Constant will trigger IsNotParamsToken()
+// Token type InstanceVariable, GlobalVariable and Constant will trigger IsNotParamsToken()
 var invalidParams = map[token.Type]bool{
 	token.InstanceVariable: true,
+	token.GlobalVariable:   true,
 	token.Constant:         true,
 }
 
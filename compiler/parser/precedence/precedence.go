@@ -12,6 +12,9 @@ const (
 	Range
 	Equals
 	Compare
+	BitOr
+	BitAnd
+	Shift
 	Sum
 	Product
 	BangPrefix
@@ -29,6 +32,11 @@ var LookupTable = map[token.Type]int{
 	token.GT:                 Compare,
 	token.GTE:                Compare,
 	token.COMP:               Compare,
+	token.Bar:                BitOr,
+	token.Caret:              BitOr,
+	token.Ampersand:          BitAnd,
+	token.LShift:             Shift,
+	token.RShift:             Shift,
 	token.And:                Logic,
 	token.Or:                 Logic,
 	token.Range:              Range,
@@ -18,9 +18,11 @@ const (
 	Constant         = "CONSTANT"
 	Ident            = "IDENT"
 	InstanceVariable = "INSTANCE_VAR"
+	GlobalVariable   = "GLOBAL_VAR"
 	Int              = "INT"
 	Float            = "FLOAT"
 	String           = "STRING"
+	Command          = "COMMAND"
 	Comment          = "COMMENT"
 
 	Assign   = "="
@@ -38,6 +40,12 @@ const (
 	OrEq     = "||="
 	Modulo   = "%"
 
+	Ampersand = "&"
+	Caret     = "^"
+	Tilde     = "~"
+	LShift    = "<<"
+	RShift    = ">>"
+
 	LT   = "<"
 	LTE  = "<="
 	GT   = ">"
@@ -123,6 +131,12 @@ var operators = map[string]Type{
 	"||=": OrEq,
 	"%":   Modulo,
 
+	"&":  Ampersand,
+	"^":  Caret,
+	"~":  Tilde,
+	"<<": LShift,
+	">>": RShift,
+
 	"<":   LT,
 	"<=":  LTE,
 	">":   GT,
@@ -3,6 +3,7 @@ module github.com/goby-lang/goby
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/chzyer/readline v0.0.0-20180603132655-2972be24d48e
 	github.com/dave/jennifer v0.19.0
 	github.com/dlclark/regexp2 v1.2.0
@@ -17,6 +18,9 @@ require (
 	github.com/mattn/go-isatty v0.0.10 // indirect
 	github.com/pkg/profile v1.3.0
 	github.com/st0012/metago v0.0.0-20170803060228-9a814882b21a
+	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
 	golang.org/x/sys v0.0.0-20191029155521-f43be2a4598c // indirect
+	golang.org/x/text v0.3.7
 	golang.org/x/tools v0.0.0-20200417140056-c07e33ef3290 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
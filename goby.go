@@ -27,9 +27,18 @@ func main() {
 	versionOptionPtr := flag.Bool("v", false, "Show current Goby version")
 	interactiveOptionPtr := flag.Bool("i", false, "Run interactive goby")
 	issueOptionPtr := flag.Bool("e", false, "Generate reporting format")
+	seedOptionPtr := flag.Int64("seed", 0, "Seed the random generator of rand, Array#shuffle and Array#sample for reproducible runs")
 
 	flag.Parse()
 
+	var options []vm.Option
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			options = append(options, vm.WithSeed(*seedOptionPtr))
+		}
+	})
+
 	if *interactiveOptionPtr {
 		igb.StartIgb(version)
 		os.Exit(0)
@@ -61,7 +70,7 @@ func main() {
 		reportErrorAndExit(err)
 
 		dir := extractDirFromFilePath(filePath, fileInfo)
-		v, err := vm.New(dir, args)
+		v, err := vm.New(dir, args, options...)
 		if err != nil {
 			reportErrorAndExit(err)
 		}
@@ -87,6 +96,7 @@ func main() {
 			reportErrorAndExit(err)
 		}
 		v.ExecInstructions(instructionSets, filePath)
+		v.RunExitHooks()
 		return
 	default:
 		fp = flag.Arg(0)
@@ -111,10 +121,10 @@ func main() {
 
 		if *issueOptionPtr {
 			fmt.Println("Will generate issue report on error...")
-			v, err = vm.InitIssueReportVM(dir, args)
+			v, err = vm.InitIssueReportVM(dir, args, options...)
 			defer vm.PrintError(v)
 		} else {
-			v, err = vm.New(dir, args)
+			v, err = vm.New(dir, args, options...)
 		}
 		reportErrorAndExit(err)
 
@@ -122,6 +132,7 @@ func main() {
 		reportErrorAndExit(err)
 
 		v.ExecInstructions(instructionSets, fp)
+		v.RunExitHooks()
 	default:
 		fmt.Printf("Unknown file extension: %s", fileExt)
 	}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/goby-lang/goby/vm"
@@ -122,6 +123,7 @@ func run(receiver Object, sourceLine int, t *Thread, args []Object) Object {
 		return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
 	}
 
+	ctx, args := contextFromArgs(args)
 	queryString := args[0].(*vm.StringObject).Value().(string)
 	execArgs := []interface{}{}
 
@@ -129,10 +131,10 @@ func run(receiver Object, sourceLine int, t *Thread, args []Object) Object {
 		execArgs = append(execArgs, arg.Value())
 	}
 
-	_, err = conn.Exec(queryString, execArgs...)
+	_, err = conn.ExecContext(ctx, queryString, execArgs...)
 
 	if err != nil {
-		return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+		return queryError(v, ctx, sourceLine, err)
 	}
 
 	return vm.TRUE
@@ -206,6 +208,7 @@ func exec(receiver Object, sourceLine int, t *Thread, args []Object) Object {
 		return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
 	}
 
+	ctx, args := contextFromArgs(args)
 	queryString := args[0].(*vm.StringObject).Value().(string)
 	execArgs := []interface{}{}
 
@@ -216,10 +219,10 @@ func exec(receiver Object, sourceLine int, t *Thread, args []Object) Object {
 	// The reason I implement this way: https://github.com/lib/pq/issues/24
 	var id int
 
-	err = conn.QueryRow(fmt.Sprintf("%s RETURNING id", queryString), execArgs...).Scan(&id)
+	err = conn.QueryRowContext(ctx, fmt.Sprintf("%s RETURNING id", queryString), execArgs...).Scan(&id)
 
 	if err != nil {
-		return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+		return queryError(v, ctx, sourceLine, err)
 	}
 
 	return v.InitIntegerObject(id)
@@ -269,6 +272,7 @@ func query(receiver Object, sourceLine int, t *Thread, args []Object) Object {
 		return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
 	}
 
+	ctx, args := contextFromArgs(args)
 	queryString := args[0].(*StringObject).Value().(string)
 	execArgs := []interface{}{}
 
@@ -276,10 +280,10 @@ func query(receiver Object, sourceLine int, t *Thread, args []Object) Object {
 		execArgs = append(execArgs, arg.Value())
 	}
 
-	rows, err := conn.Queryx(queryString, execArgs...)
+	rows, err := conn.QueryxContext(ctx, queryString, execArgs...)
 
 	if err != nil {
-		return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
+		return queryError(t.VM(), ctx, sourceLine, err)
 	}
 
 	results := []Object{}
@@ -290,7 +294,7 @@ func query(receiver Object, sourceLine int, t *Thread, args []Object) Object {
 		err = rows.MapScan(row)
 
 		if err != nil {
-			return t.VM().InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			return queryError(t.VM(), ctx, sourceLine, err)
 		}
 
 		data := map[string]Object{}
@@ -323,3 +327,27 @@ func getDBConn(t *vm.Thread, receiver Object) (*sqlx.DB, error) {
 
 	return conn, nil
 }
+
+// contextFromArgs takes out the Context passed as the last argument, if any,
+// so a long-running query can be cancelled:
+//
+// ```ruby
+// db.query("SELECT * FROM users WHERE age = $1", 21, Context.with_timeout(2))
+// ```
+func contextFromArgs(args []Object) (context.Context, []Object) {
+	if len(args) > 1 {
+		if ctx, ok := args[len(args)-1].Value().(context.Context); ok {
+			return ctx, args[:len(args)-1]
+		}
+	}
+
+	return context.Background(), args
+}
+
+func queryError(v *VM, ctx context.Context, sourceLine int, err error) Object {
+	if ctx.Err() != nil {
+		return v.InitErrorObject(errors.ContextError, sourceLine, ctx.Err().Error())
+	}
+
+	return v.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+}
@@ -17,6 +17,9 @@ for d in $(go list ./...); do
           rm profile.out
         fi
 
+        # Objects shared between threads (see thread_safe_test.go) must pass race detection
+        go test -race $d -run ThreadSafe
+
         # TODO: Add -race flag back when ready
         # Then we test other tests with race detection
         go test -coverprofile=profile.out -covermode=atomic $d
@@ -0,0 +1,787 @@
+package vm
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Archive creates, lists and extracts archive files with Go's `archive/tar` and `archive/zip` packages.
+// `require "archive/tar"` loads `Archive::Tar`, and `require "archive/zip"` loads `Archive::Zip`.
+//
+// ```ruby
+// require "archive/tar"
+//
+// Archive::Tar.create("logs.tar.gz", ["logs", "app.conf"], { gzip: true }) # => ["logs/", "logs/app.log", "app.conf"]
+// Archive::Tar.list("logs.tar.gz")                                          # => ["logs/", "logs/app.log", "app.conf"]
+// Archive::Tar.extract("logs.tar.gz", "/tmp/restore")                      # => ["/tmp/restore/logs", ...]
+// ```
+//
+// Directories are added with their contents. The entries are named after the given paths without the leading "/"
+// and "../", so they're extracted inside of the destination. Extracting an entry outside of the destination
+// raises `Archive::Error`.
+//
+// Tar archives keep symbolic links, and gzip compressed tar archives are detected when they're listed or extracted.
+// Zip archives only keep directories and regular files, which are compressed with deflate.
+//
+// - `Archive.new`, `Archive::Tar.new` and `Archive::Zip.new` are not supported.
+
+// archiveEntry is a file or a directory to add to an archive
+type archiveEntry struct {
+	name string
+	path string
+	info os.FileInfo
+}
+
+// Class methods --------------------------------------------------------
+var builtinArchiveClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Class methods of Tar ---------------------------------------------------
+var builtinArchiveTarClassMethods = []*BuiltinMethodObject{
+	{
+		// Creates the tar archive of the paths, and returns the names of the entries.
+		// The archive is compressed with gzip with the `gzip` option.
+		//
+		// ```ruby
+		// Archive::Tar.create("bundle.tar", ["a.log", "b.log"]) # => ["a.log", "b.log"]
+		// ```
+		//
+		// @param archive [String], paths [Array], options [Hash]
+		// @return [Array]
+		Name: "create",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 2 || len(args) > 3 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 2, 3, len(args))
+			}
+
+			useGzip := false
+
+			if len(args) == 3 {
+				options, ok := args[2].(*HashObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 3, classes.HashClass, args[2].Class().Name)
+				}
+
+				for _, key := range options.sortedKeys() {
+					if key != "gzip" {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+					}
+
+					b, ok := options.Pairs[key].(*BooleanObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.BooleanClass, options.Pairs[key].Class().Name)
+					}
+
+					useGzip = b.value
+				}
+			}
+
+			archivePath, entries, err := archiveEntriesOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			f, e := os.Create(archivePath)
+
+			if e != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+			}
+
+			defer f.Close()
+
+			var w io.Writer = f
+			var gw *gzip.Writer
+
+			if useGzip {
+				gw = gzip.NewWriter(f)
+				w = gw
+			}
+
+			tw := tar.NewWriter(w)
+			names := []Object{}
+
+			for _, entry := range entries {
+				if e := writeTarEntry(tw, entry); e != nil {
+					return t.vm.InitErrorObject(errors.ArchiveError, sourceLine, e.Error())
+				}
+
+				names = append(names, t.vm.InitStringObject(entry.name))
+			}
+
+			if e := tw.Close(); e != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+			}
+
+			if gw != nil {
+				if e := gw.Close(); e != nil {
+					return t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+				}
+			}
+
+			return t.vm.InitArrayObject(names)
+
+		},
+	},
+	{
+		// Extracts the tar archive into the directory, which is created if it doesn't exist,
+		// and returns the extracted paths.
+		//
+		// ```ruby
+		// Archive::Tar.extract("bundle.tar", "out") # => ["out/a.log", "out/b.log"]
+		// ```
+		//
+		// @param archive [String], destination [String]
+		// @return [Array]
+		Name: "extract",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			dest := args[1].(*StringObject).value
+			paths := []Object{}
+
+			err := readTar(t, args[0].(*StringObject).value, sourceLine, func(h *tar.Header, r io.Reader) error {
+				p, err := archiveEntryPath(dest, h.Name)
+
+				if err != nil {
+					return err
+				}
+
+				switch h.Typeflag {
+				case tar.TypeDir:
+					err = os.MkdirAll(p, os.FileMode(h.Mode).Perm()|0700)
+				case tar.TypeReg, tar.TypeRegA:
+					err = extractArchiveFile(p, os.FileMode(h.Mode).Perm(), r)
+				case tar.TypeSymlink:
+					err = extractArchiveSymlink(dest, p, h.Linkname)
+				default:
+					return nil
+				}
+
+				if err != nil {
+					return err
+				}
+
+				paths = append(paths, t.vm.InitStringObject(p))
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitArrayObject(paths)
+
+		},
+	},
+	{
+		// Returns the names of the entries of the tar archive. The names of directories end with "/".
+		//
+		// ```ruby
+		// Archive::Tar.list("bundle.tar") # => ["logs/", "logs/a.log"]
+		// ```
+		//
+		// @param archive [String]
+		// @return [Array]
+		Name: "list",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			names := []Object{}
+
+			err := readTar(t, args[0].(*StringObject).value, sourceLine, func(h *tar.Header, r io.Reader) error {
+				names = append(names, t.vm.InitStringObject(h.Name))
+				return nil
+			})
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitArrayObject(names)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Class methods of Zip ---------------------------------------------------
+var builtinArchiveZipClassMethods = []*BuiltinMethodObject{
+	{
+		// Creates the zip archive of the paths, and returns the names of the entries.
+		//
+		// ```ruby
+		// Archive::Zip.create("bundle.zip", ["logs"]) # => ["logs/", "logs/a.log"]
+		// ```
+		//
+		// @param archive [String], paths [Array]
+		// @return [Array]
+		Name: "create",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			archivePath, entries, err := archiveEntriesOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			f, e := os.Create(archivePath)
+
+			if e != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+			}
+
+			defer f.Close()
+
+			zw := zip.NewWriter(f)
+			names := []Object{}
+
+			for _, entry := range entries {
+				if !entry.info.IsDir() && !entry.info.Mode().IsRegular() {
+					continue
+				}
+
+				if e := writeZipEntry(zw, entry); e != nil {
+					return t.vm.InitErrorObject(errors.ArchiveError, sourceLine, e.Error())
+				}
+
+				names = append(names, t.vm.InitStringObject(entry.name))
+			}
+
+			if e := zw.Close(); e != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+			}
+
+			return t.vm.InitArrayObject(names)
+
+		},
+	},
+	{
+		// Extracts the zip archive into the directory, which is created if it doesn't exist,
+		// and returns the extracted paths.
+		//
+		// ```ruby
+		// Archive::Zip.extract("bundle.zip", "out") # => ["out/logs", "out/logs/a.log"]
+		// ```
+		//
+		// @param archive [String], destination [String]
+		// @return [Array]
+		Name: "extract",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			zr, err := openZip(t, args[0].(*StringObject).value, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			defer zr.Close()
+
+			dest := args[1].(*StringObject).value
+			paths := []Object{}
+
+			for _, zf := range zr.File {
+				p, e := archiveEntryPath(dest, zf.Name)
+
+				if e == nil {
+					if zf.FileInfo().IsDir() {
+						e = os.MkdirAll(p, zf.Mode().Perm()|0700)
+					} else if zf.Mode().IsRegular() {
+						e = extractZipFile(p, zf)
+					} else {
+						continue
+					}
+				}
+
+				if e != nil {
+					return t.vm.InitErrorObject(errors.ArchiveError, sourceLine, e.Error())
+				}
+
+				paths = append(paths, t.vm.InitStringObject(p))
+			}
+
+			return t.vm.InitArrayObject(paths)
+
+		},
+	},
+	{
+		// Returns the names of the entries of the zip archive. The names of directories end with "/".
+		//
+		// ```ruby
+		// Archive::Zip.list("bundle.zip") # => ["logs/", "logs/a.log"]
+		// ```
+		//
+		// @param archive [String]
+		// @return [Array]
+		Name: "list",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			zr, err := openZip(t, args[0].(*StringObject).value, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			defer zr.Close()
+
+			names := []Object{}
+
+			for _, zf := range zr.File {
+				names = append(names, t.vm.InitStringObject(zf.Name))
+			}
+
+			return t.vm.InitArrayObject(names)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initTarClass(vm *VM) {
+	initArchiveFormatClass(vm, "Tar", builtinArchiveTarClassMethods)
+}
+
+func initZipClass(vm *VM) {
+	initArchiveFormatClass(vm, "Zip", builtinArchiveZipClassMethods)
+}
+
+// initArchiveFormatClass initializes the class of the format under `Archive`
+func initArchiveFormatClass(vm *VM, name string, methods []*BuiltinMethodObject) {
+	archive := vm.loadConstant("Archive", true)
+	archive.setBuiltinMethods(builtinArchiveClassMethods, true)
+
+	if ptr, _ := archive.getConstant("Error"); ptr == nil {
+		archive.setClassConstant(vm.initializeClass("Error"))
+	}
+
+	class := vm.initializeClass(name)
+	class.setBuiltinMethods(methods, true)
+	archive.setClassConstant(class)
+}
+
+// Other helper functions -----------------------------------------------
+
+// archiveEntriesOf returns the archive path and the entries of the paths of the arguments, including the contents of directories
+func archiveEntriesOf(t *Thread, args []Object, sourceLine int) (string, []archiveEntry, *Error) {
+	archivePath, ok := args[0].(*StringObject)
+
+	if !ok {
+		return "", nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.StringClass, args[0].Class().Name)
+	}
+
+	paths, ok := args[1].(*ArrayObject)
+
+	if !ok {
+		return "", nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.ArrayClass, args[1].Class().Name)
+	}
+
+	entries := []archiveEntry{}
+
+	for _, elem := range paths.Elements {
+		p, ok := elem.(*StringObject)
+
+		if !ok {
+			return "", nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, elem.Class().Name)
+		}
+
+		name := archiveEntryName(p.value)
+
+		err := filepath.Walk(p.value, func(walkedPath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(p.value, walkedPath)
+
+			if err != nil {
+				return err
+			}
+
+			entryName := path.Join(name, filepath.ToSlash(rel))
+
+			if info.IsDir() {
+				entryName += "/"
+			}
+
+			entries = append(entries, archiveEntry{name: entryName, path: walkedPath, info: info})
+			return nil
+		})
+
+		if err != nil {
+			return "", nil, t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+		}
+	}
+
+	return archivePath.value, entries, nil
+}
+
+// archiveEntryName returns the name of the entry of the path, without the leading "/" and "../" like tar does
+func archiveEntryName(p string) string {
+	name := path.Clean(filepath.ToSlash(p))
+
+	for {
+		switch {
+		case strings.HasPrefix(name, "/"):
+			name = name[1:]
+		case strings.HasPrefix(name, "../"):
+			name = name[3:]
+		case name == "..":
+			return "."
+		default:
+			return name
+		}
+	}
+}
+
+func writeTarEntry(tw *tar.Writer, entry archiveEntry) error {
+	link := ""
+
+	if entry.info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(entry.path)
+
+		if err != nil {
+			return err
+		}
+
+		link = target
+	}
+
+	h, err := tar.FileInfoHeader(entry.info, link)
+
+	if err != nil {
+		return err
+	}
+
+	h.Name = entry.name
+
+	if err := tw.WriteHeader(h); err != nil {
+		return err
+	}
+
+	if !entry.info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(entry.path)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func writeZipEntry(zw *zip.Writer, entry archiveEntry) error {
+	h, err := zip.FileInfoHeader(entry.info)
+
+	if err != nil {
+		return err
+	}
+
+	h.Name = entry.name
+
+	if !entry.info.IsDir() {
+		h.Method = zip.Deflate
+	}
+
+	w, err := zw.CreateHeader(h)
+
+	if err != nil || entry.info.IsDir() {
+		return err
+	}
+
+	f, err := os.Open(entry.path)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// readTar calls fn with every entry of the tar archive, which can be compressed with gzip
+func readTar(t *Thread, archivePath string, sourceLine int, fn func(h *tar.Header, r io.Reader) error) *Error {
+	f, err := os.Open(archivePath)
+
+	if err != nil {
+		return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+
+	// Gzip data starts with the magic number 1f 8b
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gr, err := gzip.NewReader(br)
+
+		if err != nil {
+			return t.vm.InitErrorObject(errors.ArchiveError, sourceLine, err.Error())
+		}
+
+		defer gr.Close()
+		r = gr
+	}
+
+	tr := tar.NewReader(r)
+
+	for {
+		h, err := tr.Next()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err == nil {
+			err = fn(h, tr)
+		}
+
+		if err != nil {
+			return t.vm.InitErrorObject(errors.ArchiveError, sourceLine, err.Error())
+		}
+	}
+}
+
+func openZip(t *Thread, archivePath string, sourceLine int) (*zip.ReadCloser, *Error) {
+	zr, err := zip.OpenReader(archivePath)
+
+	if os.IsNotExist(err) {
+		return nil, t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	if err != nil {
+		return nil, t.vm.InitErrorObject(errors.ArchiveError, sourceLine, err.Error())
+	}
+
+	return zr, nil
+}
+
+// archiveEntryPath returns the path to extract the entry to, which must be inside of the destination
+// even after following the links extracted before
+func archiveEntryPath(dest, name string) (string, error) {
+	clean := path.Clean(name)
+
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+
+	p := filepath.Join(dest, filepath.FromSlash(clean))
+	realDest, err := archiveRealPath(dest)
+
+	if err != nil {
+		return "", err
+	}
+
+	parent, err := archiveRealPath(filepath.Dir(p))
+
+	if err != nil {
+		return "", err
+	}
+
+	if !archiveContains(realDest, parent) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+
+	return p, nil
+}
+
+// archiveRealPath returns the absolute path of p with the links in it resolved
+func archiveRealPath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+
+	if err != nil {
+		return "", err
+	}
+
+	return resolveArchiveLinks(string(filepath.Separator), abs)
+}
+
+// resolveArchiveLinks follows the elements of rel from the real directory base one by one, resolving the links
+// like the OS does. The elements which don't exist yet are joined as they are.
+func resolveArchiveLinks(base, rel string) (string, error) {
+	p := base
+
+	for _, elem := range strings.Split(filepath.ToSlash(rel), "/") {
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			p = filepath.Dir(p)
+			continue
+		}
+
+		p = filepath.Join(p, elem)
+
+		if _, err := os.Lstat(p); err != nil {
+			continue
+		}
+
+		real, err := filepath.EvalSymlinks(p)
+
+		if err != nil {
+			return "", err
+		}
+
+		p = real
+	}
+
+	return p, nil
+}
+
+// archiveContains reports whether the real path p is the destination or inside of it
+func archiveContains(realDest, p string) bool {
+	rel, err := filepath.Rel(realDest, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func extractArchiveFile(p string, perm os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// Replaces the link extracted before instead of writing through it
+	if info, err := os.Lstat(p); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+
+	if e := f.Close(); err == nil {
+		err = e
+	}
+
+	return err
+}
+
+func extractZipFile(p string, zf *zip.File) error {
+	r, err := zf.Open()
+
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	return extractArchiveFile(p, zf.Mode().Perm(), r)
+}
+
+// extractArchiveSymlink creates the symbolic link, whose target must be inside of the destination
+func extractArchiveSymlink(dest, p, target string) error {
+	realDest, err := archiveRealPath(dest)
+
+	if err != nil {
+		return err
+	}
+
+	base, err := archiveRealPath(filepath.Dir(p))
+
+	if err != nil {
+		return err
+	}
+
+	if filepath.IsAbs(target) {
+		base = string(filepath.Separator)
+	}
+
+	resolved, err := resolveArchiveLinks(base, target)
+
+	if err != nil || !archiveContains(realDest, resolved) {
+		return fmt.Errorf("illegal link in archive: %s", target)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	os.Remove(p)
+	return os.Symlink(target, p)
+}
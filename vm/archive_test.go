@@ -0,0 +1,219 @@
+package vm
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"os"
+	"testing"
+)
+
+// setupArchiveFiles creates the files to archive under /tmp/goby/files
+func setupArchiveFiles(t *testing.T) {
+	t.Helper()
+	setup()
+
+	for path, content := range map[string]string{
+		"/tmp/goby/files/a.log":     "a",
+		"/tmp/goby/files/sub/b.log": "b",
+	} {
+		if err := os.MkdirAll("/tmp/goby/files/sub", 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := os.Create(path)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f.WriteString(content)
+		f.Close()
+	}
+}
+
+func TestArchiveTarMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "archive/tar"
+		Archive::Tar.create("/tmp/goby/files.tar", ["/tmp/goby/files"])
+		`, []interface{}{"tmp/goby/files/", "tmp/goby/files/a.log", "tmp/goby/files/sub/", "tmp/goby/files/sub/b.log"}},
+		{`
+		require "archive/tar"
+		Archive::Tar.create("/tmp/goby/files.tar", ["/tmp/goby/files/a.log"])
+		Archive::Tar.list("/tmp/goby/files.tar")
+		`, []interface{}{"tmp/goby/files/a.log"}},
+		{`
+		require "archive/tar"
+		Archive::Tar.create("/tmp/goby/files.tar.gz", ["/tmp/goby/files/sub"], { gzip: true })
+		Archive::Tar.extract("/tmp/goby/files.tar.gz", "/tmp/goby/out")
+		`, []interface{}{"/tmp/goby/out/tmp/goby/files/sub", "/tmp/goby/out/tmp/goby/files/sub/b.log"}},
+		{`
+		require "archive/tar"
+		Archive::Tar.create("/tmp/goby/files.tar", ["/tmp/goby/files"])
+		Archive::Tar.extract("/tmp/goby/files.tar", "/tmp/goby/out")
+		File.new("/tmp/goby/out/tmp/goby/files/sub/b.log").read
+		`, "b"},
+	}
+
+	for i, tt := range tests {
+		setupArchiveFiles(t)
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+
+	teardown()
+}
+
+func TestArchiveZipMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "archive/zip"
+		Archive::Zip.create("/tmp/goby/files.zip", ["/tmp/goby/files"])
+		`, []interface{}{"tmp/goby/files/", "tmp/goby/files/a.log", "tmp/goby/files/sub/", "tmp/goby/files/sub/b.log"}},
+		{`
+		require "archive/zip"
+		Archive::Zip.create("/tmp/goby/files.zip", ["/tmp/goby/files/sub/b.log", "/tmp/goby/files/a.log"])
+		Archive::Zip.list("/tmp/goby/files.zip")
+		`, []interface{}{"tmp/goby/files/sub/b.log", "tmp/goby/files/a.log"}},
+		{`
+		require "archive/zip"
+		Archive::Zip.create("/tmp/goby/files.zip", ["/tmp/goby/files/sub"])
+		Archive::Zip.extract("/tmp/goby/files.zip", "/tmp/goby/out")
+		`, []interface{}{"/tmp/goby/out/tmp/goby/files/sub", "/tmp/goby/out/tmp/goby/files/sub/b.log"}},
+		{`
+		require "archive/zip"
+		Archive::Zip.create("/tmp/goby/files.zip", ["/tmp/goby/files"])
+		Archive::Zip.extract("/tmp/goby/files.zip", "/tmp/goby/out")
+		File.new("/tmp/goby/out/tmp/goby/files/a.log").read
+		`, "a"},
+	}
+
+	for i, tt := range tests {
+		setupArchiveFiles(t)
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+
+	teardown()
+}
+
+func TestArchiveMethodsFail(t *testing.T) {
+	setupArchiveFiles(t)
+	defer teardown()
+
+	// Archives with entries outside of the destination
+	f, err := os.Create("/tmp/goby/evil.tar")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tw := tar.NewWriter(f)
+	tw.WriteHeader(&tar.Header{Name: "../evil.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	f.Close()
+
+	f, err = os.Create("/tmp/goby/evil_link.tar")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tw = tar.NewWriter(f)
+	tw.WriteHeader(&tar.Header{Name: "link", Linkname: "../../etc/passwd", Mode: 0777, Typeflag: tar.TypeSymlink})
+	tw.Close()
+	f.Close()
+
+	// Links which lead outside of the destination only when followed through the links extracted before
+	for _, dir := range []string{"/tmp/goby/chain", "/tmp/goby/later_link"} {
+		os.RemoveAll(dir)
+		defer os.RemoveAll(dir)
+	}
+
+	f, err = os.Create("/tmp/goby/evil_chain.tar")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tw = tar.NewWriter(f)
+	tw.WriteHeader(&tar.Header{Name: "a", Linkname: ".", Mode: 0777, Typeflag: tar.TypeSymlink})
+	tw.WriteHeader(&tar.Header{Name: "a/b", Linkname: "..", Mode: 0777, Typeflag: tar.TypeSymlink})
+	tw.WriteHeader(&tar.Header{Name: "b/evil.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	f.Close()
+
+	f, err = os.Create("/tmp/goby/evil_later_link.tar")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tw = tar.NewWriter(f)
+	tw.WriteHeader(&tar.Header{Name: "c", Linkname: "d/..", Mode: 0777, Typeflag: tar.TypeSymlink})
+	tw.WriteHeader(&tar.Header{Name: "d", Linkname: ".", Mode: 0777, Typeflag: tar.TypeSymlink})
+	tw.WriteHeader(&tar.Header{Name: "c/evil.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+	f.Close()
+
+	f, err = os.Create("/tmp/goby/evil.zip")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("../evil.txt")
+	w.Write([]byte("x"))
+	zw.Close()
+	f.Close()
+
+	testsFail := []errorTestCase{
+		{`require "archive/tar";Archive.new`, "NoMethodError: Undefined Method 'new' for Archive", 1},
+		{`require "archive/tar";Archive::Tar.new`, "NoMethodError: Undefined Method 'new' for Tar", 1},
+		{`require "archive/tar";Archive::Tar.create("/tmp/goby/a.tar")`, "ArgumentError: Expect 2 to 3 argument(s). got: 1", 1},
+		{`require "archive/tar";Archive::Tar.create(1, [])`, "TypeError: Expect argument #1 to be String. got: Integer", 1},
+		{`require "archive/tar";Archive::Tar.create("/tmp/goby/a.tar", "a")`, "TypeError: Expect argument #2 to be Array. got: String", 1},
+		{`require "archive/tar";Archive::Tar.create("/tmp/goby/a.tar", [1])`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "archive/tar";Archive::Tar.create("/tmp/goby/a.tar", [], { zip: true })`, "ArgumentError: Unknown option - zip", 1},
+		{`require "archive/tar";Archive::Tar.create("/tmp/goby/a.tar", [], { gzip: 1 })`, "TypeError: Expect argument to be Boolean. got: Integer", 1},
+		{`require "archive/tar";Archive::Tar.create("/tmp/goby/a.tar", ["/tmp/goby/not_existing"])`, "IOError: lstat /tmp/goby/not_existing: no such file or directory", 1},
+		{`require "archive/tar";Archive::Tar.list("/tmp/goby/not_existing.tar")`, "IOError: open /tmp/goby/not_existing.tar: no such file or directory", 1},
+		{`require "archive/tar";Archive::Tar.list("/tmp/goby/files/a.log")`, "Archive::Error: unexpected EOF", 1},
+		{`require "archive/tar";Archive::Tar.extract("/tmp/goby/evil.tar", "/tmp/goby/out")`, "Archive::Error: illegal path in archive: ../evil.txt", 1},
+		{`require "archive/tar";Archive::Tar.extract("/tmp/goby/evil_link.tar", "/tmp/goby/out")`, "Archive::Error: illegal link in archive: ../../etc/passwd", 1},
+		{`require "archive/tar";Archive::Tar.extract("/tmp/goby/evil_chain.tar", "/tmp/goby/chain")`, "Archive::Error: illegal link in archive: ..", 1},
+		{`require "archive/tar";Archive::Tar.extract("/tmp/goby/evil_later_link.tar", "/tmp/goby/later_link")`, "Archive::Error: illegal path in archive: c/evil.txt", 1},
+		{`require "archive/zip";Archive::Zip.create("/tmp/goby/a.zip", [], {})`, "ArgumentError: Expect 2 argument(s). got: 3", 1},
+		{`require "archive/zip";Archive::Zip.list("/tmp/goby/not_existing.zip")`, "IOError: open /tmp/goby/not_existing.zip: no such file or directory", 1},
+		{`require "archive/zip";Archive::Zip.list("/tmp/goby/files/a.log")`, "Archive::Error: zip: not a valid zip file", 1},
+		{`require "archive/zip";Archive::Zip.extract("/tmp/goby/evil.zip", "/tmp/goby/out")`, "Archive::Error: illegal path in archive: ../evil.txt", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+
+	if _, err := os.Stat("/tmp/goby/evil.txt"); !os.IsNotExist(err) {
+		t.Fatal("Expect the entry outside of the destination not to be extracted")
+	}
+}
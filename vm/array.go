@@ -36,11 +36,17 @@ var builtinArrayClassMethods = []*BuiltinMethodObject{
 					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongArgumentTypeFormat, "Integer", args[0].Class().Name)
 				}
 
-				if n.value < 0 {
+				size, err := n.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				if size < 0 {
 					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Negative Array Size")
 				}
 
-				elems := make([]Object, n.value)
+				elems := make([]Object, size)
 
 				if blockFrame != nil && !blockIsEmpty(blockFrame) {
 					for i := range elems {
@@ -55,7 +61,7 @@ var builtinArrayClassMethods = []*BuiltinMethodObject{
 						elem = NULL
 					}
 
-					for i := 0; i < n.value; i++ {
+					for i := 0; i < size; i++ {
 						elems[i] = elem
 					}
 				}
@@ -150,7 +156,13 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			return receiver.(*ArrayObject).concatenateCopies(t, args[0].Value().(int))
+			copies, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return receiver.(*ArrayObject).concatenateCopies(t, copies)
 		},
 	},
 	{
@@ -270,7 +282,12 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			indexValue := args[0].Value().(int)
+			indexValue, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
 			arr := receiver.(*ArrayObject)
 
 			// <Three Argument Case>
@@ -293,10 +310,15 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
 				}
 
-				countValue := count.value
+				countValue, err := count.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
 				// Second argument must be a positive value
 				if countValue < 0 {
-					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeSecondValue, count.value)
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeSecondValue, countValue)
 				}
 
 				a := args[2]
@@ -607,8 +629,14 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
+			index, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
 			arr := receiver.(*ArrayObject)
-			normalizedIndex := arr.normalizeIndex(args[0].Value().(int))
+			normalizedIndex := arr.normalizeIndex(index)
 
 			if normalizedIndex == -1 {
 				return NULL
@@ -849,7 +877,11 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			value := args[0].Value().(int)
+			value, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
 
 			if value < 1 {
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, value)
@@ -1031,7 +1063,11 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			value := args[0].Value().(int)
+			value, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
 
 
 			if value < 1 {
@@ -1348,7 +1384,11 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 					return typeErr
 				}
 
-				rotate = args[0].Value().(int)
+				var err *Error
+
+				if rotate, err = args[0].(*IntegerObject).intValue(t, sourceLine); err != nil {
+					return err
+				}
 			}
 
 			if rotate < 0 {
@@ -1367,6 +1407,87 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns a random element, or nil if the array is empty. With an Integer `n`, returns a new array of
+		// `n` random elements at different positions, or all the elements in random order if there are fewer.
+		// The generator can be given with the `random` option, otherwise the default generator is used.
+		//
+		// ```ruby
+		// a = [1, 2, 3, 4]
+		// a.sample                                  #=> 3
+		// a.sample(2)                               #=> [4, 1]
+		// a.sample(2, { random: Random.new(42) })   #=> always the same elements
+		// ```
+		//
+		// @param n [Integer], options [Hash]
+		// @return [Object]
+		Name: "sample",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 2, len(args))
+			}
+
+			arr := receiver.(*ArrayObject)
+			r := t.vm.random
+			n := -1
+
+			for i, arg := range args {
+				switch arg := arg.(type) {
+				case *IntegerObject:
+					if i != 0 {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.HashClass, arg.Class().Name)
+					}
+
+					value, err := arg.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					if value < 0 {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, value)
+					}
+
+					n = value
+				default:
+					if i != len(args)-1 {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.IntegerClass, arg.Class().Name)
+					}
+
+					var err *Error
+					r, err = randomOf(t, arg, sourceLine)
+
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			if n < 0 {
+				if len(arr.Elements) == 0 {
+					return NULL
+				}
+
+				return arr.Elements[r.intn(len(arr.Elements))]
+			}
+
+			elems := make([]Object, len(arr.Elements))
+			copy(elems, arr.Elements)
+
+			if n > len(elems) {
+				n = len(elems)
+			}
+
+			// Picks the elements by a partial Fisher-Yates shuffle
+			for i := 0; i < n; i++ {
+				j := i + r.intn(len(elems)-i)
+				elems[i], elems[j] = elems[j], elems[i]
+			}
+
+			return t.vm.InitArrayObject(elems[:n])
+
+		},
+	},
 	{
 		// Loops through each element with the given block literal that contains conditional expressions.
 		// Returns a new array that contains elements that have been evaluated as `true` by the block.
@@ -1438,6 +1559,44 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns a new array with the elements in random order. The method is not destructive.
+		// The generator can be given with the `random` option, otherwise the default generator is used.
+		//
+		// ```ruby
+		// a = [1, 2, 3, 4]
+		// a.shuffle                               #=> [3, 1, 4, 2]
+		// a.shuffle({ random: Random.new(42) })   #=> always in the same order
+		// ```
+		//
+		// @param options [Hash]
+		// @return [Array]
+		Name: "shuffle",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			r := t.vm.random
+
+			if len(args) == 1 {
+				var err *Error
+				r, err = randomOf(t, args[0], sourceLine)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			arr := receiver.(*ArrayObject)
+			elems := make([]Object, len(arr.Elements))
+			copy(elems, arr.Elements)
+			r.shuffle(elems)
+
+			return t.vm.InitArrayObject(elems)
+
+		},
+	},
 	{
 		// Return a sorted array
 		//
@@ -1551,14 +1710,20 @@ var builtinArrayInstanceMethods = []*BuiltinMethodObject{
 					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
 				}
 
-				if index.value >= len(arr.Elements) {
+				indexValue, err := index.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				if indexValue >= len(arr.Elements) {
 					elements[i] = NULL
-				} else if index.value < 0 && -index.value > len(arr.Elements) {
+				} else if indexValue < 0 && -indexValue > len(arr.Elements) {
 					elements[i] = NULL
-				} else if index.value < 0 {
-					elements[i] = arr.Elements[len(arr.Elements)+index.value]
+				} else if indexValue < 0 {
+					elements[i] = arr.Elements[len(arr.Elements)+indexValue]
 				} else {
-					elements[i] = arr.Elements[index.value]
+					elements[i] = arr.Elements[indexValue]
 				}
 			}
 
@@ -1653,7 +1818,13 @@ func (a *ArrayObject) dig(t *Thread, keys []Object, sourceLine int) Object {
 		return typeErr
 	}
 
-	normalizedIndex := a.normalizeIndex(keys[0].Value().(int))
+	index, err := keys[0].(*IntegerObject).intValue(t, sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	normalizedIndex := a.normalizeIndex(index)
 
 	if normalizedIndex == -1 {
 		return NULL
@@ -1688,7 +1859,12 @@ func (a *ArrayObject) index(t *Thread, args []Object, sourceLine int) Object {
 		return typeErr
 	}
 
-	index := args[0].Value().(int)
+	index, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+	if err != nil {
+		return err
+	}
+
 	arrLength := a.Len()
 
 	if index < 0 && index < -arrLength {
@@ -1703,8 +1879,15 @@ func (a *ArrayObject) index(t *Thread, args []Object, sourceLine int) Object {
 		if !ok {
 			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
 		}
-		if count.value < 0 {
-			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeSecondValue, count.value)
+
+		countValue, err := count.intValue(t, sourceLine)
+
+		if err != nil {
+			return err
+		}
+
+		if countValue < 0 {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeSecondValue, countValue)
 		}
 
 		/*
@@ -1910,6 +1910,64 @@ func TestArrayRotateMethodFail(t *testing.T) {
 	}
 }
 
+func TestArraySampleMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[].sample`, nil},
+		{`[1].sample`, 1},
+		{`[1, 2, 3].sample(0)`, []interface{}{}},
+		{`[1, 2, 3].sample(5).sort`, []interface{}{1, 2, 3}},
+		{`[1, 2, 3, 4, 5].sample(3).length`, 3},
+		{`
+		a = [1, 2, 3, 4, 5]
+		a.sample(3, { random: Random.new(42) }) == a.sample(3, { random: Random.new(42) })
+		`, true},
+		{`
+		a = [1, 2, 3, 4, 5]
+		a.sample({ random: Random.new(42) }) == a.sample({ random: Random.new(42) })
+		`, true},
+		{`
+		a = [1, 2, 3]
+		a.sample(2)
+		a
+		`, []interface{}{1, 2, 3}},
+		{`
+		n = [1, 2, 3].sample
+		n >= 1 && n <= 3
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArraySampleMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2].sample(-1)`, "ArgumentError: Expect argument to be positive value. got: -1", 1},
+		{`[1, 2].sample(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`[1, 2].sample(1, 2)`, "TypeError: Expect argument #2 to be Hash. got: Integer", 1},
+		{`[1, 2].sample("a", 2)`, "TypeError: Expect argument #1 to be Integer. got: String", 1},
+		{`[1, 2].sample({ random: 1 })`, "TypeError: Expect argument to be Random. got: Integer", 1},
+		{`[1, 2].sample({ rng: Random.new })`, "ArgumentError: Unknown option - rng", 1},
+		{`[1, 2].sample(1, {}, 2)`, "ArgumentError: Expect 2 or less argument(s). got: 3", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArraySelectMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -2025,6 +2083,54 @@ func TestArrayShiftMethodFail(t *testing.T) {
 	}
 }
 
+func TestArrayShuffleMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[].shuffle`, []interface{}{}},
+		{`[1, 2, 3, 4, 5].shuffle.sort`, []interface{}{1, 2, 3, 4, 5}},
+		{`
+		a = [1, 2, 3, 4, 5]
+		a.shuffle
+		a
+		`, []interface{}{1, 2, 3, 4, 5}},
+		{`
+		a = [1, 2, 3, 4, 5, 6, 7, 8]
+		a.shuffle({ random: Random.new(42) }) == a.shuffle({ random: Random.new(42) })
+		`, true},
+		{`
+		r = Random.new(42)
+		a = [1, 2, 3, 4, 5, 6, 7, 8]
+		a.shuffle({ random: r }) == a.shuffle({ random: r })
+		`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayShuffleMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2].shuffle(1)`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`[1, 2].shuffle({ random: "a" })`, "TypeError: Expect argument to be Random. got: String", 1},
+		{`[1, 2].shuffle({}, {})`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArraySortMethod(t *testing.T) {
 	tests := []struct {
 		input    string
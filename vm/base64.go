@@ -0,0 +1,260 @@
+package vm
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Base64 encodes the bytes of Strings into base64 and decodes them back, with Go's `encoding/base64` package.
+//
+// ```ruby
+// require "base64"
+//
+// Base64.strict_encode64("Hello, Goby!")     # => "SGVsbG8sIEdvYnkh"
+// Base64.strict_decode64("SGVsbG8sIEdvYnkh") # => "Hello, Goby!"
+// Base64.urlsafe_encode64("?>?")             # => "Pz4_"
+// ```
+//
+// - `Base64.new` is not supported.
+
+// base64LineLength is the length of the lines encoded by `encode64`
+const base64LineLength = 60
+
+// Class methods --------------------------------------------------------
+var builtinBase64ClassMethods = []*BuiltinMethodObject{
+	{
+		// Decodes the base64 String into a binary String. Characters outside of the base64 alphabet, like line breaks, are ignored.
+		//
+		// ```ruby
+		// Base64.decode64("SGVsbG8s\nIEdvYnkh\n") # => "Hello, Goby!"
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "decode64",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s, err := base64Arg(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			s = strings.Map(func(r rune) rune {
+				if r == '+' || r == '/' || r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' {
+					return r
+				}
+
+				return -1
+			}, s)
+
+			return decodeBase64(t, base64.RawStdEncoding, s, sourceLine)
+
+		},
+	},
+	{
+		// Encodes the String into base64 lines of 60 characters, each of which ends with a line break.
+		//
+		// ```ruby
+		// Base64.encode64("Hello, Goby!") # => "SGVsbG8sIEdvYnkh\n"
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "encode64",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s, err := base64Arg(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			encoded := base64.StdEncoding.EncodeToString([]byte(s))
+			var b strings.Builder
+
+			for len(encoded) > 0 {
+				n := base64LineLength
+
+				if len(encoded) < n {
+					n = len(encoded)
+				}
+
+				b.WriteString(encoded[:n] + "\n")
+				encoded = encoded[n:]
+			}
+
+			return t.vm.InitStringObject(b.String())
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Decodes the base64 String into a binary String. It must be padded and can't contain characters outside of the alphabet.
+		//
+		// ```ruby
+		// Base64.strict_decode64("R29ieQ==") # => "Goby"
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "strict_decode64",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s, err := base64Arg(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidBase64, base64.CorruptInputError(i).Error())
+			}
+
+			return decodeBase64(t, base64.StdEncoding.Strict(), s, sourceLine)
+
+		},
+	},
+	{
+		// Encodes the String into base64 without line breaks.
+		//
+		// ```ruby
+		// Base64.strict_encode64("Goby") # => "R29ieQ=="
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "strict_encode64",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s, err := base64Arg(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(base64.StdEncoding.EncodeToString([]byte(s)))
+
+		},
+	},
+	{
+		// Decodes the URL-safe base64 String into a binary String. It uses "-" and "_" instead of "+" and "/". The padding is optional.
+		//
+		// ```ruby
+		// Base64.urlsafe_decode64("Pz4_") # => "?>?"
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "urlsafe_decode64",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s, err := base64Arg(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if len(s)%4 == 0 {
+				return decodeBase64(t, base64.URLEncoding, s, sourceLine)
+			}
+
+			return decodeBase64(t, base64.RawURLEncoding, s, sourceLine)
+
+		},
+	},
+	{
+		// Encodes the String into URL-safe base64, which uses "-" and "_" instead of "+" and "/".
+		// The padding is omitted with `{ padding: false }`.
+		//
+		// ```ruby
+		// Base64.urlsafe_encode64("Goby?")                     # => "R29ieT8="
+		// Base64.urlsafe_encode64("Goby?", { padding: false })  # => "R29ieT8"
+		// ```
+		//
+		// @param string [String], options [Hash]
+		// @return [String]
+		Name: "urlsafe_encode64",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			s, err := base64Arg(t, args[:1], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			encoding := base64.URLEncoding
+
+			if len(args) == 2 {
+				options, ok := args[1].(*HashObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.HashClass, args[1].Class().Name)
+				}
+
+				for _, key := range options.sortedKeys() {
+					if key != "padding" {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+					}
+
+					padding, ok := options.Pairs[key].(*BooleanObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.BooleanClass, options.Pairs[key].Class().Name)
+					}
+
+					if !padding.value {
+						encoding = base64.RawURLEncoding
+					}
+				}
+			}
+
+			return t.vm.InitStringObject(encoding.EncodeToString([]byte(s)))
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initBase64Class(vm *VM) {
+	class := vm.initializeClass("Base64")
+	class.setBuiltinMethods(builtinBase64ClassMethods, true)
+	vm.objectClass.setClassConstant(class)
+}
+
+// Other helper functions -----------------------------------------------
+
+// base64Arg returns the only String argument
+func base64Arg(t *Thread, args []Object, sourceLine int) (string, *Error) {
+	if len(args) != 1 {
+		return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+	if typeErr != nil {
+		return "", typeErr
+	}
+
+	return args[0].(*StringObject).value, nil
+}
+
+func decodeBase64(t *Thread, encoding *base64.Encoding, s string, sourceLine int) Object {
+	decoded, err := encoding.DecodeString(s)
+
+	if err != nil {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidBase64, err.Error())
+	}
+
+	return t.vm.initBinaryStringObject(string(decoded))
+}
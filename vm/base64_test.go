@@ -0,0 +1,57 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestBase64Methods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`require "base64";Base64.strict_encode64("Hello, Goby!")`, "SGVsbG8sIEdvYnkh"},
+		{`require "base64";Base64.strict_encode64("Goby")`, "R29ieQ=="},
+		{`require "base64";Base64.strict_decode64("R29ieQ==")`, "Goby"},
+		{`require "base64";Base64.encode64("Goby")`, "R29ieQ==\n"},
+		{`require "base64";Base64.encode64("")`, ""},
+		{`require "base64";Base64.encode64("a" * 46)`, "YWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFh\nYQ==\n"},
+		{`require "base64";Base64.decode64("SGVsbG8s\nIEdvYnkh\n")`, "Hello, Goby!"},
+		{`require "base64";Base64.decode64("R29ieQ==")`, "Goby"},
+		{`require "base64";Base64.urlsafe_encode64("?>?")`, "Pz4_"},
+		{`require "base64";Base64.urlsafe_encode64("Goby?")`, "R29ieT8="},
+		{`require "base64";Base64.urlsafe_encode64("Goby?", { padding: false })`, "R29ieT8"},
+		{`require "base64";Base64.urlsafe_decode64("Pz4_")`, "?>?"},
+		{`require "base64";Base64.urlsafe_decode64("R29ieT8=")`, "Goby?"},
+		{`require "base64";Base64.urlsafe_decode64("R29ieT8")`, "Goby?"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBase64MethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "base64";Base64.new`, "NoMethodError: Undefined Method 'new' for Base64", 1},
+		{`require "base64";Base64.strict_encode64`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`require "base64";Base64.strict_encode64(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "base64";Base64.strict_decode64("R29ieQ")`, "ArgumentError: Invalid base64 - illegal base64 data at input byte 4", 1},
+		{`require "base64";Base64.strict_decode64("R29i\nieQ==")`, "ArgumentError: Invalid base64 - illegal base64 data at input byte 4", 1},
+		{`require "base64";Base64.urlsafe_decode64("Pz4/")`, "ArgumentError: Invalid base64 - illegal base64 data at input byte 3", 1},
+		{`require "base64";Base64.urlsafe_encode64("a", 1)`, "TypeError: Expect argument #2 to be Hash. got: Integer", 1},
+		{`require "base64";Base64.urlsafe_encode64("a", { padding: nil })`, "TypeError: Expect argument to be Boolean. got: Null", 1},
+		{`require "base64";Base64.urlsafe_encode64("a", { pad: false })`, "ArgumentError: Unknown option - pad", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
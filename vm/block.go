@@ -157,9 +157,9 @@ func (bo *BlockObject) Inspect() string {
 	return bo.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (bo *BlockObject) ToJSON(t *Thread) string {
-	return bo.ToString()
+	return jsonString(bo.ToString())
 }
 
 // copy returns the duplicate of the Array object
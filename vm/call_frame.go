@@ -76,6 +76,12 @@ func (n *normalCallFrame) stopExecution() {
 	n.pc = n.instructionsCount()
 }
 
+// definedSet returns the instruction set the current instruction defines or passes as a block.
+// It's resolved when the instructions are translated, so the lookup doesn't mutate any shared state.
+func (n *normalCallFrame) definedSet() *instructionSet {
+	return n.instructionSet.definedSets[n.pc-1]
+}
+
 func (b *baseFrame) Self() Object {
 	return b.self
 }
@@ -176,14 +182,14 @@ func (b *baseFrame) storeConstant(constName string, constant interface{}) *Point
 
 	switch scope := b.self.(type) {
 	case *RClass:
-		scope.constants[constName] = ptr
+		scope.setConstant(constName, ptr)
 
 		if class, ok := ptr.Target.(*RClass); ok {
 			class.scope = scope
 		}
 	default:
 		c := b.self.Class()
-		c.constants[constName] = ptr
+		c.setConstant(constName, ptr)
 	}
 
 	return ptr
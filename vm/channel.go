@@ -210,13 +210,19 @@ var builtinChannelInstanceMethods = []*BuiltinMethodObject{
 		//
 		// If you call `receive` against the closed channel, an error is returned.
 		//
-		// It takes no arguments.
+		// It takes an optional `Context`; a `ContextError` is raised if the context is done before receiving anything.
 		//
+		// ```ruby
+		// c = Channel.new
+		// c.receive(Context.with_timeout(1)) # => ContextError: context deadline exceeded
+		// ```
+		//
+		// @param context [Context]
 		// @return [Object]
 		Name: "receive",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			if len(args) != 0 {
-				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
 			}
 
 			c := receiver.(*ChannelObject)
@@ -225,9 +231,24 @@ var builtinChannelInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ChannelCloseError, sourceLine, errors.ChannelIsClosed)
 			}
 
-			num := <-c.Chan
+			if len(args) == 0 {
+				num := <-c.Chan
 
-			return t.vm.channelObjectMap.retrieveObj(num)
+				return t.vm.channelObjectMap.retrieveObj(num)
+			}
+
+			ctx, err := t.vm.contextFromArg(args[0], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			select {
+			case num := <-c.Chan:
+				return t.vm.channelObjectMap.retrieveObj(num)
+			case <-ctx.Done():
+				return t.vm.initContextError(ctx, sourceLine)
+			}
 		},
 	},
 }
@@ -260,9 +281,9 @@ func (co *ChannelObject) Inspect() string {
 	return co.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (co *ChannelObject) ToJSON(t *Thread) string {
-	return co.ToString()
+	return jsonString(co.ToString())
 }
 
 // copy returns the duplicate of the Array object
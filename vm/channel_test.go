@@ -37,7 +37,9 @@ func TestChannelCloseFail(t *testing.T) {
 
 func TestChannelReceiveFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`c = Channel.new; c.receive(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`c = Channel.new; c.receive(1, 2)`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
+		{`c = Channel.new; c.receive(1)`, "TypeError: Expect argument to be Context. got: Integer", 1},
+		{`c = Channel.new; c.receive(Context.with_timeout(0.1))`, "ContextError: context deadline exceeded", 1},
 		{`c = Channel.new; c.close; c.receive`, "ChannelCloseError: The channel is already closed.", 1},
 	}
 
@@ -1,13 +1,13 @@
 package vm
 
 import (
+	"bytes"
 	"fmt"
-	"os"
 	"path"
+	"strings"
 	"sync"
 	"time"
 
-	"math/rand"
 	"sort"
 
 	"github.com/goby-lang/goby/vm/classes"
@@ -29,6 +29,7 @@ type RClass struct {
 	isSingleton           bool
 	isModule              bool
 	constants             map[string]*Pointer
+	constantsMutex        sync.RWMutex
 	scope                 *RClass
 	inheritsMethodMissing bool
 	*BaseObj
@@ -430,16 +431,10 @@ var builtinModuleCommonClassMethods = []*BuiltinMethodObject{
 	{
 		Name: "constants",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			var constantNames []string
 			var objs []Object
 			r := receiver.(*RClass)
 
-			for n := range r.constants {
-				constantNames = append(constantNames, n)
-			}
-			sort.Strings(constantNames)
-
-			for _, cn := range constantNames {
+			for _, cn := range r.constantNames() {
 				objs = append(objs, t.vm.InitStringObject(cn))
 			}
 
@@ -869,6 +864,34 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Registers the block to be run when the program exits, and returns nil. The blocks are run in the reverse
+		// order of registration, also when the program exits by `exit`, an uncaught error, or signals like INT and TERM.
+		//
+		// ```ruby
+		// log = File.new("/var/log/app.log", "a")
+		//
+		// at_exit do
+		//   log.close
+		// end
+		// ```
+		//
+		// @return [Null]
+		Name: "at_exit",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			t.vm.atExit(blockFrame)
+
+			// The block is yielded when the program exits, so we need to pop this frame manually
+			t.callFrameStack.pop()
+
+			return NULL
+
+		},
+	},
 	{
 		// Returns true if a block is given in the current context and `yield` is ready to call.
 		//
@@ -987,6 +1010,7 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	// Exits from the interpreter, returning the specified exit code (if any).
+	// The blocks registered by `at_exit` are run before exiting.
 	//
 	// The method itself formally returns nil, although it's not usable.
 	//
@@ -1003,7 +1027,7 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 			aLen := len(args)
 			switch aLen {
 			case 0:
-				os.Exit(0)
+				t.vm.exit(0)
 			case 1:
 				err := t.vm.checkArgTypes(args, sourceLine, classes.IntegerClass)
 
@@ -1011,7 +1035,13 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 					return err
 				}
 
-				os.Exit(args[0].Value().(int))
+				status, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				t.vm.exit(status)
 			default:
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, aLen)
 			}
@@ -1342,7 +1372,7 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
-		// Print an object, without the newline, converting into String if needed.
+		// Print an object to `$stdout`, without the newline, converting into String if needed.
 		//
 		// ```ruby
 		// print("foo", "bar")
@@ -1353,8 +1383,14 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 		// @return [Null]
 		Name: "print",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			var data strings.Builder
+
 			for _, arg := range args {
-				fmt.Print(arg.ToString())
+				data.WriteString(arg.ToString())
+			}
+
+			if err := t.writeOutput("$stdout", data.String(), sourceLine); err != nil {
+				return err
 			}
 
 			return NULL
@@ -1362,8 +1398,8 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
-		// Puts string literals or objects into stdout with a tailing line feed, converting into String
-		// if needed.
+		// Puts string literals or objects into `$stdout` with a tailing line feed, converting into String
+		// if needed. `$stdout` can be reassigned to any object that responds to `write`.
 		//
 		// ```ruby
 		// puts("foo", "bar")
@@ -1381,8 +1417,8 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 		// @return [Null]
 		Name: "puts",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			for _, arg := range args {
-				fmt.Println(arg.ToString())
+			if err := t.writeOutput("$stdout", linesOf(args), sourceLine); err != nil {
+				return err
 			}
 
 			return NULL
@@ -1419,21 +1455,39 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
+		// Returns a random number from the default generator, which is seeded with `goby -seed`.
+		// Without arguments, it's a Float between 0.0 and 1.0. With an Integer or a Float, it's between 0 and the number,
+		// excluding the number itself. With a Range or two Integers, it's an Integer between them, including both ends.
+		//
+		// ```ruby
+		// rand         # => 0.6046602879796196
+		// rand(10)     # => 1
+		// rand(1..6)   # => 4
+		// rand(64, 66) # => 65
+		// ```
+		//
+		// @param max [Integer, Float, Range]
+		// @return [Integer, Float]
 		Name: "rand",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 			aLen := len(args)
 
 			switch aLen {
 			case 0:
-				return t.vm.initFloatObject(rand.Float64())
+				return t.vm.random.rand(t, args, sourceLine)
 			case 1:
+				switch args[0].(type) {
+				case *FloatObject, *RangeObject:
+					return t.vm.random.rand(t, args, sourceLine)
+				}
+
 				err := t.vm.checkArgTypes(args, sourceLine, classes.IntegerClass)
 
 				if err != nil {
 					return err
 				}
 
-				return t.vm.InitIntegerObject(rand.Intn(args[0].Value().(int)))
+				return t.vm.random.rand(t, args, sourceLine)
 			case 2:
 
 				err := t.vm.checkArgTypes(args, sourceLine, classes.IntegerClass, classes.IntegerClass)
@@ -1442,7 +1496,19 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 					return err
 				}
 
-				return t.vm.InitIntegerObject(rand.Intn(args[1].Value().(int)-args[0].Value().(int)+1) + args[0].Value().(int))
+				min, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				max, err := args[1].(*IntegerObject).intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				return t.vm.InitIntegerObject(t.vm.random.intn(max-min+1) + min)
 			default:
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, aLen)
 			}
@@ -1549,7 +1615,7 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 
 			switch args[0].(type) {
 			case *StringObject:
-				callerDir := path.Dir(t.vm.currentFilePath())
+				callerDir := path.Dir(t.currentFilePath())
 				filePath := args[0].(*StringObject).value
 				filePath = path.Join(callerDir, filePath)
 				filePath += ".gb"
@@ -1649,39 +1715,143 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 	},
 	{
 		// Suspends the current thread for duration (sec).
+		// A `Context` can be passed as the second argument to wake up early, in which case a `ContextError` is raised.
 		//
 		// **Note:** currently, parameter cannot be omitted, and only Integer can be specified.
 		//
 		// ```ruby
 		// a = sleep(2)
 		// puts(a)     # => 2
+		//
+		// sleep(2, Context.with_timeout(1)) # => ContextError: context deadline exceeded
 		// ```
 		//
 		// @param sec [Integer] time to wait in sec
+		// @param context [Context]
 		// @return [Integer] actual time slept in sec
 		Name: "sleep",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			if len(args) != 1 {
-				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			aLen := len(args)
+			if aLen < 1 || aLen > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, aLen)
 			}
 
+			var duration time.Duration
+
 			int, ok := args[0].(*IntegerObject)
 
 			if ok {
-				seconds := int.value
-				time.Sleep(time.Duration(seconds) * time.Second)
-				return int
+				seconds, err := int.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				duration = time.Duration(seconds) * time.Second
 			}
 
-			float, ok := args[0].(*FloatObject)
+			float, isFloat := args[0].(*FloatObject)
 
-			if ok {
+			if isFloat {
 				nanoseconds := int64(float.value * float64(time.Second/time.Nanosecond))
-				time.Sleep(time.Duration(nanoseconds) * time.Nanosecond)
-				return float
+				duration = time.Duration(nanoseconds) * time.Nanosecond
+			}
+
+			if !ok && !isFloat {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+			}
+
+			if aLen == 2 {
+				ctx, err := t.vm.contextFromArg(args[1], sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				select {
+				case <-time.After(duration):
+				case <-ctx.Done():
+					return t.vm.initContextError(ctx, sourceLine)
+				}
+			} else {
+				time.Sleep(duration)
+			}
+
+			return args[0]
+
+		},
+	},
+	{
+		// Runs the command and waits for it, with the command's output written to `$stdout` and `$stderr`.
+		// Returns true if the command succeeds, false if it fails, or nil if it can't be run.
+		// The arguments and options are the same as `Process.spawn`'s, and the status can be retrieved by `Process.last_status`.
+		//
+		// ```ruby
+		// system("git", "fetch", "origin")        # => true
+		// system("exit 1")                         # => false
+		// system("make", { dir: "/src/app" })      # => true
+		// system("not_existing_command", "--help") # => nil
+		// ```
+		//
+		// @param command [String], options [Hash]
+		// @return [Boolean]
+		Name: "system",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			cmd, err := t.processCommand(args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if cmd.Start() != nil {
+				return NULL
+			}
+
+			status, ok := t.waitProcess(cmd, sourceLine).(*ProcessStatusObject)
+
+			if !ok {
+				return NULL
+			}
+
+			return toBooleanObject(status.state.Success())
+
+		},
+	},
+	{
+		// Runs the command with the shell and returns its standard output, which is what backticks do.
+		// The command's standard error is written to `$stderr`, and the status can be retrieved by `Process.last_status`.
+		//
+		// ```ruby
+		// `git rev-parse --abbrev-ref HEAD` # => "master\n"
+		// Process.last_status.success?       # => true
+		// ```
+		//
+		// @param command [String]
+		// @return [String]
+		Name: "`",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			if _, ok := args[0].(*StringObject); !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+			}
+
+			cmd, err := t.processCommand(args, sourceLine)
+
+			if err != nil {
+				return err
 			}
 
-			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+			var out bytes.Buffer
+			cmd.Stdout = &out
+
+			if _, err := t.runProcess(cmd, sourceLine); err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(out.String())
 
 		},
 	},
@@ -1753,6 +1923,107 @@ var builtinClassCommonInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Makes the receiver safe to be shared between threads, and returns the receiver.
+		//
+		// Objects like Array and Hash are not synchronized by default, so mutating them from several threads
+		// at the same time can crash the program. After calling `thread_safe!`, only one thread at a time
+		// can call the object's builtin methods; the others wait until the method returns.
+		// The thread holding the object can call its methods again, for example inside an `each` block.
+		//
+		// Call `thread_safe!` before sharing the object with other threads.
+		// `nil`, `true` and `false` can't be changed, so they are always thread-safe and calling `thread_safe!` on them does nothing.
+		// Note that only the methods called on the object are synchronized: elements read by other objects'
+		// methods (e.g. `a.concat(shared)`) are not.
+		//
+		// ```ruby
+		// a = [].thread_safe!
+		// c = Channel.new
+		//
+		// 10.times do |i|
+		//   thread do
+		//     a.push(i)
+		//     c.deliver(i)
+		//   end
+		// end
+		//
+		// 10.times do
+		//   c.receive
+		// end
+		//
+		// a.length # => 10
+		// ```
+		//
+		// @return [Object] the receiver
+		Name: "thread_safe!",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			if isImmutableSingleton(receiver) {
+				return receiver
+			}
+
+			obj, ok := receiver.(threadSafeObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.CantMakeThreadSafe, receiver.Class().Name)
+			}
+
+			obj.makeThreadSafe()
+
+			return receiver
+
+		},
+	},
+	{
+		// Returns `true` if `thread_safe!` has been called on the receiver.
+		//
+		// ```ruby
+		// [].thread_safe?              # => false
+		// [].thread_safe!.thread_safe? # => true
+		// nil.thread_safe?             # => true
+		// ```
+		//
+		// @return [Boolean]
+		Name: "thread_safe?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			if isImmutableSingleton(receiver) {
+				return TRUE
+			}
+
+			obj, ok := receiver.(threadSafeObject)
+
+			return toBooleanObject(ok && obj.threadSafeLock() != nil)
+
+		},
+	},
+	{
+		// Puts string literals or objects into `$stderr` with a tailing line feed, like `puts`.
+		//
+		// ```ruby
+		// warn("deprecated", "use bar instead")
+		// # => deprecated
+		// # => use bar instead
+		// ```
+		//
+		// @param *args [Class] String literals, or other objects that can be converted into String.
+		// @return [Null]
+		Name: "warn",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if err := t.writeOutput("$stderr", linesOf(args), sourceLine); err != nil {
+				return err
+			}
+
+			return NULL
+
+		},
+	},
 	{
 		// Returns object's string representation.
 		// @param n/a []
@@ -1957,9 +2228,9 @@ func (c *RClass) Inspect() string {
 	return c.ToString()
 }
 
-// ToJSON just delegates to `ToString`
+// ToJSON returns the quoted ToString
 func (c *RClass) ToJSON(t *Thread) string {
-	return c.ToString()
+	return jsonString(c.ToString())
 }
 
 // Value returns class itself
@@ -2000,8 +2271,35 @@ func (c *RClass) lookupMethod(methodName string) Object {
 	return method
 }
 
+// getConstant returns the constant stored directly under the class
+func (c *RClass) getConstant(constName string) (constant *Pointer, ok bool) {
+	c.constantsMutex.RLock()
+	constant, ok = c.constants[constName]
+	c.constantsMutex.RUnlock()
+	return
+}
+
+// setConstant stores the constant directly under the class
+func (c *RClass) setConstant(constName string, constant *Pointer) {
+	c.constantsMutex.Lock()
+	c.constants[constName] = constant
+	c.constantsMutex.Unlock()
+}
+
+// constantNames returns the sorted names of the constants stored directly under the class
+func (c *RClass) constantNames() (names []string) {
+	c.constantsMutex.RLock()
+	for n := range c.constants {
+		names = append(names, n)
+	}
+	c.constantsMutex.RUnlock()
+
+	sort.Strings(names)
+	return
+}
+
 func (c *RClass) lookupConstantInCurrentScope(constName string) *Pointer {
-	constant, ok := c.constants[constName]
+	constant, ok := c.getConstant(constName)
 
 	if !ok {
 		return nil
@@ -2011,7 +2309,7 @@ func (c *RClass) lookupConstantInCurrentScope(constName string) *Pointer {
 }
 
 func (c *RClass) lookupConstantUnderCurrentScope(constName string) *Pointer {
-	constant, ok := c.constants[constName]
+	constant, ok := c.getConstant(constName)
 
 	if !ok {
 		if c.scope != nil {
@@ -2025,7 +2323,7 @@ func (c *RClass) lookupConstantUnderCurrentScope(constName string) *Pointer {
 }
 
 func (c *RClass) lookupConstantUnderAllScope(constName string) *Pointer {
-	constant, ok := c.constants[constName]
+	constant, ok := c.getConstant(constName)
 
 	if !ok {
 		if c.scope != nil {
@@ -2034,7 +2332,7 @@ func (c *RClass) lookupConstantUnderAllScope(constName string) *Pointer {
 
 		// Finding constant in superclass means it's out of the scope
 		if c.superClass != nil && c.Name != classes.ObjectClass {
-			constant, _ = c.constants[constName]
+			constant, _ = c.getConstant(constName)
 			return constant
 		}
 
@@ -2045,12 +2343,12 @@ func (c *RClass) lookupConstantUnderAllScope(constName string) *Pointer {
 }
 
 func (c *RClass) setClassConstant(constant *RClass) {
-	c.constants[constant.Name] = &Pointer{Target: constant}
+	c.setConstant(constant.Name, &Pointer{Target: constant})
 }
 
 func (c *RClass) getClassConstant(constName string) (class *RClass) {
-	t := c.constants[constName].Target
-	class, ok := t.(*RClass)
+	constant, _ := c.getConstant(constName)
+	class, ok := constant.Target.(*RClass)
 
 	if ok {
 		return
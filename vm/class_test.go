@@ -1226,6 +1226,8 @@ func TestRandMethod(t *testing.T) {
 		{`rand(1, 2).class.name`, "Integer"},
 		{`(60 < rand(64, 66)).to_s`, "true"},
 		{`(70 > rand(64, 66)).to_s`, "true"},
+		{`rand(2.5).class.name`, "Float"},
+		{`(rand(1..6) >= 1 && rand(1..6) <= 6).to_s`, "true"},
 	}
 	for i, tt := range tests {
 		v := initTestVM()
@@ -1242,6 +1244,7 @@ func TestRandMethodFail(t *testing.T) {
 		{`rand("some string")`, "TypeError: Expect argument to be Integer. got: String", 1},
 		{`rand("some string", "some other string")`, "TypeError: Expect argument to be Integer. got: String", 1},
 		{`rand(10, "some other string")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`rand(0)`, "ArgumentError: Invalid argument - 0", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -2,25 +2,35 @@ package classes
 
 // A list of native classes
 const (
-	ObjectClass    = "Object"
-	ClassClass     = "Class"
-	ModuleClass    = "Module"
-	IntegerClass   = "Integer"
-	FloatClass     = "Float"
-	StringClass    = "String"
-	ArrayClass     = "Array"
-	HashClass      = "Hash"
-	BooleanClass   = "Boolean"
-	NullClass      = "Null"
-	ChannelClass   = "Channel"
-	RangeClass     = "Range"
-	MethodClass    = "Method"
-	PluginClass    = "Plugin"
-	GoObjectClass  = "GoObject"
-	FileClass      = "File"
-	RegexpClass    = "Regexp"
-	MatchDataClass = "MatchData"
-	GoMapClass     = "GoMap"
-	DecimalClass   = "Decimal"
-	BlockClass     = "Block"
+	ObjectClass        = "Object"
+	ClassClass         = "Class"
+	ModuleClass        = "Module"
+	IntegerClass       = "Integer"
+	FloatClass         = "Float"
+	StringClass        = "String"
+	ArrayClass         = "Array"
+	HashClass          = "Hash"
+	BooleanClass       = "Boolean"
+	NullClass          = "Null"
+	ChannelClass       = "Channel"
+	RangeClass         = "Range"
+	MethodClass        = "Method"
+	PluginClass        = "Plugin"
+	GoObjectClass      = "GoObject"
+	FileClass          = "File"
+	FileStatClass      = "Stat"
+	DirClass           = "Dir"
+	RegexpClass        = "Regexp"
+	MatchDataClass     = "MatchData"
+	GoMapClass         = "GoMap"
+	DecimalClass       = "Decimal"
+	BlockClass         = "Block"
+	ContextClass       = "Context"
+	MathModule         = "Math"
+	ProcessModule      = "Process"
+	ProcessStatusClass = "Status"
+	SignalModule       = "Signal"
+	RandomClass        = "Random"
+	TemplateClass      = "Template"
+	StringIOClass      = "StringIO"
 )
@@ -0,0 +1,923 @@
+package vm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Compress compresses and decompresses Strings and files with Go's `compress/gzip` and `compress/zlib` packages.
+// `require "compress/gzip"` loads `Compress::Gzip`, and `require "compress/zlib"` loads `Compress::Zlib`.
+//
+// ```ruby
+// require "compress/gzip"
+//
+// data = Compress::Gzip.compress("Hello, Goby!")
+// Compress::Gzip.decompress(data) # => "Hello, Goby!"
+// ```
+//
+// Their `Writer` compresses the data written into a File, and their `Reader` decompresses a File or a String:
+//
+// ```ruby
+// Compress::Gzip::Writer.open("app.log.gz") do |gz|
+//   gz.puts("started")
+//   gz.write("stopped\n")
+// end
+//
+// Compress::Gzip::Reader.open("app.log.gz") do |gz|
+//   gz.each_line do |line|
+//     puts(line)
+//   end
+// end
+// ```
+//
+// The compression level is given with the `level` option, from 1 (best speed) to 9 (best compression).
+// Corrupted data raises `Compress::DataError`.
+//
+// - `Compress.new`, `Compress::Gzip.new` and `Compress::Zlib.new` are not supported.
+
+// CompressWriterObject compresses the data written into a File
+type CompressWriterObject struct {
+	*BaseObj
+	writer io.WriteCloser
+	file   *FileObject
+	// ownsFile is true if the file is opened by `open`, so it's closed with the writer
+	ownsFile bool
+	closed   bool
+}
+
+// CompressReaderObject decompresses a File or a String
+type CompressReaderObject struct {
+	*BaseObj
+	reader       *bufio.Reader
+	decompressor io.ReadCloser
+	file         *FileObject
+	ownsFile     bool
+	closed       bool
+}
+
+// compressFormat creates the compressors and the decompressors of a format
+type compressFormat struct {
+	newWriter func(w io.Writer, level int) (io.WriteCloser, error)
+	newReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+// compressFormats are the formats of the classes under `Compress`
+var compressFormats = map[string]compressFormat{
+	"Gzip": {
+		newWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+			return gzip.NewWriterLevel(w, level)
+		},
+		newReader: func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+	},
+	"Zlib": {
+		newWriter: func(w io.Writer, level int) (io.WriteCloser, error) {
+			return zlib.NewWriterLevel(w, level)
+		},
+		newReader: zlib.NewReader,
+	},
+}
+
+// fileWriter writes into a File
+type fileWriter struct {
+	file *FileObject
+}
+
+func (w fileWriter) Write(p []byte) (int, error) {
+	return w.file.write(string(p))
+}
+
+// Class methods --------------------------------------------------------
+var builtinCompressClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Class methods of the formats -------------------------------------------
+var builtinCompressFormatClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the compressed data as a binary String.
+		//
+		// ```ruby
+		// Compress::Zlib.compress("aaaaaaaaaa", { level: 9 })
+		// ```
+		//
+		// @param string [String], options [Hash]
+		// @return [String]
+		Name: "compress",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			s, ok := args[0].(*StringObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.StringClass, args[0].Class().Name)
+			}
+
+			level, err := compressLevelOf(t, args[1:], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			var b bytes.Buffer
+			w, e := compressFormats[receiver.(*RClass).Name].newWriter(&b, level)
+
+			if e != nil {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, e.Error())
+			}
+
+			io.WriteString(w, s.value)
+			w.Close()
+
+			return t.vm.initBinaryStringObject(b.String())
+
+		},
+	},
+	{
+		// Returns the decompressed data as a binary String.
+		//
+		// ```ruby
+		// Compress::Gzip.decompress(Compress::Gzip.compress("Goby")) # => "Goby"
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "decompress",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			r, err := compressFormats[receiver.(*RClass).Name].newReader(strings.NewReader(args[0].(*StringObject).value))
+
+			if err != nil {
+				return t.vm.compressReadError(err, sourceLine)
+			}
+
+			defer r.Close()
+
+			b, err := ioutil.ReadAll(r)
+
+			if err != nil {
+				return t.vm.compressReadError(err, sourceLine)
+			}
+
+			return t.vm.initBinaryStringObject(string(b))
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Class methods of the writers -------------------------------------------
+var builtinCompressWriterClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns a writer compressing the data into the File. Closing the writer finishes the compressed data,
+		// but doesn't close the File.
+		//
+		// ```ruby
+		// f = File.new("app.log.gz", "w")
+		// gz = Compress::Gzip::Writer.new(f, { level: 1 })
+		// gz.write("Hello")
+		// gz.close
+		// f.close
+		// ```
+		//
+		// @param file [File], options [Hash]
+		// @return [Writer]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			f, ok := args[0].(*FileObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.FileClass, args[0].Class().Name)
+			}
+
+			level, err := compressLevelOf(t, args[1:], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			w, err := t.vm.initCompressWriterObject(receiver.(*RClass), f, level, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return w
+
+		},
+	},
+	{
+		// Creates the file and yields a writer compressing the data into it. The writer and the file are closed
+		// after the block, and the result of the block is returned. Without a block, the writer is returned,
+		// and closing it closes the file too.
+		//
+		// ```ruby
+		// Compress::Zlib::Writer.open("data.z") do |z|
+		//   z << "Hello"
+		// end
+		// ```
+		//
+		// @param path [String], options [Hash]
+		// @return [Object]
+		Name: "open",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return compressOpenError(t, blockFrame, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args)))
+			}
+
+			path, ok := args[0].(*StringObject)
+
+			if !ok {
+				return compressOpenError(t, blockFrame, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.StringClass, args[0].Class().Name))
+			}
+
+			level, err := compressLevelOf(t, args[1:], sourceLine)
+
+			if err != nil {
+				return compressOpenError(t, blockFrame, err)
+			}
+
+			file, e := os.Create(path.value)
+
+			if e != nil {
+				return compressOpenError(t, blockFrame, t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error()))
+			}
+
+			w, err := t.vm.initCompressWriterObject(receiver.(*RClass), t.vm.initFileObject(file), level, sourceLine)
+
+			if err != nil {
+				file.Close()
+				return compressOpenError(t, blockFrame, err)
+			}
+
+			w.ownsFile = true
+
+			if blockFrame == nil {
+				return w
+			}
+
+			defer w.close()
+			result := t.builtinMethodYield(blockFrame, w)
+
+			if err := w.close(); err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return result
+
+		},
+	},
+}
+
+// Instance methods of the writers ----------------------------------------
+var builtinCompressWriterInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Writes the String, same as `write` but returns the writer.
+		//
+		// @param string [String]
+		// @return [Writer]
+		Name: "<<",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			if _, err := receiver.(*CompressWriterObject).write(t, args[0].ToString(), sourceLine); err != nil {
+				return err
+			}
+
+			return receiver
+
+		},
+	},
+	{
+		// Finishes the compressed data. The file is closed too if the writer is created by `open`.
+		// Closing a closed writer does nothing.
+		//
+		// @return [Null]
+		Name: "close",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			if err := receiver.(*CompressWriterObject).close(); err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return NULL
+
+		},
+	},
+	{
+		// Returns true if the writer is closed.
+		//
+		// @return [Boolean]
+		Name: "closed?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return toBooleanObject(receiver.(*CompressWriterObject).closed)
+
+		},
+	},
+	{
+		// Writes the given objects with a trailing line feed each, converting non-string objects with `to_s`.
+		//
+		// @return [Null]
+		Name: "puts",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if _, err := receiver.(*CompressWriterObject).write(t, linesOf(args), sourceLine); err != nil {
+				return err
+			}
+
+			return NULL
+
+		},
+	},
+	{
+		// Writes the given objects, converting non-string objects with `to_s`, and returns the number of bytes written.
+		//
+		// ```ruby
+		// gz.write("a", 1, "\n") # => 3
+		// ```
+		//
+		// @return [Integer]
+		Name: "write",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			var data strings.Builder
+
+			for _, arg := range args {
+				data.WriteString(arg.ToString())
+			}
+
+			n, err := receiver.(*CompressWriterObject).write(t, data.String(), sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitIntegerObject(n)
+
+		},
+	},
+}
+
+// Class methods of the readers -------------------------------------------
+var builtinCompressReaderClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns a reader decompressing the File from its current position, or the String.
+		//
+		// ```ruby
+		// Compress::Gzip::Reader.new(File.new("app.log.gz")).read
+		// Compress::Zlib::Reader.new(data).gets
+		// ```
+		//
+		// @param source [File, String]
+		// @return [Reader]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			var source io.Reader
+			var file *FileObject
+
+			switch arg := args[0].(type) {
+			case *FileObject:
+				source = arg.bufReader()
+				file = arg
+			case *StringObject:
+				source = strings.NewReader(arg.value)
+			default:
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.FileClass+" or "+classes.StringClass, args[0].Class().Name)
+			}
+
+			r, err := t.vm.initCompressReaderObject(receiver.(*RClass), source, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			r.file = file
+			return r
+
+		},
+	},
+	{
+		// Opens the file and yields a reader decompressing it. The reader and the file are closed after the block,
+		// and the result of the block is returned. Without a block, the reader is returned,
+		// and closing it closes the file too.
+		//
+		// ```ruby
+		// Compress::Gzip::Reader.open("app.log.gz") do |gz|
+		//   gz.read
+		// end
+		// ```
+		//
+		// @param path [String]
+		// @return [Object]
+		Name: "open",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return compressOpenError(t, blockFrame, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args)))
+			}
+
+			path, ok := args[0].(*StringObject)
+
+			if !ok {
+				return compressOpenError(t, blockFrame, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name))
+			}
+
+			file, e := os.Open(path.value)
+
+			if e != nil {
+				return compressOpenError(t, blockFrame, t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error()))
+			}
+
+			f := t.vm.initFileObject(file)
+			r, err := t.vm.initCompressReaderObject(receiver.(*RClass), f.bufReader(), sourceLine)
+
+			if err != nil {
+				file.Close()
+				return compressOpenError(t, blockFrame, err)
+			}
+
+			r.file = f
+			r.ownsFile = true
+
+			if blockFrame == nil {
+				return r
+			}
+
+			defer r.close()
+			return t.builtinMethodYield(blockFrame, r)
+
+		},
+	},
+}
+
+// Instance methods of the readers ----------------------------------------
+var builtinCompressReaderInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Closes the reader. The file is closed too if the reader is created by `open`.
+		//
+		// @return [Null]
+		Name: "close",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			receiver.(*CompressReaderObject).close()
+			return NULL
+
+		},
+	},
+	{
+		// Yields each decompressed line including the trailing newline.
+		//
+		// ```ruby
+		// Compress::Gzip::Reader.open("app.log.gz") do |gz|
+		//   gz.each_line do |line|
+		//     puts(line)
+		//   end
+		// end
+		// ```
+		//
+		// @return [Reader]
+		Name: "each_line",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			r := receiver.(*CompressReaderObject)
+			yielded := false
+
+			for {
+				line, err := r.gets(t, sourceLine)
+
+				if err != nil {
+					if !yielded {
+						t.callFrameStack.pop()
+					}
+
+					return err
+				}
+
+				if line == nil {
+					break
+				}
+
+				yielded = true
+				t.builtinMethodYield(blockFrame, line)
+			}
+
+			if !yielded {
+				t.callFrameStack.pop()
+			}
+
+			return r
+
+		},
+	},
+	{
+		// Returns true if there's nothing left to decompress.
+		//
+		// @return [Boolean]
+		Name: "eof?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			_, err := receiver.(*CompressReaderObject).reader.Peek(1)
+
+			if err == io.EOF {
+				return TRUE
+			}
+
+			if err != nil {
+				return t.vm.compressReadError(err, sourceLine)
+			}
+
+			return FALSE
+
+		},
+	},
+	{
+		// Returns the next decompressed line including the trailing newline, or nil at the end.
+		//
+		// @return [String]
+		Name: "gets",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			line, err := receiver.(*CompressReaderObject).gets(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if line == nil {
+				return NULL
+			}
+
+			return line
+
+		},
+	},
+	{
+		// Returns the rest of the decompressed data. If a length is given, returns at most that many bytes as a binary String instead,
+		// or nil at the end.
+		//
+		// ```ruby
+		// gz.read(4) # => "Hell"
+		// gz.read    # => "o, Goby!"
+		// ```
+		//
+		// @param length [Integer]
+		// @return [String]
+		Name: "read",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			r := receiver.(*CompressReaderObject)
+
+			if len(args) == 0 {
+				b, err := ioutil.ReadAll(r.reader)
+
+				if err != nil {
+					return t.vm.compressReadError(err, sourceLine)
+				}
+
+				return t.vm.InitStringObject(string(b))
+			}
+
+			n, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+			}
+
+			length, errObj := n.intValue(t, sourceLine)
+
+			if errObj != nil {
+				return errObj
+			}
+
+			if length < 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, length)
+			}
+
+			// The buffer grows as the data is read, so a large length doesn't allocate the memory up front
+			var buf bytes.Buffer
+			l, err := io.CopyN(&buf, r.reader, int64(length))
+
+			if err == io.EOF && l == 0 && length > 0 {
+				return NULL
+			}
+
+			if err != nil && err != io.EOF {
+				return t.vm.compressReadError(err, sourceLine)
+			}
+
+			return t.vm.initBinaryStringObject(buf.String())
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initGzipClass(vm *VM) {
+	initCompressFormatClass(vm, "Gzip")
+}
+
+func initZlibClass(vm *VM) {
+	initCompressFormatClass(vm, "Zlib")
+}
+
+// initCompressFormatClass initializes the class of the format and its writer and reader under `Compress`
+func initCompressFormatClass(vm *VM, name string) {
+	compress := vm.loadConstant("Compress", true)
+	compress.setBuiltinMethods(builtinCompressClassMethods, true)
+
+	if ptr, _ := compress.getConstant("DataError"); ptr == nil {
+		compress.setClassConstant(vm.initializeClass("DataError"))
+	}
+
+	class := vm.initializeClass(name)
+	class.setBuiltinMethods(builtinCompressFormatClassMethods, true)
+
+	writer := vm.initializeClass("Writer")
+	writer.scope = class
+	writer.setBuiltinMethods(builtinCompressWriterClassMethods, true)
+	writer.setBuiltinMethods(builtinCompressWriterInstanceMethods, false)
+	class.setClassConstant(writer)
+
+	reader := vm.initializeClass("Reader")
+	reader.scope = class
+	reader.setBuiltinMethods(builtinCompressReaderClassMethods, true)
+	reader.setBuiltinMethods(builtinCompressReaderInstanceMethods, false)
+	class.setClassConstant(reader)
+
+	compress.setClassConstant(class)
+}
+
+// initCompressWriterObject returns a writer of the format of the class's scope
+func (vm *VM) initCompressWriterObject(class *RClass, file *FileObject, level int, sourceLine int) (*CompressWriterObject, *Error) {
+	w, err := compressFormats[compressFormatName(class)].newWriter(fileWriter{file}, level)
+
+	if err != nil {
+		return nil, vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+	}
+
+	return &CompressWriterObject{
+		BaseObj: NewBaseObject(class),
+		writer:  w,
+		file:    file,
+	}, nil
+}
+
+// initCompressReaderObject returns a reader of the format of the class's scope, which reads the header at once
+func (vm *VM) initCompressReaderObject(class *RClass, source io.Reader, sourceLine int) (*CompressReaderObject, *Error) {
+	r, err := compressFormats[compressFormatName(class)].newReader(source)
+
+	if err != nil {
+		return nil, vm.compressReadError(err, sourceLine)
+	}
+
+	return &CompressReaderObject{
+		BaseObj:      NewBaseObject(class),
+		reader:       bufio.NewReader(r),
+		decompressor: r,
+	}, nil
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the name of the class
+func (w *CompressWriterObject) ToString() string {
+	return "#<Compress::" + compressFormatName(w.class) + "::Writer>"
+}
+
+// Inspect delegates to ToString
+func (w *CompressWriterObject) Inspect() string {
+	return w.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (w *CompressWriterObject) ToJSON(t *Thread) string {
+	return jsonString(w.ToString())
+}
+
+// Value returns the compressor
+func (w *CompressWriterObject) Value() interface{} {
+	return w.writer
+}
+
+// ToString returns the name of the class
+func (r *CompressReaderObject) ToString() string {
+	return "#<Compress::" + compressFormatName(r.class) + "::Reader>"
+}
+
+// Inspect delegates to ToString
+func (r *CompressReaderObject) Inspect() string {
+	return r.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (r *CompressReaderObject) ToJSON(t *Thread) string {
+	return jsonString(r.ToString())
+}
+
+// Value returns the decompressor
+func (r *CompressReaderObject) Value() interface{} {
+	return r.decompressor
+}
+
+// Other helper functions -----------------------------------------------
+
+func (w *CompressWriterObject) write(t *Thread, data string, sourceLine int) (int, *Error) {
+	if w.closed {
+		return 0, t.vm.InitErrorObject(errors.IOError, sourceLine, "closed stream")
+	}
+
+	n, err := io.WriteString(w.writer, data)
+
+	if err != nil {
+		return n, t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	return n, nil
+}
+
+// close finishes the compressed data and flushes it into the file
+func (w *CompressWriterObject) close() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	err := w.writer.Close()
+
+	var e error
+
+	if w.ownsFile {
+		e = w.file.close()
+	} else {
+		e = w.file.flush()
+	}
+
+	if err == nil {
+		err = e
+	}
+
+	return err
+}
+
+// gets returns the next line, or nil at the end
+func (r *CompressReaderObject) gets(t *Thread, sourceLine int) (Object, *Error) {
+	line, err := r.reader.ReadString('\n')
+
+	if err == io.EOF && len(line) == 0 {
+		return nil, nil
+	}
+
+	if err != nil && err != io.EOF {
+		return nil, t.vm.compressReadError(err, sourceLine)
+	}
+
+	return t.vm.InitStringObject(line), nil
+}
+
+func (r *CompressReaderObject) close() {
+	if r.closed {
+		return
+	}
+
+	r.closed = true
+	r.decompressor.Close()
+
+	if r.ownsFile {
+		r.file.close()
+	}
+}
+
+// compressFormatName returns the name of the format of the writer or reader class, which is its scope
+func compressFormatName(class *RClass) string {
+	return class.scope.Name
+}
+
+// compressLevelOf returns the compression level of the optional options
+func compressLevelOf(t *Thread, args []Object, sourceLine int) (int, *Error) {
+	level := gzip.DefaultCompression
+
+	if len(args) == 0 {
+		return level, nil
+	}
+
+	options, ok := args[0].(*HashObject)
+
+	if !ok {
+		return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.HashClass, args[0].Class().Name)
+	}
+
+	for _, key := range options.sortedKeys() {
+		if key != "level" {
+			return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+		}
+
+		l, ok := options.Pairs[key].(*IntegerObject)
+
+		if !ok {
+			return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, options.Pairs[key].Class().Name)
+		}
+
+		value, err := l.intValue(t, sourceLine)
+
+		if err != nil {
+			return 0, err
+		}
+
+		level = value
+	}
+
+	return level, nil
+}
+
+// compressReadError returns a Compress::DataError for corrupted data, or an IOError
+func (vm *VM) compressReadError(err error, sourceLine int) *Error {
+	switch err {
+	case gzip.ErrHeader, gzip.ErrChecksum, zlib.ErrHeader, zlib.ErrChecksum, zlib.ErrDictionary, io.ErrUnexpectedEOF, io.EOF:
+		return vm.InitErrorObject(errors.CompressDataError, sourceLine, err.Error())
+	}
+
+	if _, ok := err.(flate.CorruptInputError); ok {
+		return vm.InitErrorObject(errors.CompressDataError, sourceLine, err.Error())
+	}
+
+	return vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+}
+
+// compressOpenError pops the block that can't be yielded, and returns the error
+func compressOpenError(t *Thread, blockFrame *normalCallFrame, err *Error) *Error {
+	if blockFrame != nil {
+		t.callFrameStack.pop()
+	}
+
+	return err
+}
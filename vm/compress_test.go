@@ -0,0 +1,154 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestCompressMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`require "compress/gzip";Compress::Gzip.decompress(Compress::Gzip.compress("Hello, Goby!"))`, "Hello, Goby!"},
+		{`require "compress/gzip";Compress::Gzip.decompress(Compress::Gzip.compress(""))`, ""},
+		{`require "compress/gzip";Compress::Gzip.compress("Goby").size > 0`, true},
+		{`require "compress/zlib";Compress::Zlib.decompress(Compress::Zlib.compress("Hello, Goby!", { level: 9 }))`, "Hello, Goby!"},
+		{`require "compress/zlib";Compress::Zlib.compress("a" * 1000, { level: 9 }).size < 100`, true},
+		{`
+		require "compress/gzip"
+		require "compress/zlib"
+		Compress::Zlib.decompress(Compress::Gzip.decompress(Compress::Gzip.compress(Compress::Zlib.compress("nested"))))
+		`, "nested"},
+		{`
+		require "compress/gzip"
+		r = Compress::Gzip::Reader.new(Compress::Gzip.compress("a\nb\nc"))
+		[r.gets, r.read(1), r.eof?, r.read, r.eof?, r.read(1), r.gets]
+		`, []interface{}{"a\n", "b", false, "\nc", true, nil, nil}},
+		{`
+		require "compress/zlib"
+		lines = []
+		Compress::Zlib::Reader.new(Compress::Zlib.compress("x\ny\n")).each_line do |line|
+		  lines.push(line)
+		end
+		lines
+		`, []interface{}{"x\n", "y\n"}},
+		{`require "compress/gzip";Compress::Gzip::Reader.new(Compress::Gzip.compress("")).inspect`, "#<Compress::Gzip::Reader>"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCompressFileMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "compress/gzip"
+		Compress::Gzip::Writer.open("/tmp/goby/app.log.gz") do |gz|
+		  gz.puts("started", 1)
+		  gz << "stopped"
+		  gz.write("\n", 2)
+		end
+
+		Compress::Gzip::Reader.open("/tmp/goby/app.log.gz") do |gz|
+		  gz.read
+		end
+		`, "started\n1\nstopped\n2"},
+		{`
+		require "compress/zlib"
+		f = File.new("/tmp/goby/data.z", "w")
+		z = Compress::Zlib::Writer.new(f, { level: 1 })
+		z.write("Hello")
+		closed = z.closed?
+		z.close
+		z.close
+		f.write("trailing")
+		f.close
+
+		f = File.new("/tmp/goby/data.z")
+		r = Compress::Zlib::Reader.new(f)
+		[closed, z.closed?, r.read]
+		`, []interface{}{false, true, "Hello"}},
+		{`
+		require "compress/gzip"
+		gz = Compress::Gzip::Writer.open("/tmp/goby/block.gz")
+		gz.write("no block")
+		gz.close
+
+		r = Compress::Gzip::Reader.open("/tmp/goby/block.gz")
+		s = r.read
+		r.close
+		s
+		`, "no block"},
+		{`
+		require "compress/gzip"
+		Compress::Gzip::Writer.open("/tmp/goby/lines.gz") do |gz|
+		  gz.puts("a", "b")
+		end
+
+		lines = []
+		Compress::Gzip::Reader.open("/tmp/goby/lines.gz") do |gz|
+		  gz.each_line do |line|
+		    lines.push(line)
+		  end
+		end
+		lines
+		`, []interface{}{"a\n", "b\n"}},
+	}
+
+	setup()
+	defer teardown()
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCompressMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "compress/gzip";Compress.new`, "NoMethodError: Undefined Method 'new' for Compress", 1},
+		{`require "compress/gzip";Compress::Gzip.new`, "NoMethodError: Undefined Method 'new' for Gzip", 1},
+		{`require "compress/gzip";Compress::Gzip.compress`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`require "compress/gzip";Compress::Gzip.compress(1)`, "TypeError: Expect argument #1 to be String. got: Integer", 1},
+		{`require "compress/gzip";Compress::Gzip.compress("a", 1)`, "TypeError: Expect argument #2 to be Hash. got: Integer", 1},
+		{`require "compress/gzip";Compress::Gzip.compress("a", { speed: 1 })`, "ArgumentError: Unknown option - speed", 1},
+		{`require "compress/gzip";Compress::Gzip.compress("a", { level: "1" })`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`require "compress/gzip";Compress::Gzip.compress("a", { level: 10 })`, "ArgumentError: gzip: invalid compression level: 10", 1},
+		{`require "compress/gzip";Compress::Gzip.decompress(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "compress/gzip";Compress::Gzip.decompress("not gzipped data")`, "Compress::DataError: gzip: invalid header", 1},
+		{`require "compress/zlib";Compress::Zlib.decompress("not zlib data")`, "Compress::DataError: zlib: invalid header", 1},
+		{`require "compress/gzip";require "hex";Compress::Gzip.decompress(Hex.decode(Hex.encode(Compress::Gzip.compress("Goby"))[0..23]))`, "Compress::DataError: unexpected EOF", 1},
+		{`require "compress/gzip";Compress::Gzip::Writer.new("file")`, "TypeError: Expect argument #1 to be File. got: String", 1},
+		{`require "compress/gzip";Compress::Gzip::Reader.new(1)`, "TypeError: Expect argument to be File or String. got: Integer", 1},
+		{`require "compress/gzip";Compress::Gzip::Reader.open("/tmp/goby/not_existing.gz")`, "IOError: open /tmp/goby/not_existing.gz: no such file or directory", 1},
+		{`require "compress/gzip";Compress::Gzip::Reader.open("/tmp/goby/not_existing.gz") do |gz|; end`, "IOError: open /tmp/goby/not_existing.gz: no such file or directory", 1},
+		{`
+		require "compress/gzip"
+		gz = Compress::Gzip::Writer.open("/tmp/goby/closed.gz")
+		gz.close
+		gz.write("a")
+		`, "IOError: closed stream", 1},
+	}
+
+	setup()
+	defer teardown()
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
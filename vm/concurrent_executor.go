@@ -0,0 +1,620 @@
+package vm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// ConcurrentExecutorObject is a fixed-size pool of worker threads.
+// Unlike `thread`, which spawns a new goroutine for every block, an executor runs the submitted blocks
+// on at most `size` threads at a time, so the amount of concurrent work stays bounded.
+//
+// `submit` returns a `Concurrent::Future` that holds the block's result once it is finished.
+// `submit` blocks when all the workers are busy and the queue (which has the same size as the pool) is full.
+//
+// ```ruby
+// require 'concurrent/executor'
+//
+// executor = Concurrent::Executor.new(4)
+//
+// futures = (1..10).to_a.map do |i|
+//   executor.submit(i) do |n|
+//     n * 2
+//   end
+// end
+//
+// Concurrent::Future.all(futures).value # => [2, 4, 6, 8, 10, 12, 14, 16, 18, 20]
+// executor.shutdown
+// ```
+//
+type ConcurrentExecutorObject struct {
+	*BaseObj
+	size       int
+	tasks      chan *executorTask
+	workers    *sync.WaitGroup
+	sending    *sync.WaitGroup
+	mutex      *sync.Mutex
+	isShutdown bool
+}
+
+// ConcurrentFutureObject represents the result of a block that is (or will be) evaluated on another thread.
+// If the block raises an error, the error is raised again when the future's value is retrieved.
+//
+// ```ruby
+// require 'concurrent/executor'
+//
+// executor = Concurrent::Executor.new(2)
+// future = executor.submit do
+//   10
+// end
+//
+// future.then do |v|
+//   v + 1
+// end.value # => 11
+// ```
+//
+type ConcurrentFutureObject struct {
+	*BaseObj
+	done   chan struct{}
+	once   *sync.Once
+	result Object
+	err    *Error
+}
+
+type executorTask struct {
+	blockFrame *normalCallFrame
+	args       []Object
+	future     *ConcurrentFutureObject
+}
+
+// Class methods --------------------------------------------------------
+var builtinConcurrentExecutorClassMethods = []*BuiltinMethodObject{
+	{
+		// Creates an executor with the given number of worker threads.
+		//
+		// ```ruby
+		// Concurrent::Executor.new(4)
+		// ```
+		//
+		// @param size [Integer]
+		// @return [Concurrent::Executor]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			size, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+			}
+
+			value, err := size.intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if value <= 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, value)
+			}
+
+			return t.vm.initConcurrentExecutorObject(value)
+
+		},
+	},
+}
+
+var builtinConcurrentFutureClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns a future that is resolved with an array of the given futures' values, keeping their order.
+		// If any of the futures fails, the returned future fails with the same error.
+		//
+		// ```ruby
+		// Concurrent::Future.all([f1, f2]).value # => [1, 2]
+		// ```
+		//
+		// @param futures [Array]
+		// @return [Concurrent::Future]
+		Name: "all",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			futures, err := t.vm.futuresFromArgs(args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			combined := t.vm.initConcurrentFutureObject()
+
+			go func() {
+				values := []Object{}
+
+				for _, f := range futures {
+					<-f.done
+
+					if f.err != nil {
+						combined.resolve(f.err)
+						return
+					}
+
+					values = append(values, f.result)
+				}
+
+				combined.resolve(t.vm.InitArrayObject(values))
+			}()
+
+			return combined
+
+		},
+	},
+	{
+		// Returns a future that is resolved with the outcome of whichever given future finishes first.
+		//
+		// ```ruby
+		// Concurrent::Future.any([slow, fast]).value # => the value of `fast`
+		// ```
+		//
+		// @param futures [Array]
+		// @return [Concurrent::Future]
+		Name: "any",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			futures, err := t.vm.futuresFromArgs(args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if len(futures) == 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect at least one future. got: 0")
+			}
+
+			first := t.vm.initConcurrentFutureObject()
+
+			for _, f := range futures {
+				go func(f *ConcurrentFutureObject) {
+					<-f.done
+
+					if f.err != nil {
+						first.resolve(f.err)
+						return
+					}
+
+					first.resolve(f.result)
+				}(f)
+			}
+
+			return first
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinConcurrentExecutorInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Stops accepting new blocks, waits for all the submitted blocks to finish and then stops the workers.
+		// Calling `shutdown` twice is allowed.
+		//
+		// ```ruby
+		// executor = Concurrent::Executor.new(2)
+		// executor.submit do
+		//   sleep(1)
+		// end
+		// executor.shutdown # returns after the block above is finished
+		// ```
+		//
+		// @return [Null]
+		Name: "shutdown",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			e := receiver.(*ConcurrentExecutorObject)
+
+			e.mutex.Lock()
+			wasShutdown := e.isShutdown
+			e.isShutdown = true
+			e.mutex.Unlock()
+
+			if !wasShutdown {
+				// The blocks being submitted right now are still queued before the channel is closed
+				e.sending.Wait()
+				close(e.tasks)
+			}
+
+			e.workers.Wait()
+
+			return NULL
+
+		},
+	},
+	{
+		// Returns `true` if the executor has been shut down.
+		//
+		// ```ruby
+		// executor = Concurrent::Executor.new(2)
+		// executor.shutdown?  # => false
+		// executor.shutdown
+		// executor.shutdown?  # => true
+		// ```
+		//
+		// @return [Boolean]
+		Name: "shutdown?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			e := receiver.(*ConcurrentExecutorObject)
+
+			e.mutex.Lock()
+			defer e.mutex.Unlock()
+
+			return toBooleanObject(e.isShutdown)
+
+		},
+	},
+	{
+		// Returns the number of worker threads.
+		//
+		// ```ruby
+		// Concurrent::Executor.new(4).size # => 4
+		// ```
+		//
+		// @return [Integer]
+		Name: "size",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(receiver.(*ConcurrentExecutorObject).size)
+
+		},
+	},
+	{
+		// Queues the block to be evaluated by one of the workers and returns a future of its result.
+		// The given arguments are passed to the block.
+		//
+		// ```ruby
+		// executor = Concurrent::Executor.new(2)
+		// future = executor.submit(1, 2) do |a, b|
+		//   a + b
+		// end
+		// future.value # => 3
+		// ```
+		//
+		// @param *args [Object]
+		// @return [Concurrent::Future]
+		Name: "submit",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			e := receiver.(*ConcurrentExecutorObject)
+
+			e.mutex.Lock()
+
+			if e.isShutdown {
+				e.mutex.Unlock()
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.ExecutorIsShutdown)
+			}
+
+			e.sending.Add(1)
+			e.mutex.Unlock()
+
+			// The queue can be full, so the task is sent without holding the lock
+			future := t.vm.initConcurrentFutureObject()
+			e.tasks <- &executorTask{blockFrame: blockFrame, args: args, future: future}
+			e.sending.Done()
+
+			// We need to pop this frame from current thread manually,
+			// because the block's 'leave' instruction is running on a worker thread
+			t.callFrameStack.pop()
+
+			return future
+
+		},
+	},
+}
+
+var builtinConcurrentFutureInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Returns `true` if the block has finished, whether it succeeded or not.
+		//
+		// ```ruby
+		// future = executor.submit do
+		//   sleep(1)
+		// end
+		// future.completed? # => false
+		// ```
+		//
+		// @return [Boolean]
+		Name: "completed?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			select {
+			case <-receiver.(*ConcurrentFutureObject).done:
+				return TRUE
+			default:
+				return FALSE
+			}
+
+		},
+	},
+	{
+		// Returns a new future that is resolved with the block's result, which takes the receiver's value.
+		// The block runs on a new thread after the receiver is finished.
+		// If the receiver fails, the block is skipped and the new future fails with the same error.
+		//
+		// ```ruby
+		// future = executor.submit do
+		//   10
+		// end
+		//
+		// future.then do |v|
+		//   v * 2
+		// end.value # => 20
+		// ```
+		//
+		// @return [Concurrent::Future]
+		Name: "then",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			f := receiver.(*ConcurrentFutureObject)
+			next := t.vm.initConcurrentFutureObject()
+
+			go func() {
+				<-f.done
+
+				if f.err != nil {
+					next.resolve(f.err)
+					return
+				}
+
+				next.resolve(t.vm.yieldOnNewThread(blockFrame, f.result))
+			}()
+
+			// We need to pop this frame from current thread manually,
+			// because the block's 'leave' instruction is running on other thread
+			t.callFrameStack.pop()
+
+			return next
+
+		},
+	},
+	{
+		// Waits for the block to finish and returns its result. If the block raised an error, the error is raised.
+		// An optional timeout in seconds (Integer or Float) can be given; `nil` is returned if the block doesn't
+		// finish in time.
+		//
+		// ```ruby
+		// future = executor.submit do
+		//   sleep(2)
+		//   10
+		// end
+		//
+		// future.value(0.5) # => nil
+		// future.value      # => 10
+		// ```
+		//
+		// @param timeout [Numeric]
+		// @return [Object]
+		Name: "value",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			aLen := len(args)
+
+			if aLen > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, aLen)
+			}
+
+			f := receiver.(*ConcurrentFutureObject)
+
+			if aLen == 0 {
+				<-f.done
+			} else {
+				timeout, ok := args[0].(Numeric)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+				}
+
+				select {
+				case <-f.done:
+				case <-time.After(secondsToDuration(timeout)):
+					return NULL
+				}
+			}
+
+			if f.err != nil {
+				return f.err
+			}
+
+			return f.result
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initConcurrentExecutorObject(size int) *ConcurrentExecutorObject {
+	concurrent := vm.loadConstant("Concurrent", true)
+
+	e := &ConcurrentExecutorObject{
+		BaseObj: NewBaseObject(concurrent.getClassConstant("Executor")),
+		size:    size,
+		tasks:   make(chan *executorTask, size),
+		workers: &sync.WaitGroup{},
+		sending: &sync.WaitGroup{},
+		mutex:   &sync.Mutex{},
+	}
+
+	e.workers.Add(size)
+
+	for i := 0; i < size; i++ {
+		go e.work(vm)
+	}
+
+	return e
+}
+
+func (vm *VM) initConcurrentFutureObject() *ConcurrentFutureObject {
+	concurrent := vm.loadConstant("Concurrent", true)
+
+	return &ConcurrentFutureObject{
+		BaseObj: NewBaseObject(concurrent.getClassConstant("Future")),
+		done:    make(chan struct{}),
+		once:    &sync.Once{},
+	}
+}
+
+func initConcurrentExecutorClass(vm *VM) {
+	concurrent := vm.loadConstant("Concurrent", true)
+
+	executor := vm.initializeClass("Executor")
+	executor.setBuiltinMethods(builtinConcurrentExecutorInstanceMethods, false)
+	executor.setBuiltinMethods(builtinConcurrentExecutorClassMethods, true)
+
+	future := vm.initializeClass("Future")
+	future.setBuiltinMethods(builtinConcurrentFutureInstanceMethods, false)
+	future.setBuiltinMethods(builtinConcurrentFutureClassMethods, true)
+
+	concurrent.setClassConstant(executor)
+	concurrent.setClassConstant(future)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// work evaluates the queued tasks one by one until the executor is shut down
+func (e *ConcurrentExecutorObject) work(vm *VM) {
+	defer e.workers.Done()
+
+	for task := range e.tasks {
+		task.future.resolve(vm.yieldOnNewThread(task.blockFrame, task.args...))
+	}
+}
+
+// Value returns the object
+func (e *ConcurrentExecutorObject) Value() interface{} {
+	return e.tasks
+}
+
+// ToString returns the object's name as the string format
+func (e *ConcurrentExecutorObject) ToString() string {
+	return fmt.Sprintf("#<%s size=%d >", e.class.Name, e.size)
+}
+
+// Inspect delegates to ToString
+func (e *ConcurrentExecutorObject) Inspect() string {
+	return e.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (e *ConcurrentExecutorObject) ToJSON(t *Thread) string {
+	return jsonString(e.ToString())
+}
+
+// resolve stores the result (or the error) of the future; only the first call takes effect
+func (f *ConcurrentFutureObject) resolve(result Object) {
+	f.once.Do(func() {
+		if err, ok := result.(*Error); ok {
+			f.err = err
+		} else {
+			f.result = result
+		}
+
+		close(f.done)
+	})
+}
+
+// Value returns the object
+func (f *ConcurrentFutureObject) Value() interface{} {
+	return f.done
+}
+
+// ToString returns the object's name as the string format
+func (f *ConcurrentFutureObject) ToString() string {
+	return "#<" + f.class.Name + " >"
+}
+
+// Inspect delegates to ToString
+func (f *ConcurrentFutureObject) Inspect() string {
+	return f.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (f *ConcurrentFutureObject) ToJSON(t *Thread) string {
+	return jsonString(f.ToString())
+}
+
+// Other helper functions -----------------------------------------------
+
+// yieldOnNewThread evaluates the block on a new thread, and returns either the block's result
+// or the error raised by it
+func (vm *VM) yieldOnNewThread(blockFrame *normalCallFrame, args ...Object) (result Object) {
+	newT := vm.newThread()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(*Error)
+
+			// a true Go panic can't be handled by Goby
+			if !ok {
+				panic(r)
+			}
+
+			result = err
+		}
+	}()
+
+	return newT.builtinMethodYield(blockFrame, args...)
+}
+
+func (vm *VM) futuresFromArgs(args []Object, sourceLine int) ([]*ConcurrentFutureObject, *Error) {
+	if len(args) != 1 {
+		return nil, vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	arr, ok := args[0].(*ArrayObject)
+
+	if !ok {
+		return nil, vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.ArrayClass, args[0].Class().Name)
+	}
+
+	futures := []*ConcurrentFutureObject{}
+
+	for _, elem := range arr.Elements {
+		f, ok := elem.(*ConcurrentFutureObject)
+
+		if !ok {
+			return nil, vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Concurrent::Future", elem.Class().Name)
+		}
+
+		futures = append(futures, f)
+	}
+
+	return futures, nil
+}
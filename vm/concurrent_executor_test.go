@@ -0,0 +1,236 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+func TestExecutorSubmitMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(2)
+		future = executor.submit do
+		  10
+		end
+		future.value
+		`, 10},
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(2)
+		future = executor.submit(3, 4) do |a, b|
+		  a * b
+		end
+		future.value
+		`, 12},
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(1)
+		future = executor.submit do
+		  sleep(1)
+		  10
+		end
+		future.value(0.1)
+		`, nil},
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(1)
+		future = executor.submit do
+		  10
+		end
+		future.value
+		future.completed?
+		`, true},
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(3)
+		executor.size
+		`, 3},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestExecutorSubmitMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		require 'concurrent/executor'
+		Concurrent::Executor.new(0)
+		`, "ArgumentError: Expect argument to be positive value. got: 0", 1},
+		{`
+		require 'concurrent/executor'
+		Concurrent::Executor.new("2")
+		`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`
+		require 'concurrent/executor'
+		Concurrent::Executor.new(2).submit
+		`, "InternalError: Can't yield without a block", 1},
+		{`
+		require 'concurrent/executor'
+		executor = Concurrent::Executor.new(2)
+		executor.shutdown
+		executor.submit do
+		  1
+		end
+		`, "InternalError: The executor is already shut down.", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestExecutorShutdownMethod(t *testing.T) {
+	code := `
+	require 'concurrent/executor'
+
+	executor = Concurrent::Executor.new(2)
+	futures = []
+	5.times do |i|
+	  future = executor.submit(i) do |n|
+	    sleep(0.1)
+	    n
+	  end
+	  futures.push(future)
+	end
+	executor.shutdown
+	completed = futures.select do |future|
+	  future.completed?
+	end
+	[completed.length, executor.shutdown?]
+	`
+
+	v := initTestVM()
+	evaluated := v.testEval(t, code, getFilename())
+	verifyArrayObject(t, 0, evaluated, []interface{}{5, true})
+	v.checkCFP(t, 0, 0)
+	v.checkSP(t, 0, 1)
+}
+
+func TestFutureValueMethodFail(t *testing.T) {
+	v := initTestVM()
+	initConcurrentExecutorClass(v)
+
+	// Resolves the future here, as errors raised by the blocks on the executor's threads read the main thread's call frames
+	future := v.initConcurrentFutureObject()
+	future.resolve(v.InitErrorObject(errors.ArgumentError, 1, "%s", "failed"))
+	v.objectClass.setConstant("FailedFuture", &Pointer{Target: future})
+
+	evaluated := v.testEval(t, `FailedFuture.value`, getFilename())
+	checkErrorMsg(t, 0, evaluated, "ArgumentError: failed")
+	v.checkCFP(t, 0, 1)
+	v.checkSP(t, 0, 1)
+}
+
+func TestFutureThenMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(2)
+		future = executor.submit do
+		  10
+		end
+		future.then do |v|
+		  v * 2
+		end.then do |v|
+		  v + 1
+		end.value
+		`, 21},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFutureAllAndAnyMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(4)
+		futures = [1, 2, 3].map do |i|
+		  executor.submit(i) do |n|
+		    n * 2
+		  end
+		end
+		Concurrent::Future.all(futures).value
+		`, []interface{}{2, 4, 6}},
+		{`
+		require 'concurrent/executor'
+
+		executor = Concurrent::Executor.new(2)
+		slow = executor.submit do
+		  sleep(1)
+		  "slow"
+		end
+		fast = executor.submit do
+		  "fast"
+		end
+		Concurrent::Future.any([slow, fast]).value
+		`, "fast"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFutureAllMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		require 'concurrent/executor'
+		Concurrent::Future.all([1])
+		`, "TypeError: Expect argument to be Concurrent::Future. got: Integer", 1},
+		{`
+		require 'concurrent/executor'
+		Concurrent::Future.any([])
+		`, "ArgumentError: Expect at least one future. got: 0", 1},
+		{`
+		require 'concurrent/executor'
+		Concurrent::Future.new
+		`, "NoMethodError: Undefined Method 'new' for Future", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -255,7 +255,7 @@ func (lock *ConcurrentRWLockObject) Inspect() string {
 	return lock.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (lock *ConcurrentRWLockObject) ToJSON(t *Thread) string {
-	return lock.ToString()
+	return jsonString(lock.ToString())
 }
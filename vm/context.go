@@ -0,0 +1,378 @@
+package vm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// ContextObject wraps Go's `context.Context`, and is used to cancel blocking operations.
+// A context can be passed as the last argument to `sleep`, `Channel#receive`, `Net::HTTP::Client#exec`
+// and `DB#query`/`DB#exec`/`DB#run`; these methods raise a `ContextError` once the context is cancelled
+// or its deadline is exceeded.
+//
+// Contexts derived from another context (via the instance methods `with_cancel`, `with_timeout` and
+// `with_deadline`) are cancelled when their parent is cancelled.
+//
+// ```ruby
+// ctx = Context.with_timeout(2)
+//
+// Net::HTTP.start do |client|
+//   req = client.request
+//   req.url = "http://example.com"
+//   req.method = "GET"
+//   client.exec(req, ctx) # raises ContextError if the request takes more than 2 seconds
+// end
+// ```
+//
+type ContextObject struct {
+	*BaseObj
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Class methods --------------------------------------------------------
+var builtinContextClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns an empty context that is never cancelled.
+		//
+		// ```ruby
+		// Context.background.done? # => false
+		// ```
+		//
+		// @return [Context]
+		Name: "background",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.initContextObject(context.Background(), func() {})
+
+		},
+	},
+	{
+		// Returns a new context that is cancelled when `cancel` is called.
+		//
+		// ```ruby
+		// ctx = Context.with_cancel
+		// ctx.cancel
+		// ctx.done? # => true
+		// ```
+		//
+		// @return [Context]
+		Name: "with_cancel",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return contextWithCancel(t, context.Background(), sourceLine, args)
+
+		},
+	},
+	{
+		// Returns a new context that is cancelled after the given seconds (Integer or Float).
+		//
+		// ```ruby
+		// ctx = Context.with_timeout(0.5)
+		// sleep(1, ctx) # => ContextError: context deadline exceeded
+		// ```
+		//
+		// @param seconds [Numeric]
+		// @return [Context]
+		Name: "with_timeout",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return contextWithTimeout(t, context.Background(), sourceLine, args)
+
+		},
+	},
+	{
+		// Returns a new context that is cancelled at the given Unix time in seconds (Integer or Float).
+		//
+		// ```ruby
+		// ctx = Context.with_deadline(1893456000)
+		// ```
+		//
+		// @param time [Numeric]
+		// @return [Context]
+		Name: "with_deadline",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return contextWithDeadline(t, context.Background(), sourceLine, args)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinContextInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Cancels the context and all the contexts derived from it. Calling `cancel` twice is allowed.
+		//
+		// ```ruby
+		// ctx = Context.with_cancel
+		// ctx.cancel
+		// ctx.err # => "context canceled"
+		// ```
+		//
+		// @return [Null]
+		Name: "cancel",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			receiver.(*ContextObject).cancel()
+
+			return NULL
+
+		},
+	},
+	{
+		// Returns the context's deadline in Unix time (Float), or `nil` if the context has no deadline.
+		//
+		// ```ruby
+		// Context.with_deadline(1893456000).deadline # => 1893456000.0
+		// Context.background.deadline                # => nil
+		// ```
+		//
+		// @return [Float]
+		Name: "deadline",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			deadline, ok := receiver.(*ContextObject).ctx.Deadline()
+
+			if !ok {
+				return NULL
+			}
+
+			return t.vm.initFloatObject(float64(deadline.UnixNano()) / float64(time.Second))
+
+		},
+	},
+	{
+		// Returns `true` if the context is cancelled or its deadline is exceeded.
+		//
+		// ```ruby
+		// ctx = Context.with_cancel
+		// ctx.done? # => false
+		// ctx.cancel
+		// ctx.done? # => true
+		// ```
+		//
+		// @return [Boolean]
+		Name: "done?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return toBooleanObject(receiver.(*ContextObject).ctx.Err() != nil)
+
+		},
+	},
+	{
+		// Returns the reason why the context is done as a String, or `nil` if it isn't done yet.
+		//
+		// ```ruby
+		// ctx = Context.with_cancel
+		// ctx.err # => nil
+		// ctx.cancel
+		// ctx.err # => "context canceled"
+		// ```
+		//
+		// @return [String]
+		Name: "err",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			err := receiver.(*ContextObject).ctx.Err()
+
+			if err == nil {
+				return NULL
+			}
+
+			return t.vm.InitStringObject(err.Error())
+
+		},
+	},
+	{
+		// Blocks until the context is done, then returns the receiver.
+		//
+		// ```ruby
+		// ctx = Context.with_timeout(1)
+		// ctx.wait
+		// ctx.done? # => true
+		// ```
+		//
+		// @return [Context]
+		Name: "wait",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			<-receiver.(*ContextObject).ctx.Done()
+
+			return receiver
+
+		},
+	},
+	{
+		// Returns a child context that is cancelled when `cancel` is called or the receiver is cancelled.
+		//
+		// ```ruby
+		// parent = Context.with_timeout(10)
+		// child = parent.with_cancel
+		// ```
+		//
+		// @return [Context]
+		Name: "with_cancel",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return contextWithCancel(t, receiver.(*ContextObject).ctx, sourceLine, args)
+
+		},
+	},
+	{
+		// Returns a child context that is cancelled after the given seconds or when the receiver is cancelled.
+		//
+		// ```ruby
+		// parent = Context.with_cancel
+		// child = parent.with_timeout(5)
+		// ```
+		//
+		// @param seconds [Numeric]
+		// @return [Context]
+		Name: "with_timeout",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return contextWithTimeout(t, receiver.(*ContextObject).ctx, sourceLine, args)
+
+		},
+	},
+	{
+		// Returns a child context that is cancelled at the given Unix time or when the receiver is cancelled.
+		//
+		// ```ruby
+		// parent = Context.with_cancel
+		// child = parent.with_deadline(1893456000)
+		// ```
+		//
+		// @param time [Numeric]
+		// @return [Context]
+		Name: "with_deadline",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return contextWithDeadline(t, receiver.(*ContextObject).ctx, sourceLine, args)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initContextObject(ctx context.Context, cancel context.CancelFunc) *ContextObject {
+	return &ContextObject{
+		BaseObj: NewBaseObject(vm.TopLevelClass(classes.ContextClass)),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+func (vm *VM) initContextClass() *RClass {
+	class := vm.initializeClass(classes.ContextClass)
+	class.setBuiltinMethods(builtinContextClassMethods, true)
+	class.setBuiltinMethods(builtinContextInstanceMethods, false)
+	return class
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Value returns the Go context
+func (c *ContextObject) Value() interface{} {
+	return c.ctx
+}
+
+// ToString returns the object's name as the string format
+func (c *ContextObject) ToString() string {
+	return fmt.Sprintf("#<%s:%d >", c.class.Name, c.ID())
+}
+
+// Inspect delegates to ToString
+func (c *ContextObject) Inspect() string {
+	return c.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (c *ContextObject) ToJSON(t *Thread) string {
+	return jsonString(c.ToString())
+}
+
+// Other helper functions -----------------------------------------------
+
+func contextWithCancel(t *Thread, parent context.Context, sourceLine int, args []Object) Object {
+	if len(args) != 0 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	return t.vm.initContextObject(ctx, cancel)
+}
+
+func contextWithTimeout(t *Thread, parent context.Context, sourceLine int, args []Object) Object {
+	if len(args) != 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	seconds, ok := args[0].(Numeric)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+	}
+
+	ctx, cancel := context.WithTimeout(parent, secondsToDuration(seconds))
+	return t.vm.initContextObject(ctx, cancel)
+}
+
+func contextWithDeadline(t *Thread, parent context.Context, sourceLine int, args []Object) Object {
+	if len(args) != 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	unixTime, ok := args[0].(Numeric)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+	}
+
+	deadline := time.Unix(0, 0).Add(secondsToDuration(unixTime))
+	ctx, cancel := context.WithDeadline(parent, deadline)
+	return t.vm.initContextObject(ctx, cancel)
+}
+
+// contextFromArg returns the Go context held by the given argument, which must be a Context object
+func (vm *VM) contextFromArg(arg Object, sourceLine int) (context.Context, *Error) {
+	c, ok := arg.(*ContextObject)
+
+	if !ok {
+		return nil, vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.ContextClass, arg.Class().Name)
+	}
+
+	return c.ctx, nil
+}
+
+// initContextError returns the error raised when an operation is aborted by the given context
+func (vm *VM) initContextError(ctx context.Context, sourceLine int) *Error {
+	return vm.InitErrorObject(errors.ContextError, sourceLine, ctx.Err().Error())
+}
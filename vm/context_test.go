@@ -0,0 +1,151 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestContextClassSuperclass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`Context.class.name`, "Class"},
+		{`Context.superclass.name`, "Object"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestContextCancelMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Context.background.done?`, false},
+		{`Context.background.err`, nil},
+		{`Context.background.deadline`, nil},
+		{`Context.with_cancel.done?`, false},
+		{`
+		ctx = Context.with_cancel
+		ctx.cancel
+		ctx.done?
+		`, true},
+		{`
+		ctx = Context.with_cancel
+		ctx.cancel
+		ctx.cancel
+		ctx.err
+		`, "context canceled"},
+		{`
+		parent = Context.with_cancel
+		child = parent.with_timeout(10)
+		parent.cancel
+		child.err
+		`, "context canceled"},
+		{`
+		parent = Context.with_timeout(10)
+		child = parent.with_cancel
+		child.cancel
+		parent.done?
+		`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestContextTimeoutAndDeadlineMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Context.with_timeout(0.1).wait.err`, "context deadline exceeded"},
+		{`Context.with_deadline(0).err`, "context deadline exceeded"},
+		{`Context.with_deadline(1893456000).deadline`, 1893456000.0},
+		{`Context.with_cancel.with_deadline(1893456000).done?`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestContextMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Context.new`, "NoMethodError: Undefined Method 'new' for Context", 1},
+		{`Context.with_timeout`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`Context.with_timeout("1")`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`Context.with_deadline(nil)`, "TypeError: Expect argument to be Numeric. got: Null", 1},
+		{`Context.with_cancel(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`Context.background.cancel(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSleepWithContextFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`sleep(5, Context.with_timeout(0.1))`, "ContextError: context deadline exceeded", 1},
+		{`
+		ctx = Context.with_cancel
+		ctx.cancel
+		sleep(5, ctx)
+		`, "ContextError: context canceled", 1},
+		{`sleep(1, 2)`, "TypeError: Expect argument to be Context. got: Integer", 1},
+		{`sleep(1, 2, 3)`, "ArgumentError: Expect 1 to 2 argument(s). got: 3", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHTTPClientExecWithContextFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		require "net/http"
+
+		ctx = Context.with_cancel
+		ctx.cancel
+
+		Net::HTTP.start do |client|
+			r = client.request()
+			r.url = "http://127.0.0.1:3000/index"
+			r.method = "GET"
+			client.exec(r, ctx)
+		end
+		`, "ContextError: context canceled", 4},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+	}
+}
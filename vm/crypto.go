@@ -0,0 +1,433 @@
+package vm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Crypto provides authenticated encryption, key derivation and password hashing with Go's `crypto` packages
+// and `golang.org/x/crypto`. Keys, nonces and encrypted data are the raw bytes held in binary Strings,
+// which can be encoded with the `base64` or `hex` libraries for storing.
+//
+// ```ruby
+// require "crypto"
+//
+// key = SecureRandom.bytes(32)
+// encrypted = Crypto::AES.encrypt("secret", key)
+// Crypto::AES.decrypt(encrypted, key) # => "secret"
+//
+// salt = SecureRandom.bytes(16)
+// key = Crypto.pbkdf2("password", salt, { iterations: 100000, length: 32 })
+//
+// digest = Crypto.bcrypt("password")
+// Crypto.bcrypt_verify("password", digest) # => true
+// ```
+//
+// `require "crypto"` also loads `SecureRandom`.
+//
+// - `Crypto.new` is not supported.
+
+const (
+	// maxKeyLength is the longest key in bytes the key derivation functions derive
+	maxKeyLength = 1 << 20
+	// maxScryptMemory is the most memory in bytes scrypt is allowed to use, which is 128 * n * r
+	maxScryptMemory = 1 << 30
+)
+
+// Class methods --------------------------------------------------------
+var builtinCryptoClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the bcrypt hash of the password, which contains the salt and the cost.
+		// The cost is 10 by default, and between 4 and 31.
+		//
+		// ```ruby
+		// Crypto.bcrypt("password", { cost: 12 }) # => "$2a$12$..."
+		// ```
+		//
+		// @param password [String], options [Hash]
+		// @return [String]
+		Name: "bcrypt",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			password, ok := args[0].(*StringObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.StringClass, args[0].Class().Name)
+			}
+
+			options, err := cryptoOptionsOf(t, args[1:], map[string]Object{
+				"cost": t.vm.InitIntegerObject(bcrypt.DefaultCost),
+			}, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			cost, err := options["cost"].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			// GenerateFromPassword falls back to the default cost if the cost is too small
+			if cost < bcrypt.MinCost {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, strings.TrimPrefix(bcrypt.InvalidCostError(cost).Error(), "crypto/bcrypt: "))
+			}
+
+			hashed, bcryptErr := bcrypt.GenerateFromPassword([]byte(password.value), cost)
+
+			if bcryptErr != nil {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, strings.TrimPrefix(bcryptErr.Error(), "crypto/bcrypt: "))
+			}
+
+			return t.vm.InitStringObject(string(hashed))
+
+		},
+	},
+	{
+		// Returns true if the password matches the bcrypt hash.
+		//
+		// ```ruby
+		// Crypto.bcrypt_verify("password", Crypto.bcrypt("password")) # => true
+		// ```
+		//
+		// @param password [String], hash [String]
+		// @return [Boolean]
+		Name: "bcrypt_verify",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			password, hashed := args[0].(*StringObject).value, args[1].(*StringObject).value
+			return toBooleanObject(bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)) == nil)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Derives a key from the password and the salt with PBKDF2.
+		// The options are `iterations` (600000 by default), `length` of the key in bytes (32 by default),
+		// and `digest`, which is "SHA256" by default and can be any algorithm of the `digest` library.
+		//
+		// ```ruby
+		// Crypto.pbkdf2("password", salt, { iterations: 210000, digest: "SHA512", length: 64 })
+		// ```
+		//
+		// @param password [String], salt [String], options [Hash]
+		// @return [String]
+		Name: "pbkdf2",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			password, salt, err := passwordAndSaltOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			options, err := cryptoOptionsOf(t, args[2:], map[string]Object{
+				"iterations": t.vm.InitIntegerObject(600000),
+				"length":     t.vm.InitIntegerObject(32),
+				"digest":     t.vm.InitStringObject("SHA256"),
+			}, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			digest := options["digest"].(*StringObject).value
+			fn, ok := digestAlgorithms[strings.ToLower(digest)]
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownDigest, digest)
+			}
+
+			values, err := positiveOptionsOf(t, options, sourceLine, "iterations", "length")
+
+			if err != nil {
+				return err
+			}
+
+			iterations, length := values[0], values[1]
+
+			if length > maxKeyLength {
+				return t.vm.InitErrorObject(errors.RangeError, sourceLine, errors.KeyTooLong, maxKeyLength, length)
+			}
+
+			return t.vm.initBinaryStringObject(string(pbkdf2.Key([]byte(password), []byte(salt), iterations, length, fn)))
+
+		},
+	},
+	{
+		// Derives a key from the password and the salt with scrypt. The options are the cost parameters
+		// `n` (32768 by default), `r` (8 by default) and `p` (1 by default), and `length` of the key in bytes (32 by default).
+		//
+		// ```ruby
+		// Crypto.scrypt("password", salt, { n: 65536, length: 64 })
+		// ```
+		//
+		// @param password [String], salt [String], options [Hash]
+		// @return [String]
+		Name: "scrypt",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			password, salt, err := passwordAndSaltOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			options, err := cryptoOptionsOf(t, args[2:], map[string]Object{
+				"n":      t.vm.InitIntegerObject(32768),
+				"r":      t.vm.InitIntegerObject(8),
+				"p":      t.vm.InitIntegerObject(1),
+				"length": t.vm.InitIntegerObject(32),
+			}, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			values, err := positiveOptionsOf(t, options, sourceLine, "n", "r", "p", "length")
+
+			if err != nil {
+				return err
+			}
+
+			n, r, p, length := values[0], values[1], values[2], values[3]
+
+			if length > maxKeyLength {
+				return t.vm.InitErrorObject(errors.RangeError, sourceLine, errors.KeyTooLong, maxKeyLength, length)
+			}
+
+			// Checked by division, so that 128 * n * r can't overflow
+			if n > maxScryptMemory/128/r {
+				return t.vm.InitErrorObject(errors.RangeError, sourceLine, errors.ScryptMemoryTooBig, maxScryptMemory, n, r)
+			}
+
+			key, scryptErr := scrypt.Key([]byte(password), []byte(salt), n, r, p, length)
+
+			if scryptErr != nil {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, strings.TrimPrefix(scryptErr.Error(), "scrypt: "))
+			}
+
+			return t.vm.initBinaryStringObject(string(key))
+
+		},
+	},
+}
+
+// Class methods of AES -------------------------------------------------
+var builtinCryptoAESClassMethods = []*BuiltinMethodObject{
+	{
+		// Decrypts and verifies the data encrypted by `encrypt` with the same key and `aad` option.
+		// It raises `Crypto::DecryptionError` if the key is wrong or the data has been tampered with.
+		//
+		// ```ruby
+		// Crypto::AES.decrypt(encrypted, key) # => "secret"
+		// ```
+		//
+		// @param encrypted [String], key [String], options [Hash]
+		// @return [String]
+		Name: "decrypt",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			gcm, data, aad, err := aesGCMOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if len(data) < gcm.NonceSize()+gcm.Overhead() {
+				return t.vm.InitErrorObject(errors.DecryptionError, sourceLine, "encrypted data is too short")
+			}
+
+			nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+			plaintext, openErr := gcm.Open(nil, nonce, ciphertext, aad)
+
+			if openErr != nil {
+				return t.vm.InitErrorObject(errors.DecryptionError, sourceLine, "message authentication failed")
+			}
+
+			return t.vm.initBinaryStringObject(string(plaintext))
+
+		},
+	},
+	{
+		// Encrypts the String with AES-GCM. The key is 16, 24 or 32 bytes for AES-128, AES-192 or AES-256.
+		// A random nonce is generated for each call and prepended to the encrypted data.
+		// The `aad` option is additional data that's authenticated but not encrypted.
+		//
+		// ```ruby
+		// key = SecureRandom.bytes(32)
+		// encrypted = Crypto::AES.encrypt("secret", key, { aad: "user:1" })
+		// ```
+		//
+		// @param plaintext [String], key [String], options [Hash]
+		// @return [String]
+		Name: "encrypt",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			gcm, plaintext, aad, err := aesGCMOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			nonce := make([]byte, gcm.NonceSize())
+
+			if _, randErr := rand.Read(nonce); randErr != nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, randErr.Error())
+			}
+
+			return t.vm.initBinaryStringObject(string(gcm.Seal(nonce, nonce, plaintext, aad)))
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initCryptoClass(vm *VM) {
+	class := vm.initializeClass("Crypto")
+	class.setBuiltinMethods(builtinCryptoClassMethods, true)
+
+	aesClass := vm.initializeClass("AES")
+	aesClass.setBuiltinMethods(builtinCryptoAESClassMethods, true)
+	class.setClassConstant(aesClass)
+
+	class.setClassConstant(vm.initializeClass("DecryptionError"))
+	vm.objectClass.setClassConstant(class)
+
+	initSecureRandomClass(vm)
+}
+
+// Other helper functions -----------------------------------------------
+
+// aesGCMOf returns the AES-GCM cipher of the key, the data and the `aad` option of the arguments
+func aesGCMOf(t *Thread, args []Object, sourceLine int) (cipher.AEAD, []byte, []byte, *Error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, nil, nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 2, 3, len(args))
+	}
+
+	for i, arg := range args[:2] {
+		if _, ok := arg.(*StringObject); !ok {
+			return nil, nil, nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.StringClass, arg.Class().Name)
+		}
+	}
+
+	options, err := cryptoOptionsOf(t, args[2:], map[string]Object{
+		"aad": t.vm.InitStringObject(""),
+	}, sourceLine)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	block, cipherErr := aes.NewCipher([]byte(args[1].(*StringObject).value))
+
+	if cipherErr != nil {
+		return nil, nil, nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, strings.TrimPrefix(cipherErr.Error(), "crypto/aes: "))
+	}
+
+	gcm, _ := cipher.NewGCM(block)
+	aad := []byte(options["aad"].(*StringObject).value)
+
+	return gcm, []byte(args[0].(*StringObject).value), aad, nil
+}
+
+// passwordAndSaltOf returns the password and the salt of the key derivation functions
+func passwordAndSaltOf(t *Thread, args []Object, sourceLine int) (string, string, *Error) {
+	if len(args) < 2 || len(args) > 3 {
+		return "", "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 2, 3, len(args))
+	}
+
+	for i, arg := range args[:2] {
+		if _, ok := arg.(*StringObject); !ok {
+			return "", "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.StringClass, arg.Class().Name)
+		}
+	}
+
+	return args[0].(*StringObject).value, args[1].(*StringObject).value, nil
+}
+
+// positiveOptionsOf returns the values of the given Integer options, which must be positive
+func positiveOptionsOf(t *Thread, options map[string]Object, sourceLine int, names ...string) ([]int, *Error) {
+	values := make([]int, len(names))
+
+	for i, name := range names {
+		value, err := options[name].(*IntegerObject).intValue(t, sourceLine)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if value <= 0 {
+			return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, value)
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// cryptoOptionsOf returns the options given as the optional Hash argument, merged into the defaults.
+// Each option must be of the same class as its default value.
+func cryptoOptionsOf(t *Thread, args []Object, defaults map[string]Object, sourceLine int) (map[string]Object, *Error) {
+	if len(args) == 0 {
+		return defaults, nil
+	}
+
+	h, ok := args[0].(*HashObject)
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+	}
+
+	for _, key := range h.sortedKeys() {
+		defaultValue, ok := defaults[key]
+
+		if !ok {
+			return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+		}
+
+		value := h.Pairs[key]
+
+		if value.Class() != defaultValue.Class() {
+			return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, defaultValue.Class().Name, value.Class().Name)
+		}
+
+		defaults[key] = value
+	}
+
+	return defaults, nil
+}
@@ -0,0 +1,129 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestCryptoAES(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "crypto"
+		key = SecureRandom.bytes(32)
+		Crypto::AES.decrypt(Crypto::AES.encrypt("secret", key), key)
+		`, "secret"},
+		{`
+		require "crypto"
+		key = "0123456789abcdef"
+		Crypto::AES.decrypt(Crypto::AES.encrypt("", key, { aad: "user:1" }), key, { aad: "user:1" })
+		`, ""},
+		{`
+		require "crypto"
+		key = SecureRandom.bytes(24)
+		Crypto::AES.encrypt("secret", key) == Crypto::AES.encrypt("secret", key)
+		`, false},
+		{`
+		require "crypto"
+		require "hex"
+		Hex.encode(Crypto::AES.encrypt("secret", SecureRandom.bytes(32))).length
+		`, 68},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCryptoKeyDerivation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "crypto"
+		require "hex"
+		Hex.encode(Crypto.pbkdf2("password", "salt", { iterations: 1, length: 20, digest: "SHA1" }))
+		`, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{`
+		require "crypto"
+		require "hex"
+		Hex.encode(Crypto.pbkdf2("password", "salt", { iterations: 2, length: 32 }))
+		`, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{`
+		require "crypto"
+		require "hex"
+		Hex.encode(Crypto.scrypt("password", "NaCl", { n: 1024, r: 8, p: 16, length: 64 }))
+		`, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+		{`
+		require "crypto"
+		digest = Crypto.bcrypt("password", { cost: 4 })
+		[digest.split("$")[2], Crypto.bcrypt_verify("password", digest), Crypto.bcrypt_verify("Password", digest)]
+		`, []interface{}{"04", true, false}},
+		{`
+		require "crypto"
+		Crypto.bcrypt_verify("password", "not a hash")
+		`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCryptoMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "crypto";Crypto.new`, "NoMethodError: Undefined Method 'new' for Crypto", 1},
+		{`require "crypto";Crypto::AES.new`, "NoMethodError: Undefined Method 'new' for AES", 1},
+		{`require "crypto";Crypto::AES.encrypt("a")`, "ArgumentError: Expect 2 to 3 argument(s). got: 1", 1},
+		{`require "crypto";Crypto::AES.encrypt("a", 1)`, "TypeError: Expect argument #2 to be String. got: Integer", 1},
+		{`require "crypto";Crypto::AES.encrypt("a", "short")`, "ArgumentError: invalid key size 5", 1},
+		{`require "crypto";Crypto::AES.encrypt("a", "0123456789abcdef", { aad: 1 })`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "crypto";Crypto::AES.encrypt("a", "0123456789abcdef", { nonce: "x" })`, "ArgumentError: Unknown option - nonce", 1},
+		{`require "crypto";Crypto::AES.decrypt("a", "0123456789abcdef")`, "Crypto::DecryptionError: encrypted data is too short", 1},
+		{`
+		require "crypto"
+		encrypted = Crypto::AES.encrypt("secret", "0123456789abcdef")
+		Crypto::AES.decrypt(encrypted, "fedcba9876543210")
+		`, "Crypto::DecryptionError: message authentication failed", 1},
+		{`
+		require "crypto"
+		encrypted = Crypto::AES.encrypt("secret", "0123456789abcdef", { aad: "user:1" })
+		Crypto::AES.decrypt(encrypted, "0123456789abcdef", { aad: "user:2" })
+		`, "Crypto::DecryptionError: message authentication failed", 1},
+		{`require "crypto";Crypto.pbkdf2("password")`, "ArgumentError: Expect 2 to 3 argument(s). got: 1", 1},
+		{`require "crypto";Crypto.pbkdf2("password", nil)`, "TypeError: Expect argument #2 to be String. got: Null", 1},
+		{`require "crypto";Crypto.pbkdf2("password", "salt", { digest: "SHA3" })`, "ArgumentError: Unknown digest algorithm - SHA3", 1},
+		{`require "crypto";Crypto.pbkdf2("password", "salt", { iterations: 0 })`, "ArgumentError: Expect argument to be positive value. got: 0", 1},
+		{`require "crypto";Crypto.scrypt("password", "salt", { length: -1 })`, "ArgumentError: Expect argument to be positive value. got: -1", 1},
+		{`require "crypto";Crypto.scrypt("password", "salt", { n: 1000 })`, "ArgumentError: N must be > 1 and a power of 2", 1},
+		{`require "crypto";Crypto.pbkdf2("password", "salt", { length: 4611686018427387903 })`, "RangeError: Key length must be 1048576 or less. got: 4611686018427387903", 1},
+		{`require "crypto";Crypto.pbkdf2("password", "salt", { length: 2 ** 64 })`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`require "crypto";Crypto.pbkdf2("password", "salt", { iterations: 2 ** 64 })`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`require "crypto";Crypto.scrypt("password", "salt", { length: 4611686018427387903 })`, "RangeError: Key length must be 1048576 or less. got: 4611686018427387903", 1},
+		{`require "crypto";Crypto.scrypt("password", "salt", { n: 2 ** 64 })`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`require "crypto";Crypto.scrypt("password", "salt", { n: 2 ** 40 })`, "RangeError: scrypt can't use more than 1073741824 bytes of memory. got n: 1099511627776, r: 8", 1},
+		{`require "crypto";Crypto.scrypt("password", "salt", { r: 4611686018427387903 })`, "RangeError: scrypt can't use more than 1073741824 bytes of memory. got n: 32768, r: 4611686018427387903", 1},
+		{`require "crypto";Crypto.scrypt("password", "salt", { p: 0 })`, "ArgumentError: Expect argument to be positive value. got: 0", 1},
+		{`require "crypto";Crypto.bcrypt("password", { cost: 3 })`, "ArgumentError: cost 3 is outside allowed range (4,31)", 1},
+		{`require "crypto";Crypto.bcrypt("password", { cost: "4" })`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`require "crypto";Crypto.bcrypt_verify("password")`, "ArgumentError: Expect 2 argument(s). got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
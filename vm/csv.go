@@ -0,0 +1,533 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// CSV reads and writes comma-separated values. The implementation internally uses Go's `encoding/csv` package.
+//
+// ```ruby
+// require "csv"
+//
+// CSV.parse("name,age\nStan,30\n")                      # => [["name", "age"], ["Stan", "30"]]
+// CSV.parse("name,age\nStan,30\n", { headers: true })   # => [{ name: "Stan", age: "30" }]
+//
+// CSV.foreach("users.tsv", { col_sep: "\t", headers: true }) do |user|
+//   puts(user["name"])
+// end
+//
+// CSV.generate({ headers: ["name", "note"] }) do |csv|
+//   csv << { name: "Stan", note: "says \"hi\", twice" }
+// end
+// # => "name,note\nStan,\"says \"\"hi\"\", twice\"\n"
+// ```
+//
+// Options:
+//
+// - `col_sep`: the column separator, which is "," by default
+// - `headers`: when reading, true maps the rows to Hashes whose keys are the fields of the first row.
+//   When writing, an Array of the header fields, which are written first and used for picking the values of Hash rows
+// - `liberal_parsing`: when reading, allows quotes in unquoted fields and non-doubled quotes in quoted fields
+//
+// Malformed CSV raises `CSV::MalformedCSVError`.
+//
+// - `CSV.new` is not supported.
+
+// CSVWriterObject writes the rows given by `<<` in the block of `CSV.generate`.
+type CSVWriterObject struct {
+	*BaseObj
+	writer  *csv.Writer
+	buffer  *bytes.Buffer
+	headers []string
+}
+
+// csvOptions are the options of reading and writing CSV
+type csvOptions struct {
+	colSep         rune
+	headers        bool
+	headerFields   []string
+	liberalParsing bool
+}
+
+// Class methods --------------------------------------------------------
+var builtinCSVClassMethods = []*BuiltinMethodObject{
+	{
+		// Yields each row of the CSV file, which is read one row at a time.
+		//
+		// ```ruby
+		// CSV.foreach("report.csv", { headers: true }) do |row|
+		//   total += row["amount"].to_i
+		// end
+		// ```
+		//
+		// @param path [String], options [Hash]
+		// @return [Null]
+		Name: "foreach",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			path, ok := args[0].(*StringObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.StringClass, args[0].Class().Name)
+			}
+
+			options, err := csvOptionsOf(t, args[1:], false, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			f, openErr := os.Open(path.value)
+
+			if openErr != nil {
+				t.callFrameStack.pop()
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, openErr.Error())
+			}
+
+			defer f.Close()
+
+			yielded := false
+
+			err = t.readCSV(f, options, sourceLine, func(row Object) {
+				yielded = true
+				t.builtinMethodYield(blockFrame, row)
+			})
+
+			if !yielded {
+				t.callFrameStack.pop()
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return NULL
+
+		},
+	},
+	{
+		// Yields a `CSV::Writer` to the block, and returns the CSV written by `<<`.
+		//
+		// ```ruby
+		// CSV.generate({ col_sep: ";" }) do |csv|
+		//   csv << ["a", nil, 1]
+		// end
+		// # => "a;;1\n"
+		// ```
+		//
+		// @param options [Hash]
+		// @return [String]
+		Name: "generate",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			options, err := csvOptionsOf(t, args, true, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			w := t.vm.initCSVWriterObject(options)
+
+			if len(w.headers) > 0 {
+				w.writer.Write(w.headers)
+			}
+
+			t.builtinMethodYield(blockFrame, w)
+			w.writer.Flush()
+
+			return t.vm.InitStringObject(w.buffer.String())
+
+		},
+	},
+	{
+		// Returns a line of CSV of the fields.
+		//
+		// ```ruby
+		// CSV.generate_line(["foo", "bar, baz"]) # => "foo,\"bar, baz\"\n"
+		// ```
+		//
+		// @param fields [Array], options [Hash]
+		// @return [String]
+		Name: "generate_line",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			options, err := csvOptionsOf(t, args[1:], true, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			w := t.vm.initCSVWriterObject(options)
+
+			if err := w.writeRow(t, args[0], sourceLine); err != nil {
+				return err
+			}
+
+			w.writer.Flush()
+			return t.vm.InitStringObject(w.buffer.String())
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Returns the rows of the CSV string as Arrays, or Hashes with the `headers` option.
+		// A File or a StringIO is read from the current position too.
+		// With a block, yields each row instead and returns nil.
+		//
+		// ```ruby
+		// CSV.parse("a,b\n1,2\n") # => [["a", "b"], ["1", "2"]]
+		// CSV.parse("a,b\n1,2\n", { headers: true }) do |row|
+		//   row["b"] # => "2"
+		// end
+		// ```
+		//
+		// @param csv [String, File, StringIO], options [Hash]
+		// @return [Array]
+		Name: "parse",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			var r io.Reader
+
+			switch source := args[0].(type) {
+			case *StringObject:
+				r = strings.NewReader(source.value)
+			case *FileObject:
+				r = source.bufReader()
+			case *StringIOObject:
+				if err := source.checkOpen(t, sourceLine); err != nil {
+					return err
+				}
+
+				r = source
+			default:
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, "String, File or StringIO", args[0].Class().Name)
+			}
+
+			options, err := csvOptionsOf(t, args[1:], false, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			rows := []Object{}
+			yielded := false
+
+			err = t.readCSV(r, options, sourceLine, func(row Object) {
+				if blockFrame != nil {
+					yielded = true
+					t.builtinMethodYield(blockFrame, row)
+				} else {
+					rows = append(rows, row)
+				}
+			})
+
+			if blockFrame != nil && !yielded {
+				t.callFrameStack.pop()
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if blockFrame != nil {
+				return NULL
+			}
+
+			return t.vm.InitArrayObject(rows)
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinCSVInstanceMethods = []*BuiltinMethodObject{}
+
+// Class methods --------------------------------------------------------
+var builtinCSVWriterClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinCSVWriterInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Writes a row, which is an Array of fields or a Hash. The values of a Hash are picked by the headers,
+		// which are the Hash's sorted keys if the `headers` option isn't given. nil is written as an empty field.
+		//
+		// ```ruby
+		// CSV.generate do |csv|
+		//   csv << { name: "Stan", age: 30 }
+		//   csv << { name: "Ann" }
+		// end
+		// # => "age,name\n30,Stan\n,Ann\n"
+		// ```
+		//
+		// @param row [Array]
+		// @return [CSV::Writer]
+		Name: "<<",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			if err := receiver.(*CSVWriterObject).writeRow(t, args[0], sourceLine); err != nil {
+				return err
+			}
+
+			return receiver
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initCSVClass(vm *VM) {
+	class := vm.initializeClass("CSV")
+	class.setBuiltinMethods(builtinCSVClassMethods, true)
+	class.setBuiltinMethods(builtinCSVInstanceMethods, false)
+
+	writer := vm.initializeClass("Writer")
+	writer.setBuiltinMethods(builtinCSVWriterClassMethods, true)
+	writer.setBuiltinMethods(builtinCSVWriterInstanceMethods, false)
+	class.setClassConstant(writer)
+
+	class.setClassConstant(vm.initializeClass("MalformedCSVError"))
+	vm.objectClass.setClassConstant(class)
+}
+
+func (vm *VM) initCSVWriterObject(options *csvOptions) *CSVWriterObject {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	writer.Comma = options.colSep
+
+	return &CSVWriterObject{
+		BaseObj: NewBaseObject(vm.objectClass.getClassConstant("CSV").getClassConstant("Writer")),
+		writer:  writer,
+		buffer:  buffer,
+		headers: options.headerFields,
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the CSV written so far
+func (w *CSVWriterObject) ToString() string {
+	w.writer.Flush()
+	return w.buffer.String()
+}
+
+// Inspect returns the class name
+func (w *CSVWriterObject) Inspect() string {
+	return "#<CSV::Writer>"
+}
+
+// ToJSON returns the quoted CSV written so far
+func (w *CSVWriterObject) ToJSON(t *Thread) string {
+	return jsonString(w.ToString())
+}
+
+// Value returns the CSV written so far
+func (w *CSVWriterObject) Value() interface{} {
+	return w.ToString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// writeRow writes an Array of fields, or a Hash whose values are picked by the headers
+func (w *CSVWriterObject) writeRow(t *Thread, row Object, sourceLine int) *Error {
+	var values []Object
+
+	switch row := row.(type) {
+	case *ArrayObject:
+		values = row.Elements
+	case *HashObject:
+		if w.headers == nil {
+			w.headers = row.sortedKeys()
+			w.writer.Write(w.headers)
+		}
+
+		for _, header := range w.headers {
+			v, ok := row.Pairs[header]
+
+			if !ok {
+				v = NULL
+			}
+
+			values = append(values, v)
+		}
+	default:
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Array or Hash", row.Class().Name)
+	}
+
+	fields := []string{}
+
+	for _, v := range values {
+		if v == NULL {
+			fields = append(fields, "")
+		} else {
+			fields = append(fields, v.ToString())
+		}
+	}
+
+	if err := w.writer.Write(fields); err != nil {
+		return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	return nil
+}
+
+// readCSV reads the rows one by one, and calls the function with each row as an Array, or a Hash if the `headers`
+// option is given
+func (t *Thread) readCSV(r io.Reader, options *csvOptions, sourceLine int, fn func(Object)) *Error {
+	reader := csv.NewReader(r)
+	reader.Comma = options.colSep
+	reader.LazyQuotes = options.liberalParsing
+	// Rows can have different numbers of fields
+	reader.FieldsPerRecord = -1
+
+	var headers []string
+
+	for {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); ok {
+				return t.vm.InitErrorObject(errors.MalformedCSVError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+		}
+
+		if !options.headers {
+			fn(t.vm.initStringsArray(record))
+			continue
+		}
+
+		if headers == nil {
+			headers = record
+			continue
+		}
+
+		pairs := map[string]Object{}
+
+		for i, header := range headers {
+			if i < len(record) {
+				pairs[header] = t.vm.InitStringObject(record[i])
+			} else {
+				pairs[header] = NULL
+			}
+		}
+
+		fn(t.vm.InitHashObject(pairs))
+	}
+}
+
+// csvOptionsOf returns the options given as the optional Hash argument of reading or writing methods
+func csvOptionsOf(t *Thread, args []Object, writing bool, sourceLine int) (*csvOptions, *Error) {
+	options := &csvOptions{colSep: ','}
+
+	if len(args) == 0 {
+		return options, nil
+	}
+
+	h, ok := args[0].(*HashObject)
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+	}
+
+	for _, key := range h.sortedKeys() {
+		value := h.Pairs[key]
+
+		switch {
+		case key == "col_sep":
+			s, ok := value.(*StringObject)
+
+			if !ok {
+				return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, value.Class().Name)
+			}
+
+			r, size := utf8.DecodeRuneInString(s.value)
+
+			if size == 0 || size != len(s.value) || r == '"' || r == '\r' || r == '\n' {
+				return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidSeparator, s.value)
+			}
+
+			options.colSep = r
+		case key == "headers" && writing:
+			arr, ok := value.(*ArrayObject)
+
+			if !ok {
+				return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.ArrayClass, value.Class().Name)
+			}
+
+			options.headerFields = []string{}
+
+			for _, elem := range arr.Elements {
+				options.headerFields = append(options.headerFields, elem.ToString())
+			}
+		case key == "headers" || key == "liberal_parsing" && !writing:
+			b, ok := value.(*BooleanObject)
+
+			if !ok {
+				return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.BooleanClass, value.Class().Name)
+			}
+
+			if key == "headers" {
+				options.headers = b.value
+			} else {
+				options.liberalParsing = b.value
+			}
+		default:
+			return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,198 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestCSVParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "csv"
+		CSV.parse("name,age\nStan,30\n")
+		`, []interface{}{[]interface{}{"name", "age"}, []interface{}{"Stan", "30"}}},
+		{`
+		require "csv"
+		CSV.parse("a,\"b, c\",\"say \"\"hi\"\"\"\n\nd\n")
+		`, []interface{}{[]interface{}{"a", "b, c", "say \"hi\""}, []interface{}{"d"}}},
+		{`
+		require "csv"
+		CSV.parse("")
+		`, []interface{}{}},
+		{`
+		require "csv"
+		rows = CSV.parse("name,age\nStan,30\nAnn\n", { headers: true })
+		[rows.length, rows[0]["name"], rows[0]["age"], rows[1]["age"]]
+		`, []interface{}{2, "Stan", "30", nil}},
+		{`
+		require "csv"
+		CSV.parse("a;b\tc\n", { col_sep: ";" })
+		`, []interface{}{[]interface{}{"a", "b\tc"}}},
+		{`
+		require "csv"
+		CSV.parse("a\tb\n", { col_sep: "\t" })
+		`, []interface{}{[]interface{}{"a", "b"}}},
+		{`
+		require "csv"
+		CSV.parse("a \"b\" c,d\n", { liberal_parsing: true })
+		`, []interface{}{[]interface{}{"a \"b\" c", "d"}}},
+		{`
+		require "csv"
+		names = []
+		CSV.parse("name\nStan\nAnn\n", { headers: true }) do |row|
+		  names.push(row["name"])
+		end
+		names
+		`, []interface{}{"Stan", "Ann"}},
+		{`
+		require "csv"
+		CSV.parse("") do |row|
+		  raise("unreachable")
+		end
+		`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCSVForeach(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "csv"
+		File.open("/tmp/goby/report.csv", "w") do |f|
+		  f.write("item,amount\napple,3\nbanana,4\n")
+		end
+
+		total = 0
+		CSV.foreach("/tmp/goby/report.csv", { headers: true }) do |row|
+		  total += row["amount"].to_i
+		end
+		total
+		`, 7},
+		{`
+		require "csv"
+		File.open("/tmp/goby/report.tsv", "w") do |f|
+		  f.write("a\tb\n")
+		end
+
+		rows = []
+		CSV.foreach("/tmp/goby/report.tsv", { col_sep: "\t" }) do |row|
+		  rows.push(row)
+		end
+		rows
+		`, []interface{}{[]interface{}{"a", "b"}}},
+	}
+
+	setup()
+	defer teardown()
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCSVGenerate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "csv"
+		CSV.generate do |csv|
+		  csv << ["name", "note"]
+		  csv << ["Stan", "says \"hi\", twice"]
+		  csv << ["multi\nline", nil]
+		end
+		`, "name,note\nStan,\"says \"\"hi\"\", twice\"\n\"multi\nline\",\n"},
+		{`
+		require "csv"
+		CSV.generate({ col_sep: ";" }) do |csv|
+		  csv << ["a;b", 1, true]
+		end
+		`, "\"a;b\";1;true\n"},
+		{`
+		require "csv"
+		CSV.generate({ headers: ["name", "age"] }) do |csv|
+		  csv << { age: 30, name: "Stan" }
+		  csv << { name: "Ann", city: "Taipei" }
+		  csv << ["Bob", 20]
+		end
+		`, "name,age\nStan,30\nAnn,\nBob,20\n"},
+		{`
+		require "csv"
+		CSV.generate do |csv|
+		  csv << { name: "Stan", age: 30 }
+		  csv << { name: "Ann" }
+		end
+		`, "age,name\n30,Stan\n,Ann\n"},
+		{`
+		require "csv"
+		CSV.generate do |csv|
+		end
+		`, ""},
+		{`
+		require "csv"
+		CSV.generate_line(["foo", "bar, baz"])
+		`, "foo,\"bar, baz\"\n"},
+		{`
+		require "csv"
+		CSV.generate_line(["foo", "bar"], { col_sep: "|" })
+		`, "foo|bar\n"},
+		{`
+		require "csv"
+		CSV.parse(CSV.generate_line(["a,b", "\"c\"", ""]))
+		`, []interface{}{[]interface{}{"a,b", "\"c\"", ""}}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCSVMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "csv";CSV.new`, "NoMethodError: Undefined Method 'new' for CSV", 1},
+		{`require "csv";CSV::Writer.new`, "NoMethodError: Undefined Method 'new' for Writer", 1},
+		{`require "csv";CSV.parse`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`require "csv";CSV.parse(1)`, "TypeError: Expect argument #1 to be String, File or StringIO. got: Integer", 1},
+		{`require "csv";CSV.parse("a", 1)`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`require "csv";CSV.parse("a\"b\n")`, "CSV::MalformedCSVError: parse error on line 1, column 2: bare \" in non-quoted-field", 1},
+		{`require "csv";CSV.parse("a", { col_sep: "ab" })`, "ArgumentError: Invalid separator - \"ab\"", 1},
+		{`require "csv";CSV.parse("a", { col_sep: 1 })`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "csv";CSV.parse("a", { headers: ["a"] })`, "TypeError: Expect argument to be Boolean. got: Array", 1},
+		{`require "csv";CSV.parse("a", { quote_char: "'" })`, "ArgumentError: Unknown option - quote_char", 1},
+		{`require "csv";CSV.generate({ headers: true }) do |csv| end`, "TypeError: Expect argument to be Array. got: Boolean", 1},
+		{`require "csv";CSV.generate({ liberal_parsing: true }) do |csv| end`, "ArgumentError: Unknown option - liberal_parsing", 1},
+		{`require "csv";CSV.generate`, "InternalError: Can't yield without a block", 1},
+		{`require "csv";CSV.generate_line("a")`, "TypeError: Expect argument to be Array or Hash. got: String", 1},
+		{`require "csv";CSV.foreach("/tmp/goby/not_existing.csv") do |row| end`, "IOError: open /tmp/goby/not_existing.csv: no such file or directory", 1},
+		{`require "csv";CSV.foreach("a.csv")`, "InternalError: Can't yield without a block", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -469,7 +469,9 @@ var builtinDecimalInstanceMethods = []*BuiltinMethodObject{
 		// @return [Integer]
 		Name: "to_i",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			return t.vm.InitIntegerObject(receiver.(*DecimalObject).IntegerValue())
+			d := receiver.(*DecimalObject).value
+
+			return t.vm.initIntegerObjectFromBigInt(new(big.Int).Quo(d.Num(), d.Denom()))
 
 		},
 	},
@@ -648,7 +650,7 @@ func assertNumeric(n Object) (v *Decimal, result bool) {
 
 // intToDecimal converts int to Decimal
 func intToDecimal(i Object) *Decimal {
-	return new(Decimal).SetInt64(int64(i.(*IntegerObject).value))
+	return new(Decimal).SetInt(i.(*IntegerObject).bigInt())
 }
 
 // floatToDecimal converts int to Decimal
@@ -0,0 +1,408 @@
+package vm
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Digest computes the message digests of Strings with Go's `crypto` packages.
+// `Digest::MD5`, `Digest::SHA1`, `Digest::SHA256` and `Digest::SHA512` compute the digests of their algorithms
+// at once, or incrementally with `update`.
+//
+// ```ruby
+// require "digest"
+//
+// Digest::SHA256.hexdigest("abc")    # => "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+// Digest::MD5.base64digest("abc")    # => "kAFQmDzST7DWlj99KOF/cg=="
+//
+// sha = Digest::SHA256.new
+// sha.update("a")
+// sha.update("bc")
+// sha.hexdigest                      # => "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+// ```
+//
+// `digest` returns the raw bytes of the digest as a binary String.
+//
+// - `Digest.new` is not supported.
+
+// DigestObject computes the digest of the Strings given by `update`. It's also the object of HMAC.
+type DigestObject struct {
+	*BaseObj
+	name string
+	hash hash.Hash
+}
+
+// digestAlgorithms are the functions that create the hashes of the algorithms
+var digestAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Class methods --------------------------------------------------------
+var builtinDigestClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Class methods of the algorithms ---------------------------------------
+var builtinDigestAlgorithmClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the base64 encoded digest of the String.
+		//
+		// ```ruby
+		// Digest::SHA1.base64digest("abc") # => "qZk+NkcGgWq6PiVxeFDCbJzQ2J0="
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "base64digest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			sum, err := digestOf(t, receiver, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(base64.StdEncoding.EncodeToString(sum))
+
+		},
+	},
+	{
+		// Returns the raw bytes of the String's digest as a binary String.
+		//
+		// ```ruby
+		// Hex.encode(Digest::SHA256.digest("abc")) # => "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "digest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			sum, err := digestOf(t, receiver, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.initBinaryStringObject(string(sum))
+
+		},
+	},
+	{
+		// Returns a digest object that's updated with the content of the file, which is read in chunks.
+		//
+		// ```ruby
+		// Digest::SHA256.file("release.tar.gz").hexdigest
+		// ```
+		//
+		// @param path [String]
+		// @return [Digest]
+		Name: "file",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			d, digestErr := t.vm.newDigestObject(receiver.(*RClass), sourceLine)
+
+			if digestErr != nil {
+				return digestErr
+			}
+
+			f, err := os.Open(args[0].(*StringObject).value)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			defer f.Close()
+
+			if _, err := io.Copy(d.hash, f); err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return d
+
+		},
+	},
+	{
+		// Returns the hex encoded digest of the String.
+		//
+		// ```ruby
+		// Digest::MD5.hexdigest("abc") # => "900150983cd24fb0d6963f7d28e17f72"
+		// ```
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "hexdigest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			sum, err := digestOf(t, receiver, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(hex.EncodeToString(sum))
+
+		},
+	},
+	{
+		// Returns a digest object to be updated incrementally.
+		//
+		// ```ruby
+		// sha = Digest::SHA512.new
+		// sha << "a"
+		// sha << "bc"
+		// ```
+		//
+		// @return [Digest]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			d, err := t.vm.newDigestObject(receiver.(*RClass), sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return d
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinDigestInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Appends the String to the data to digest, same as `update`.
+		//
+		// @param string [String]
+		// @return [Digest]
+		Name: "<<",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*DigestObject).update(t, args, sourceLine)
+
+		},
+	},
+	{
+		// Returns the base64 encoded digest of the data given so far. The digest object can be updated afterwards.
+		//
+		// @return [String]
+		Name: "base64digest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(base64.StdEncoding.EncodeToString(receiver.(*DigestObject).hash.Sum(nil)))
+
+		},
+	},
+	{
+		// Returns the raw bytes of the digest of the data given so far as a binary String.
+		//
+		// @return [String]
+		Name: "digest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.initBinaryStringObject(string(receiver.(*DigestObject).hash.Sum(nil)))
+
+		},
+	},
+	{
+		// Returns the hex encoded digest of the data given so far.
+		//
+		// ```ruby
+		// sha = Digest::SHA1.new
+		// sha.update("abc")
+		// sha.hexdigest # => "a9993e364706816aba3e25717850c26c9cd0d89d"
+		// ```
+		//
+		// @return [String]
+		Name: "hexdigest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(receiver.(*DigestObject).ToString())
+
+		},
+	},
+	{
+		// Clears the data given so far.
+		//
+		// @return [Digest]
+		Name: "reset",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			receiver.(*DigestObject).hash.Reset()
+			return receiver
+
+		},
+	},
+	{
+		// Returns the hex encoded digest of the data given so far, same as `hexdigest`.
+		//
+		// @return [String]
+		Name: "to_s",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(receiver.(*DigestObject).ToString())
+
+		},
+	},
+	{
+		// Appends the String to the data to digest.
+		//
+		// ```ruby
+		// sha = Digest::SHA256.new
+		// File.open("large.log") do |f|
+		//   sha.update(f.read)
+		// end
+		// ```
+		//
+		// @param string [String]
+		// @return [Digest]
+		Name: "update",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*DigestObject).update(t, args, sourceLine)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initDigestClass(vm *VM) {
+	class := vm.initializeClass("Digest")
+	class.setBuiltinMethods(builtinDigestClassMethods, true)
+
+	for _, name := range []string{"MD5", "SHA1", "SHA256", "SHA512"} {
+		algorithm := vm.initializeClass(name)
+		algorithm.setBuiltinMethods(builtinDigestAlgorithmClassMethods, true)
+		algorithm.setBuiltinMethods(builtinDigestInstanceMethods, false)
+		algorithm.scope = class
+		class.setClassConstant(algorithm)
+	}
+
+	vm.objectClass.setClassConstant(class)
+}
+
+func (vm *VM) initDigestObject(class *RClass, name string, h hash.Hash) *DigestObject {
+	return &DigestObject{
+		BaseObj: NewBaseObject(class),
+		name:    name,
+		hash:    h,
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the hex encoded digest
+func (d *DigestObject) ToString() string {
+	return hex.EncodeToString(d.hash.Sum(nil))
+}
+
+// Inspect returns the name of the algorithm and the hex encoded digest
+func (d *DigestObject) Inspect() string {
+	return "#<" + d.name + ": " + d.ToString() + ">"
+}
+
+// ToJSON returns the quoted ToString
+func (d *DigestObject) ToJSON(t *Thread) string {
+	return jsonString(d.ToString())
+}
+
+// Value returns the hash
+func (d *DigestObject) Value() interface{} {
+	return d.hash
+}
+
+// Other helper functions -----------------------------------------------
+
+func (d *DigestObject) update(t *Thread, args []Object, sourceLine int) Object {
+	if len(args) != 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+	if typeErr != nil {
+		return typeErr
+	}
+
+	io.WriteString(d.hash, args[0].(*StringObject).value)
+	return d
+}
+
+// digestOf returns the digest of the String argument with the algorithm of the class
+func digestOf(t *Thread, class Object, args []Object, sourceLine int) ([]byte, *Error) {
+	if len(args) != 1 {
+		return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+	if typeErr != nil {
+		return nil, typeErr
+	}
+
+	d, err := t.vm.newDigestObject(class.(*RClass), sourceLine)
+
+	if err != nil {
+		return nil, err
+	}
+
+	io.WriteString(d.hash, args[0].(*StringObject).value)
+	return d.hash.Sum(nil), nil
+}
+
+// newDigestObject returns a digest object with the algorithm of the class, which is inherited by its subclasses
+func (vm *VM) newDigestObject(class *RClass, sourceLine int) (*DigestObject, *Error) {
+	for c := class; c != nil; c = c.superClass {
+		if fn, ok := digestAlgorithms[strings.ToLower(c.Name)]; ok {
+			return vm.initDigestObject(class, "Digest::"+c.Name, fn()), nil
+		}
+	}
+
+	return nil, vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownDigest, class.Name)
+}
@@ -0,0 +1,116 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestDigestClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`require "digest";Digest::MD5.hexdigest("abc")`, "900150983cd24fb0d6963f7d28e17f72"},
+		{`require "digest";Digest::SHA1.hexdigest("abc")`, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{`require "digest";Digest::SHA256.hexdigest("abc")`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`require "digest";Digest::SHA512.hexdigest("")`, "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e"},
+		{`require "digest";Digest::MD5.base64digest("abc")`, "kAFQmDzST7DWlj99KOF/cg=="},
+		{`require "digest";require "hex";Hex.encode(Digest::SHA256.digest("abc"))`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`require "digest";Digest::SHA1.digest("abc") == Digest::SHA1.new.update("abc").digest`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDigestInstanceMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "digest"
+		sha = Digest::SHA256.new
+		sha.update("a")
+		sha << "b"
+		sha << "c"
+		sha.hexdigest
+		`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`
+		require "digest"
+		sha = Digest::SHA1.new
+		sha.update("ab")
+		first = sha.hexdigest
+		sha.update("c")
+		[first == Digest::SHA1.hexdigest("ab"), sha.to_s == Digest::SHA1.hexdigest("abc")]
+		`, []interface{}{true, true}},
+		{`
+		require "digest"
+		md5 = Digest::MD5.new
+		md5.update("garbage")
+		md5.reset
+		md5.update("abc")
+		md5.base64digest
+		`, "kAFQmDzST7DWlj99KOF/cg=="},
+		{`
+		require "digest"
+		Digest::MD5.new.inspect
+		`, "#<Digest::MD5: d41d8cd98f00b204e9800998ecf8427e>"},
+		{`
+		require "digest"
+		File.open("/tmp/goby/digest.txt", "w") do |f|
+		  f.write("abc")
+		end
+
+		Digest::SHA256.file("/tmp/goby/digest.txt").hexdigest
+		`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`
+		require "digest"
+
+		class Checksum < Digest::SHA256
+		end
+
+		class StrictChecksum < Checksum
+		end
+
+		sha = StrictChecksum.new
+		sha << "abc"
+		[Checksum.hexdigest("abc") == sha.hexdigest, sha.class.name, sha.inspect == Digest::SHA256.new.update("abc").inspect]
+		`, []interface{}{true, "StrictChecksum", true}},
+	}
+
+	setup()
+	defer teardown()
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDigestMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "digest";Digest.new`, "NoMethodError: Undefined Method 'new' for Digest", 1},
+		{`require "digest";Digest::SHA256.new(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`require "digest";Digest::SHA256.hexdigest`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`require "digest";Digest::SHA256.hexdigest(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "digest";Digest::SHA256.new.update(nil)`, "TypeError: Expect argument to be String. got: Null", 1},
+		{`require "digest";Digest::SHA256.new.hexdigest(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`require "digest";Digest::SHA256.file("/tmp/goby/not_existing")`, "IOError: open /tmp/goby/not_existing: no such file or directory", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,727 @@
+package vm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Dir provides class methods for listing, creating, removing and traversing directories.
+// Relative paths are resolved against the current working directory.
+//
+// ```ruby
+// Dir.mkdir_p("build/assets")
+// Dir.glob("src/**/*.gb").each do |path|
+//   puts(path)
+// end
+// Dir.rm_rf("build")
+//
+// Dir.mktmpdir do |dir|
+//   puts(dir) # => "/tmp/123456789", which is removed after the block
+// end
+// ```
+//
+// - `Dir.new` is not supported.
+var builtinDirClassMethods = []*BuiltinMethodObject{
+	{
+		// Changes the current working directory, to the home directory by default.
+		// If a block is given, the directory is changed back after the block is executed,
+		// and the block's result is returned.
+		//
+		// ```ruby
+		// Dir.chdir("/tmp")
+		// Dir.pwd # => "/tmp"
+		//
+		// Dir.chdir("/usr") do
+		//   Dir.pwd # => "/usr"
+		// end
+		// Dir.pwd # => "/tmp"
+		// ```
+		//
+		// @param path [String]
+		// @return [Object]
+		Name: "chdir",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			var dir string
+
+			if len(args) == 0 {
+				home, err := os.UserHomeDir()
+
+				if err != nil {
+					return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+				}
+
+				dir = home
+			} else {
+				typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+				if typeErr != nil {
+					return typeErr
+				}
+
+				dir = args[0].(*StringObject).value
+			}
+
+			wd, err := os.Getwd()
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			err = os.Chdir(dir)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			if blockFrame == nil {
+				return t.vm.InitIntegerObject(0)
+			}
+
+			// Changes back even if the block raises an error
+			defer os.Chdir(wd)
+
+			return t.builtinMethodYield(blockFrame)
+
+		},
+	},
+	{
+		// Returns the names of the entries in the given directory in lexical order, without "." and "..".
+		//
+		// ```ruby
+		// Dir.children("lib") # => ["file.gb", "net"]
+		// ```
+		//
+		// @param path [String]
+		// @return [Array]
+		Name: "children",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			names, err := readDirNames(args[0].(*StringObject).value)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.initStringsArray(names)
+
+		},
+	},
+	{
+		// Returns the names of the entries in the given directory in lexical order, including "." and "..".
+		//
+		// ```ruby
+		// Dir.entries("lib") # => [".", "..", "file.gb", "net"]
+		// ```
+		//
+		// @param path [String]
+		// @return [Array]
+		Name: "entries",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			names, err := readDirNames(args[0].(*StringObject).value)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.initStringsArray(append([]string{".", ".."}, names...))
+
+		},
+	},
+	{
+		// Returns true if the given path is a directory.
+		//
+		// ```ruby
+		// Dir.exist?("/tmp") # => true
+		// ```
+		//
+		// @param path [String]
+		// @return [Boolean]
+		Name: "exist?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			fs, err := os.Stat(args[0].(*StringObject).value)
+
+			return toBooleanObject(err == nil && fs.IsDir())
+
+		},
+	},
+	{
+		// Returns the paths that match the given pattern in lexical order.
+		// Besides the `*`, `?`, `[...]` patterns, a `**` path element matches zero or more directories.
+		// Entries starting with "." are only matched by patterns starting with ".".
+		//
+		// ```ruby
+		// Dir.glob("lib/*.gb")     # => ["lib/file.gb"]
+		// Dir.glob("lib/**/*.gb")  # => ["lib/file.gb", "lib/net/http.gb"]
+		// ```
+		//
+		// @param pattern [String]
+		// @return [Array]
+		Name: "glob",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			paths, err := glob(args[0].(*StringObject).value)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
+			}
+
+			return t.vm.initStringsArray(paths)
+
+		},
+	},
+	{
+		// Creates a directory with the given permission, which is 0755 by default.
+		// The parent directory must exist.
+		//
+		// ```ruby
+		// Dir.mkdir("build")       # => 0
+		// Dir.mkdir("build", 0700) # => 0
+		// ```
+		//
+		// @param path [String]
+		// @param permission [Integer]
+		// @return [Integer]
+		Name: "mkdir",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			path, perm, errObj := dirArguments(t, args, sourceLine)
+
+			if errObj != nil {
+				return errObj
+			}
+
+			err := os.Mkdir(path, perm)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitIntegerObject(0)
+
+		},
+	},
+	{
+		// Creates a directory with the given permission, which is 0755 by default, along with any missing parents.
+		// Does nothing if the directory already exists.
+		//
+		// ```ruby
+		// Dir.mkdir_p("build/assets/css") # => "build/assets/css"
+		// ```
+		//
+		// @param path [String]
+		// @param permission [Integer]
+		// @return [String]
+		Name: "mkdir_p",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			path, perm, errObj := dirArguments(t, args, sourceLine)
+
+			if errObj != nil {
+				return errObj
+			}
+
+			err := os.MkdirAll(path, perm)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitStringObject(path)
+
+		},
+	},
+	{
+		// Creates a new directory with a random name in the temporary directory, or in the given directory,
+		// and returns its path. The name starts with the prefix, or the prefix's last "*" is replaced
+		// by the random part.
+		// If a block is given, the path is yielded instead, and the directory is removed with everything
+		// it contains after the block is executed. The block's result is returned.
+		//
+		// ```ruby
+		// Dir.mktmpdir("build") # => "/tmp/build123456789"
+		//
+		// Dir.mktmpdir do |dir|
+		//   File.open(dir + "/out.txt", "w") do |f|
+		//     f.write("scratch")
+		//   end
+		// end # the directory is removed here
+		// ```
+		//
+		// @param prefix [String]
+		// @param dir [String]
+		// @return [String]
+		Name: "mktmpdir",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			prefix, dir, errObj := tempArguments(t, args, sourceLine)
+			var path string
+
+			if errObj == nil {
+				var err error
+				path, err = ioutil.TempDir(dir, prefix)
+
+				if err != nil {
+					errObj = t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+				}
+			}
+
+			if errObj != nil {
+				if blockFrame != nil {
+					t.callFrameStack.pop()
+				}
+
+				return errObj
+			}
+
+			if blockFrame == nil {
+				return t.vm.InitStringObject(path)
+			}
+
+			// Removes the directory even if the block raises an error
+			defer os.RemoveAll(path)
+
+			return t.builtinMethodYield(blockFrame, t.vm.InitStringObject(path))
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Returns the current working directory.
+		//
+		// ```ruby
+		// Dir.pwd # => "/home/goby"
+		// ```
+		//
+		// @return [String]
+		Name: "pwd",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			wd, err := os.Getwd()
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitStringObject(wd)
+
+		},
+	},
+	{
+		// Removes the given path and everything it contains. Does nothing if the path doesn't exist.
+		//
+		// ```ruby
+		// Dir.rm_rf("build") # => "build"
+		// ```
+		//
+		// @param path [String]
+		// @return [String]
+		Name: "rm_rf",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			path := args[0].(*StringObject).value
+			err := os.RemoveAll(path)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitStringObject(path)
+
+		},
+	},
+	{
+		// Removes the given directory, which must be empty.
+		//
+		// ```ruby
+		// Dir.rmdir("build") # => 0
+		// ```
+		//
+		// @param path [String]
+		// @return [Integer]
+		Name: "rmdir",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			path := args[0].(*StringObject).value
+			fs, err := os.Stat(path)
+
+			if err == nil && !fs.IsDir() {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, errors.NotADirectory, path)
+			}
+
+			if err == nil {
+				err = os.Remove(path)
+			}
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitIntegerObject(0)
+
+		},
+	},
+	{
+		// Returns the directory for temporary files, which is `$TMPDIR` or "/tmp" on Unix systems.
+		//
+		// ```ruby
+		// Dir.tmpdir # => "/tmp"
+		// ```
+		//
+		// @return [String]
+		Name: "tmpdir",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(os.TempDir())
+
+		},
+	},
+	{
+		// Walks the file tree rooted at the given path in lexical order, including the root itself,
+		// and yields each path with its `File::Stat`. Symbolic links aren't followed.
+		//
+		// ```ruby
+		// Dir.walk("src") do |path, stat|
+		//   if stat.file? && File.extname(path) == ".gb"
+		//     puts(path)
+		//   end
+		// end
+		// ```
+		//
+		// @param path [String]
+		// @return [Null]
+		Name: "walk",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			yielded := false
+
+			err := filepath.Walk(args[0].(*StringObject).value, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+
+				yielded = true
+				t.builtinMethodYield(blockFrame, t.vm.InitStringObject(path), t.vm.initFileStatObject(path, info))
+
+				return nil
+			})
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			if !yielded {
+				t.callFrameStack.pop()
+			}
+
+			return NULL
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initDirClass() *RClass {
+	dc := vm.initializeClass(classes.DirClass)
+	dc.setBuiltinMethods(builtinDirClassMethods, true)
+
+	return dc
+}
+
+// Other helper functions -----------------------------------------------
+
+func (vm *VM) initStringsArray(strs []string) *ArrayObject {
+	elems := []Object{}
+
+	for _, s := range strs {
+		elems = append(elems, vm.InitStringObject(s))
+	}
+
+	return vm.InitArrayObject(elems)
+}
+
+// dirArguments returns the path and the optional permission passed to `Dir.mkdir` and `Dir.mkdir_p`
+func dirArguments(t *Thread, args []Object, sourceLine int) (string, os.FileMode, *Error) {
+	if len(args) < 1 || len(args) > 2 {
+		return "", 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+	}
+
+	path, ok := args[0].(*StringObject)
+
+	if !ok {
+		return "", 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.StringClass, args[0].Class().Name)
+	}
+
+	perm := os.FileMode(0755)
+
+	if len(args) == 2 {
+		p, ok := args[1].(*IntegerObject)
+
+		if !ok {
+			return "", 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.IntegerClass, args[1].Class().Name)
+		}
+
+		value, err := p.intValue(t, sourceLine)
+
+		if err != nil {
+			return "", 0, err
+		}
+
+		if !os.FileMode(value).IsRegular() {
+			return "", 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidChmodNumber, value)
+		}
+
+		perm = os.FileMode(value)
+	}
+
+	return path.value, perm, nil
+}
+
+// tempArguments returns the optional prefix and directory passed to `Dir.mktmpdir` and `Tempfile.create`.
+// The directory is empty if it's not given, which means the directory for temporary files.
+func tempArguments(t *Thread, args []Object, sourceLine int) (string, string, *Error) {
+	if len(args) > 2 {
+		return "", "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 2, len(args))
+	}
+
+	values := []string{"", ""}
+
+	for i, arg := range args {
+		str, ok := arg.(*StringObject)
+
+		if !ok {
+			return "", "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.StringClass, arg.Class().Name)
+		}
+
+		values[i] = str.value
+	}
+
+	return values[0], values[1], nil
+}
+
+// readDirNames returns the names of the entries in dir in lexical order
+func readDirNames(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+
+	return names, nil
+}
+
+// glob returns the paths matching pattern in lexical order.
+// Unlike filepath.Glob, a "**" element matches zero or more directories.
+func glob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		paths, err := filepath.Glob(pattern)
+
+		if paths == nil {
+			paths = []string{}
+		}
+
+		return paths, err
+	}
+
+	root := ""
+
+	if filepath.IsAbs(pattern) {
+		root = string(filepath.Separator)
+	}
+
+	elements := []string{}
+
+	for _, e := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if e != "" {
+			elements = append(elements, e)
+		}
+	}
+
+	found := map[string]bool{}
+	err := globElements(root, elements, found)
+
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+
+	for path := range found {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// globElements adds the paths under dir that match the pattern elements to found.
+// Directories that can't be read are skipped.
+func globElements(dir string, elements []string, found map[string]bool) error {
+	element, rest := elements[0], elements[1:]
+
+	readDir := dir
+
+	if readDir == "" {
+		readDir = "."
+	}
+
+	infos, err := ioutil.ReadDir(readDir)
+
+	if err != nil {
+		return nil
+	}
+
+	// A trailing "**" matches like "*"
+	if element == "**" && len(rest) > 0 {
+		err := globElements(dir, rest, found)
+
+		if err != nil {
+			return err
+		}
+
+		for _, info := range infos {
+			if info.IsDir() && !strings.HasPrefix(info.Name(), ".") {
+				err := globElements(filepath.Join(dir, info.Name()), elements, found)
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if element == "**" {
+		element = "*"
+	}
+
+	for _, info := range infos {
+		name := info.Name()
+
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(element, ".") {
+			continue
+		}
+
+		matched, err := filepath.Match(element, name)
+
+		if err != nil {
+			return err
+		}
+
+		if !matched {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+
+		if len(rest) == 0 {
+			found[path] = true
+		} else if info.IsDir() {
+			err := globElements(path, rest, found)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
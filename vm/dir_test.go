@@ -0,0 +1,154 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestDirMethods(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir("/tmp/goby/dir")
+		Dir.exist?("/tmp/goby/dir")
+		`, true},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir_p("/tmp/goby/dir/a/b")
+		Dir.mkdir_p("/tmp/goby/dir/a/b")
+		File.directory?("/tmp/goby/dir/a/b")
+		`, true},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir_p("/tmp/goby/dir/b")
+		File.new("/tmp/goby/dir/a.gb", "w").close
+		File.new("/tmp/goby/dir/.hidden", "w").close
+		Dir.entries("/tmp/goby/dir")
+		`, []interface{}{".", "..", ".hidden", "a.gb", "b"}},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir_p("/tmp/goby/dir/b")
+		File.new("/tmp/goby/dir/a.gb", "w").close
+		Dir.children("/tmp/goby/dir")
+		`, []interface{}{"a.gb", "b"}},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir_p("/tmp/goby/dir/b/c")
+		Dir.mkdir_p("/tmp/goby/dir/.git")
+		File.new("/tmp/goby/dir/a.gb", "w").close
+		File.new("/tmp/goby/dir/a.txt", "w").close
+		File.new("/tmp/goby/dir/b/b.gb", "w").close
+		File.new("/tmp/goby/dir/b/c/c.gb", "w").close
+		File.new("/tmp/goby/dir/.git/d.gb", "w").close
+		Dir.glob("/tmp/goby/dir/**/*.gb")
+		`, []interface{}{"/tmp/goby/dir/a.gb", "/tmp/goby/dir/b/b.gb", "/tmp/goby/dir/b/c/c.gb"}},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir_p("/tmp/goby/dir/b")
+		File.new("/tmp/goby/dir/a.gb", "w").close
+		File.new("/tmp/goby/dir/b/b.gb", "w").close
+		Dir.glob("/tmp/goby/dir/*.gb")
+		`, []interface{}{"/tmp/goby/dir/a.gb"}},
+		{`Dir.glob("/tmp/goby/non-existent/**/*")`, []interface{}{}},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir("/tmp/goby/dir")
+		Dir.rmdir("/tmp/goby/dir")
+		Dir.exist?("/tmp/goby/dir")
+		`, false},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.rm_rf("/tmp/goby/dir")
+		`, "/tmp/goby/dir"},
+		{`
+		wd = Dir.pwd
+		Dir.chdir("/tmp/goby") do
+		  Dir.pwd
+		end
+		`, "/tmp/goby"},
+		{`
+		wd = Dir.pwd
+		Dir.chdir("/tmp/goby") do
+		  10
+		end
+		Dir.pwd == wd
+		`, true},
+		{`
+		Dir.rm_rf("/tmp/goby/dir")
+		Dir.mkdir_p("/tmp/goby/dir/b")
+		File.new("/tmp/goby/dir/a.gb", "w").close
+		File.new("/tmp/goby/dir/b/b.gb", "w").close
+		paths = []
+		Dir.walk("/tmp/goby/dir") do |path, stat|
+		  if stat.file?
+		    paths.push(path)
+		  end
+		end
+		paths
+		`, []interface{}{"/tmp/goby/dir/a.gb", "/tmp/goby/dir/b/b.gb"}},
+		{`
+		Dir.mktmpdir("build", "/tmp/goby") do |dir|
+		  [File.directory?(dir), File.basename(dir).start_with("build"), File.split(dir)[0]]
+		end
+		`, []interface{}{true, true, "/tmp/goby/"}},
+		{`
+		Dir.mktmpdir("build*.d", "/tmp/goby") do |dir|
+		  File.basename(dir).end_with?(".d")
+		end
+		`, true},
+		{`
+		path = nil
+		result = Dir.mktmpdir("scratch", "/tmp/goby") do |dir|
+		  path = dir
+		  Dir.mkdir_p(dir + "/a/b")
+		  File.open(dir + "/a/b/c.txt", "w") do |f|
+		    f.write("c")
+		  end
+		  File.exist?(dir + "/a/b/c.txt")
+		end
+		[result, Dir.exist?(path)]
+		`, []interface{}{true, false}},
+		{`Dir.tmpdir == ENV["TMPDIR"] || Dir.tmpdir == "/tmp"`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDirMethodsFail(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testsFail := []errorTestCase{
+		{`Dir.new`, "NoMethodError: Undefined Method 'new' for Dir", 1},
+		{`Dir.mkdir("/tmp/goby/non-existent/dir")`, "IOError: mkdir /tmp/goby/non-existent/dir: no such file or directory", 1},
+		{`Dir.mkdir(1)`, "TypeError: Expect argument #1 to be String. got: Integer", 1},
+		{`Dir.mkdir("/tmp/goby/dir", "0755")`, "TypeError: Expect argument #2 to be Integer. got: String", 1},
+		{`Dir.entries("/tmp/goby/non-existent")`, "IOError: open /tmp/goby/non-existent: no such file or directory", 1},
+		{`File.new("/tmp/goby/file.txt", "w").close; Dir.rmdir("/tmp/goby/file.txt")`, "IOError: Not a directory - /tmp/goby/file.txt", 1},
+		{`Dir.chdir("/tmp/goby/non-existent")`, "IOError: chdir /tmp/goby/non-existent: no such file or directory", 1},
+		{`Dir.glob("[")`, "ArgumentError: syntax error in pattern", 1},
+		{`Dir.walk("/tmp/goby")`, "InternalError: Can't yield without a block", 1},
+		{`Dir.mktmpdir(1)`, "TypeError: Expect argument #1 to be String. got: Integer", 1},
+		{`Dir.mktmpdir("a", "b", "c") do |d| end`, "ArgumentError: Expect 2 or less argument(s). got: 3", 1},
+		{`Dir.tmpdir(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
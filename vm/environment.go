@@ -1,39 +1,53 @@
 package vm
 
-import "sort"
+import (
+	"sort"
+	"sync"
+)
 
 func newEnvironment() *environment {
 	s := make(map[string]Object)
 	return &environment{store: s}
 }
 
+// environment stores instance variables and methods, which can be read and written by several threads at the same time,
+// so all the accesses are guarded by a lock
 type environment struct {
 	store map[string]Object
+	sync.RWMutex
 }
 
 func (e *environment) get(name string) (Object, bool) {
+	e.RLock()
 	obj, ok := e.store[name]
+	e.RUnlock()
 	return obj, ok
 }
 
 func (e *environment) set(name string, val Object) Object {
+	e.Lock()
 	e.store[name] = val
+	e.Unlock()
 	return val
 }
 
 func (e *environment) names() []string {
 	keys := []string{}
+	e.RLock()
 	for key := range e.store {
 		keys = append(keys, key)
 	}
+	e.RUnlock()
 	sort.Strings(keys)
 	return keys
 }
 
 func (e *environment) copy() *environment {
 	newEnv := make(map[string]Object)
+	e.RLock()
 	for key, value := range e.store {
 		newEnv[key] = value
 	}
+	e.RUnlock()
 	return &environment{store: newEnv}
 }
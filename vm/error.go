@@ -36,7 +36,7 @@ func (vm *VM) InitNoMethodError(sourceLine int, methodName string, receiver Obje
 
 // InitErrorObject initializes and returns Error object
 func (vm *VM) InitErrorObject(errorType string, sourceLine int, format string, args ...interface{}) *Error {
-	errClass := vm.objectClass.getClassConstant(errorType)
+	errClass := vm.errorClass(errorType)
 
 	t := &vm.mainThread
 	cf := t.callFrameStack.top()
@@ -50,17 +50,35 @@ func (vm *VM) InitErrorObject(errorType string, sourceLine int, format string, a
 		}
 	}
 
+	// The main thread has no call frames when the program has finished, e.g. when running `at_exit` blocks
+	stackTraces := []string{}
+
+	if cf != nil {
+		stackTraces = append(stackTraces, fmt.Sprintf("from %s:%d", cf.FileName(), sourceLine))
+	}
+
 	return &Error{
 		BaseObj: NewBaseObject(errClass),
 		// Add 1 to source line because it's zero indexed
 		message:     fmt.Sprintf(errorType+": "+format, args...),
-		stackTraces: []string{fmt.Sprintf("from %s:%d", cf.FileName(), sourceLine)},
+		stackTraces: stackTraces,
 		Type:        errorType,
 	}
 }
 
+// errorClass returns the class of the given error type, which can be namespaced like `Math::DomainError`
+func (vm *VM) errorClass(errorType string) *RClass {
+	errClass := vm.objectClass
+
+	for _, name := range strings.Split(errorType, "::") {
+		errClass = errClass.getClassConstant(name)
+	}
+
+	return errClass
+}
+
 func (vm *VM) initErrorClasses() {
-	errTypes := []string{errors.InternalError, errors.IOError, errors.ArgumentError, errors.NameError, errors.StopIteration, errors.TypeError, errors.NoMethodError, errors.ConstantAlreadyInitializedError, errors.HTTPError, errors.ZeroDivisionError, errors.ChannelCloseError, errors.NotImplementedError}
+	errTypes := []string{errors.InternalError, errors.IOError, errors.ArgumentError, errors.NameError, errors.StopIteration, errors.TypeError, errors.NoMethodError, errors.ConstantAlreadyInitializedError, errors.HTTPError, errors.ZeroDivisionError, errors.ChannelCloseError, errors.NotImplementedError, errors.ContextError, errors.EOFError, errors.RangeError}
 
 	for _, errType := range errTypes {
 		c := vm.initializeClass(errType)
@@ -80,9 +98,9 @@ func (e *Error) Inspect() string {
 	return e.ToString()
 }
 
-// ToJSON just delegates to `ToString`
+// ToJSON returns the quoted ToString
 func (e *Error) ToJSON(t *Thread) string {
-	return e.ToString()
+	return jsonString(e.ToString())
 }
 
 // Value is equivalent to ToString
@@ -92,5 +110,9 @@ func (e *Error) Value() interface{} {
 
 // Message prints the error's message and its stack traces
 func (e *Error) Message() string {
+	if len(e.stackTraces) == 0 {
+		return e.message
+	}
+
 	return e.message + "\n" + strings.Join(e.stackTraces, "\n")
 }
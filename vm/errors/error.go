@@ -25,6 +25,34 @@ const (
 	ChannelCloseError = "ChannelCloseError"
 	// NotImplementedError means the method is missing
 	NotImplementedError = "NotImplementedError"
+	// ContextError is returned when an operation is aborted by a cancelled or expired Context
+	ContextError = "ContextError"
+	// RangeError is raised when an Integer is too big for the operation
+	RangeError = "RangeError"
+	// EOFError is raised when reading at the end of a file
+	EOFError = "EOFError"
+	// DomainError is raised when an argument is out of a mathematical function's domain
+	DomainError = "Math::DomainError"
+	// ParseError is raised when the command line options can't be parsed by OptionParser
+	ParseError = "OptionParser::ParseError"
+	// MalformedCSVError is raised when CSV can't be parsed
+	MalformedCSVError = "CSV::MalformedCSVError"
+	// YAMLSyntaxError is raised when YAML can't be parsed
+	YAMLSyntaxError = "YAML::SyntaxError"
+	// TOMLSyntaxError is raised when TOML can't be parsed
+	TOMLSyntaxError = "TOML::SyntaxError"
+	// JSONGeneratorError is raised when an object can't be generated as JSON
+	JSONGeneratorError = "JSON::GeneratorError"
+	// JSONNestingError is raised when arrays and hashes are nested too deeply to generate JSON
+	JSONNestingError = "JSON::NestingError"
+	// DecryptionError is raised when encrypted data can't be decrypted or authenticated
+	DecryptionError = "Crypto::DecryptionError"
+	// TemplateSyntaxError is raised when a template can't be compiled
+	TemplateSyntaxError = "Template::SyntaxError"
+	// CompressDataError is raised when compressed data is corrupted
+	CompressDataError = "Compress::DataError"
+	// ArchiveError is raised when an archive can't be created or extracted
+	ArchiveError = "Archive::Error"
 )
 
 /*
@@ -39,12 +67,39 @@ const (
 	WrongArgumentTypeFormatNum      = "Expect argument #%d to be %s. got: %s"
 	InvalidChmodNumber              = "Invalid chmod number. got: %d"
 	InvalidNumericString            = "Invalid numeric string. got: %s"
+	InvalidRadix                    = "Invalid radix. got: %d"
+	ShiftWidthTooBig                = "Shift width too big. got: %s"
+	IntegerTooBig                   = "Integer too big to convert into int. got: %s"
+	ExponentTooBig                  = "Exponent too big. got: %s"
+	KeyTooLong                      = "Key length must be %d or less. got: %d"
+	ScryptMemoryTooBig              = "scrypt can't use more than %d bytes of memory. got n: %d, r: %d"
+	NotADirectory                   = "Not a directory - %s"
+	NoChildProcess                  = "No child process - %d"
+	UnknownSignal                   = "Unknown signal - %s"
+	CantTrapSignal                  = "Can't trap reserved signal - %s"
+	InvalidSignalCommand            = "Invalid signal command - %s"
+	UnknownOption                   = "Unknown option - %s"
+	InvalidLogLevel                 = "Invalid log level - %s"
+	InvalidLogFormat                = "Invalid log format - %s"
+	InvalidSeparator                = "Invalid separator - %q"
+	CantConvertTo                   = "Can't convert %s to %s"
+	UnknownDigest                   = "Unknown digest algorithm - %s"
+	InvalidBase64                   = "Invalid base64 - %s"
+	InvalidHex                      = "Invalid hex - %s"
+	InvalidArgument                 = "Invalid argument - %s"
+	UnknownEncoding                 = "Unknown encoding name - %s"
+	InvalidByteSequence             = "Invalid byte sequence in %s"
+	UnknownNormalizationForm        = "Unknown normalization form - %s"
+	EndOfFile                       = "End of file reached"
 	CantLoadFile                    = "Can't load \"%s\""
 	CantRequireNonString            = "Can't require \"%s\": Pass a string instead"
 	CantYieldWithoutBlockFormat     = "Can't yield without a block"
 	NotDiggable                     = "Expect target to be Diggable, got %s"
 	DividedByZero                   = "Divided by 0"
+	OutOfDomain                     = "Numerical argument is out of domain - \"%s\""
 	ChannelIsClosed                 = "The channel is already closed."
+	ExecutorIsShutdown              = "The executor is already shut down."
+	CantMakeThreadSafe              = "Can't make %s thread-safe"
 	TooSmallIndexValue              = "Index value %d too small for array. minimum: %d"
 	IndexOutOfRange                 = "Index value out of range. got: %v"
 	InvalidCode                     = "invalid code: %s"
@@ -1076,6 +1076,58 @@ func TestInstanceVariableEvaluation(t *testing.T) {
 	}
 }
 
+func TestGlobalVariableEvaluation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		$count = 1
+
+		class Foo
+		  def incr
+		    $count += 1
+		  end
+		end
+
+		Foo.new.incr
+		Foo.new.incr
+		$count
+		`, 3},
+		{`$not_assigned`, nil},
+		{`$stdout.object_id == STDOUT.object_id`, true},
+		{`$stderr.object_id == STDERR.object_id`, true},
+		{`$stdin.object_id == STDIN.object_id`, true},
+		{`
+		$stdout = STDERR
+		$stdout.name
+		`, "/dev/stderr"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGlobalVariableEvaluationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`$stdout = 1`, "TypeError: $stdout must have write method, Integer given", 1},
+		{`$stderr = nil`, "TypeError: $stderr must have write method, Null given", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestMethodCall(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -2,10 +2,15 @@ package vm
 
 import (
 	"bufio"
+	"bytes"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
@@ -21,16 +26,48 @@ import (
 // end         # f automatically closes
 // ```
 //
+// Reading is buffered, so large files can be processed line by line.
+//
+// ```ruby
+// File.foreach("/var/log/app.log") do |line|
+//   puts(line) if line.include?("ERROR")
+// end
+//
+// File.new("/var/log/app.log").each_line.map do |line|
+//   line.size
+// end.first(10)
+// ```
+//
 type FileObject struct {
 	*BaseObj
-	File *os.File
+	File   *os.File
+	reader *bufio.Reader
+	writer *bufio.Writer
+	// output is the writer behind `STDOUT` and `STDERR`
+	output io.Writer
+}
+
+// FileStatObject holds the information of a path, which is returned by `File.stat` and `File.lstat`.
+//
+// ```ruby
+// stat = File.stat("/tmp")
+// stat.directory? # => true
+// ```
+//
+// - `File::Stat.new` is not supported.
+type FileStatObject struct {
+	*BaseObj
+	path string
+	info os.FileInfo
 }
 
 var fileModeTable = map[string]int{
 	"r":  syscall.O_RDONLY,
-	"r+": syscall.O_RDWR,
-	"w":  syscall.O_WRONLY,
-	"w+": syscall.O_RDWR,
+	"r+": syscall.O_RDWR | syscall.O_CREAT,
+	"w":  syscall.O_WRONLY | syscall.O_CREAT | syscall.O_TRUNC,
+	"w+": syscall.O_RDWR | syscall.O_CREAT | syscall.O_TRUNC,
+	"a":  syscall.O_WRONLY | syscall.O_CREAT | syscall.O_APPEND,
+	"a+": syscall.O_RDWR | syscall.O_CREAT | syscall.O_APPEND,
 }
 
 // Class methods --------------------------------------------------------
@@ -79,8 +116,14 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.IntegerClass, args[0].Class().Name)
 			}
 
-			if !os.FileMode(mod.value).IsRegular() {
-				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidChmodNumber, mod.value)
+			modValue, errObj := mod.intValue(t, sourceLine)
+
+			if errObj != nil {
+				return errObj
+			}
+
+			if !os.FileMode(modValue).IsRegular() {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidChmodNumber, modValue)
 			}
 
 			for i := 1; i < len(args); i++ {
@@ -93,7 +136,7 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 					fn.value = filepath.Join(t.vm.fileDir, fn.value)
 				}
 
-				err := os.Chmod(fn.value, os.FileMode(uint32(mod.value)))
+				err := os.Chmod(fn.value, os.FileMode(uint32(modValue)))
 				if err != nil {
 					return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
 				}
@@ -134,6 +177,33 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns true if the given path is a directory.
+		//
+		// ```ruby
+		// File.directory?("/tmp")       # => true
+		// File.directory?("/etc/hosts") # => false
+		// ```
+		// @param path [String]
+		// @return [Boolean]
+		Name: "directory?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			fs, err := os.Stat(args[0].Value().(string))
+
+			return toBooleanObject(err == nil && fs.IsDir())
+
+		},
+	},
 	// Determines if the specified file.
 	//
 	// ```ruby
@@ -166,6 +236,46 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns the absolute path of the given path. A leading `~` is expanded to the home directory,
+		// and a relative path is resolved against the given directory, or the current directory by default.
+		//
+		// ```ruby
+		// File.expand_path("lib/../plugin", "/home/goby") # => "/home/goby/plugin"
+		// File.expand_path("~/.gobyrc")                   # => "/home/goby/.gobyrc"
+		// ```
+		//
+		// @param path [String]
+		// @param dir [String]
+		// @return [String]
+		Name: "expand_path",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			for i, arg := range args {
+				if _, ok := arg.(*StringObject); !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.StringClass, arg.Class().Name)
+				}
+			}
+
+			dir := ""
+
+			if len(args) == 2 {
+				dir = args[1].(*StringObject).value
+			}
+
+			path, err := expandPath(args[0].(*StringObject).value, dir)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitStringObject(path)
+
+		},
+	},
 	{
 		// Returns the extension part of file.
 		//
@@ -190,6 +300,91 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 			return t.vm.InitStringObject(filepath.Ext(args[0].Value().(string)))
 		},
 	},
+	{
+		// Returns true if the given path is a regular file.
+		//
+		// ```ruby
+		// File.file?("/etc/hosts") # => true
+		// File.file?("/tmp")       # => false
+		// ```
+		// @param path [String]
+		// @return [Boolean]
+		Name: "file?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			fs, err := os.Stat(args[0].Value().(string))
+
+			return toBooleanObject(err == nil && fs.Mode().IsRegular())
+
+		},
+	},
+	{
+		// Yields each line of the file, including the trailing newline, and closes the file after the block
+		// is executed, or when the block raises an error.
+		// Returns a lazy enumerator of the lines without a block, which keeps the file open.
+		//
+		// ```ruby
+		// File.foreach("/var/log/app.log") do |line|
+		//   puts(line)
+		// end
+		//
+		// File.foreach("/var/log/app.log").first(10)
+		// ```
+		//
+		// @param fileName [String]
+		// @return [Null]
+		Name: "foreach",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			var errObj *Error
+			var file *os.File
+
+			if len(args) != 1 {
+				errObj = t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			} else if fn, ok := args[0].(*StringObject); !ok {
+				errObj = t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+			} else {
+				var err error
+				file, err = os.Open(fn.value)
+
+				if err != nil {
+					errObj = t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+				}
+			}
+
+			if errObj != nil {
+				if blockFrame != nil {
+					t.callFrameStack.pop()
+				}
+
+				return errObj
+			}
+
+			f := t.vm.initFileObject(file)
+
+			if blockFrame == nil {
+				return t.callMethod(f, "each_line", sourceLine)
+			}
+
+			// Closes the file even if the block raises an error
+			defer file.Close()
+
+			if err := t.yieldLines(f.bufReader(), blockFrame, sourceLine); err != nil {
+				return err
+			}
+
+			return NULL
+
+		},
+	},
 	{
 		// Returns the string with joined elements.
 		// Arguments can be zero.
@@ -216,16 +411,93 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns a `File::Stat` object of the given path like `File.stat`, but doesn't follow symbolic links.
+		//
+		// ```ruby
+		// File.lstat("link").symlink? # => true
+		// ```
+		//
+		// @param path [String]
+		// @return [File::Stat]
+		Name: "lstat",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			path := args[0].Value().(string)
+			fs, err := os.Lstat(path)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.initFileStatObject(path, fs)
+
+		},
+	},
+	{
+		// Returns the last modification time of the given path, in seconds since the Unix epoch.
+		//
+		// ```ruby
+		// File.mtime("loop.gb") # => 1514764800.123456
+		// File.mtime("a.gb") > File.mtime("b.gb")
+		// ```
+		//
+		// @param path [String]
+		// @return [Float]
+		Name: "mtime",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			fs, err := os.Stat(args[0].Value().(string))
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.initFloatObject(unixSeconds(fs.ModTime()))
+
+		},
+	},
 	{
 		// Finds the file with given fileName and initializes a file object with it.
-		// File permissions can be specified at the second or third argument.
+		// The mode can be specified at the second argument:
+		//
+		// - "r": read only, which is the default.
+		// - "r+": read and write. The file is created if it doesn't exist.
+		// - "w": write only. The file is created or truncated.
+		// - "w+": read and write. The file is created or truncated.
+		// - "a": write only, at the end of the file. The file is created if it doesn't exist.
+		// - "a+": read, and write at the end of the file. The file is created if it doesn't exist.
+		//
+		// A "b" can be added to the mode, like "rb", which doesn't change anything since Goby's strings
+		// can hold any bytes. The permission of a created file can be specified at the third argument,
+		// which is 0755 by default.
 		//
 		// ```ruby
 		// File.new("./samples/server.gb")
 		//
 		// File.new("../test_fixtures/file_test/size.gb", "r")
 		//
-		// File.new("../test_fixtures/file_test/size.gb", "r", 0755)
+		// File.new("/tmp/goby/out.txt", "w", 0755)
+		//
+		// File.new("/tmp/goby/app.log", "a")
 		// ```
 		// @param fileName [String]
 		// @return [File]
@@ -249,17 +521,12 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.StringClass, args[1].Class().Name)
 				}
 
-				md, ok := fileModeTable[m.value]
+				md, ok := fileModeTable[strings.Replace(m.value, "b", "", 1)]
 				if !ok {
 					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Unknown file mode: %s", m.value)
 				}
 
-				if md == syscall.O_RDWR || md == syscall.O_WRONLY {
-					os.Create(fn.value)
-				}
-
 				mod = md
-				perm = os.FileMode(0755)
 
 				if aLen == 3 {
 					p, ok := args[2].(*IntegerObject)
@@ -267,11 +534,17 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 3, classes.IntegerClass, args[2].Class().Name)
 					}
 
-					if !os.FileMode(p.value).IsRegular() {
-						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidChmodNumber, p.value)
+					value, errObj := p.intValue(t, sourceLine)
+
+					if errObj != nil {
+						return errObj
+					}
+
+					if !os.FileMode(value).IsRegular() {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidChmodNumber, value)
 					}
 
-					perm = os.FileMode(p.value)
+					perm = os.FileMode(value)
 				}
 			}
 
@@ -281,10 +554,39 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
 			}
 
-			// TODO: Refactor this class retrieval mess
-			fo := &FileObject{File: f, BaseObj: NewBaseObject(t.vm.TopLevelClass(classes.FileClass))}
+			return t.vm.initFileObject(f)
+
+		},
+	},
+	{
+		// Renames or moves a file or a directory. An existing file at the new path is replaced.
+		//
+		// ```ruby
+		// File.rename("a.txt", "b.txt") # => 0
+		// ```
+		//
+		// @param oldPath [String]
+		// @param newPath [String]
+		// @return [Integer]
+		Name: "rename",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			err := os.Rename(args[0].Value().(string), args[1].Value().(string))
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
 
-			return fo
+			return t.vm.InitIntegerObject(0)
 
 		},
 	},
@@ -325,6 +627,41 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns a `File::Stat` object with the information of the given path.
+		//
+		// ```ruby
+		// stat = File.stat("loop.gb")
+		// stat.size       # => 321123
+		// stat.directory? # => false
+		// stat.mode       # => 420
+		// ```
+		//
+		// @param path [String]
+		// @return [File::Stat]
+		Name: "stat",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			path := args[0].Value().(string)
+			fs, err := os.Stat(path)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.initFileStatObject(path, fs)
+
+		},
+	},
 	{
 		// Returns array of path and file.
 		//
@@ -352,12 +689,39 @@ var builtinFileClassMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns true if the given path is a symbolic link.
+		//
+		// ```ruby
+		// File.symlink?("link") # => true
+		// ```
+		//
+		// @param path [String]
+		// @return [Boolean]
+		Name: "symlink?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			fs, err := os.Lstat(args[0].Value().(string))
+
+			return toBooleanObject(err == nil && fs.Mode()&os.ModeSymlink != 0)
+
+		},
+	},
 }
 
 // Instance methods -----------------------------------------------------
 var builtinFileInstanceMethods = []*BuiltinMethodObject{
 	{
-		// Closes the instance of File class. Possible to close twice.
+		// Flushes the written data and closes the instance of File class. Possible to close twice.
 		//
 		// ```ruby
 		// File.open("/tmp/goby/out.txt", "w", 0755) do |f|
@@ -372,58 +736,303 @@ var builtinFileInstanceMethods = []*BuiltinMethodObject{
 		// @return [Null]
 		Name: "close",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			file := receiver.(*FileObject).File
-			file.Close()
+			receiver.(*FileObject).close()
 
 			return NULL
 
 		},
 	},
-	// Returns the path and the file name.
-	//
-	// ```ruby
-	// File.open("/tmp/goby/out.txt", "w", 0755) do |f|
-	//   puts f.name      #=> "/tmp/goby/out.txt"
-	// end
-	// ```
-	//
-	// @return [String]
 	{
-		Name: "name",
+		// Yields each line from the current position, including the trailing newline.
+		// Returns a lazy enumerator of the lines without a block, so only the lines that are needed are read.
+		//
+		// ```ruby
+		// File.new("/var/log/app.log").each_line do |line|
+		//   puts(line)
+		// end
+		//
+		// File.new("/var/log/app.log").each_line.first(10)
+		// ```
+		//
+		// @return [File]
+		Name: "each_line",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			name := receiver.(*FileObject).File.Name()
-			return t.vm.InitStringObject(name)
+			if len(args) != 0 {
+				if blockFrame != nil {
+					t.callFrameStack.pop()
+				}
 
-		},
-	},
-	// Returns the contents of the specified file.
-	//
-	// ```ruby
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			if blockFrame == nil {
+				return t.lineEnumerator(receiver, sourceLine)
+			}
+
+			if err := t.yieldLines(receiver.(*FileObject).bufReader(), blockFrame, sourceLine); err != nil {
+				return err
+			}
+
+			return receiver
+
+		},
+	},
+	{
+		// Returns true if there's nothing left to read from the current position.
+		//
+		// ```ruby
+		// f = File.new("/tmp/goby/out.txt")
+		// while !f.eof? do
+		//   puts(f.gets)
+		// end
+		// ```
+		//
+		// @return [Boolean]
+		Name: "eof?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			_, err := receiver.(*FileObject).bufReader().Peek(1)
+
+			if err == io.EOF {
+				return TRUE
+			}
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return FALSE
+
+		},
+	},
+	{
+		// Writes the data written so far to the file. Written data is already flushed by `write`, `puts` and `print`,
+		// so this is kept for compatibility.
+		//
+		// ```ruby
+		// f = File.new("/tmp/goby/out.txt", "w")
+		// f.write("Hello")
+		// f.flush
+		// ```
+		//
+		// @return [File]
+		Name: "flush",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			err := receiver.(*FileObject).flush()
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return receiver
+
+		},
+	},
+	{
+		// Reads the next line including the trailing newline, or returns nil at the end of the file.
+		//
+		// ```ruby
+		// File.open("/tmp/goby/out.txt", "r") do |f|
+		//   f.gets # => "first line\n"
+		// end
+		// ```
+		//
+		// @return [String]
+		Name: "gets",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			line, err := receiver.(*FileObject).bufReader().ReadString('\n')
+
+			if err == io.EOF && len(line) == 0 {
+				return NULL
+			}
+
+			if err != nil && err != io.EOF {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitStringObject(line)
+
+		},
+	},
+	// Returns the path and the file name.
+	//
+	// ```ruby
+	// File.open("/tmp/goby/out.txt", "w", 0755) do |f|
+	//   puts f.name      #=> "/tmp/goby/out.txt"
+	// end
+	// ```
+	//
+	// @return [String]
+	{
+		Name: "name",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			name := receiver.(*FileObject).File.Name()
+			return t.vm.InitStringObject(name)
+
+		},
+	},
+	{
+		// Returns the current position in bytes.
+		//
+		// ```ruby
+		// File.open("/tmp/goby/out.txt", "r") do |f|
+		//   f.gets
+		//   f.pos # => 11
+		// end
+		// ```
+		//
+		// @return [Integer]
+		Name: "pos",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			pos, err := receiver.(*FileObject).pos()
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitIntegerObject(int(pos))
+
+		},
+	},
+	{
+		// Writes the given objects like `write`, converting non-string objects with `to_s`.
+		//
+		// ```ruby
+		// STDERR.print("Loading", "...")
+		// ```
+		//
+		// @return [Null]
+		Name: "print",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			var data strings.Builder
+
+			for _, arg := range args {
+				data.WriteString(arg.ToString())
+			}
+
+			_, err := receiver.(*FileObject).write(data.String())
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return NULL
+
+		},
+	},
+	{
+		// Writes the given objects with a trailing line feed each, converting non-string objects with `to_s`.
+		//
+		// ```ruby
+		// $stderr.puts("Something went wrong")
+		//
+		// File.open("/tmp/goby/out.txt", "w") do |f|
+		//   f.puts("foo", "bar")
+		// end
+		// ```
+		//
+		// @return [Null]
+		Name: "puts",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			_, err := receiver.(*FileObject).write(linesOf(args))
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return NULL
+
+		},
+	},
+	// Returns the contents of the specified file.
+	// If a length is given, reads at most that many bytes from the current position instead,
+	// and returns nil at the end of the file. The bytes are returned as a binary String, so binary files can be read too.
+	//
+	// ```ruby
 	// File.open("/tmp/goby/out.txt", "w", 0755) do |f|
 	//   f.write("Hello, Goby!")
 	//   puts f.read      #=> "Hello, Goby!"
 	// end
+	//
+	// File.open("/tmp/goby/image.png", "rb") do |f|
+	//   f.read(8)        #=> "\x89PNG\r\n\x1A\n"
+	// end
 	// ```
 	//
+	// @param length [Integer]
 	// @return [String]
 	{
 		Name: "read",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			file := receiver.(*FileObject)
+
+			if len(args) == 1 {
+				n, ok := args[0].(*IntegerObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+				}
+
+				length, errObj := n.intValue(t, sourceLine)
+
+				if errObj != nil {
+					return errObj
+				}
+
+				if length < 0 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, length)
+				}
+
+				// The buffer grows as the data is read, so a large length doesn't allocate the memory up front
+				var buf bytes.Buffer
+				l, err := io.CopyN(&buf, file.bufReader(), int64(length))
+
+				if err == io.EOF && l == 0 && length > 0 {
+					return NULL
+				}
+
+				if err != nil && err != io.EOF {
+					return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+				}
+
+				return t.vm.initBinaryStringObject(buf.String())
+			}
+
 			var result string
 			var f []byte
 			var err error
 
-			file := receiver.(*FileObject).File
-
-			if file.Name() == "/dev/stdin" {
-				reader := bufio.NewReader(os.Stdin)
-				result, err = reader.ReadString('\n')
+			if file.File.Name() == "/dev/stdin" {
+				result, err = file.bufReader().ReadString('\n')
 			} else {
-				f, err = ioutil.ReadFile(file.Name())
-				result = string(f)
+				err = file.flush()
+
+				if err == nil {
+					f, err = ioutil.ReadFile(file.File.Name())
+					result = string(f)
+				}
 			}
 
-			if err != nil {
+			if err != nil && err != io.EOF {
 				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
 			}
 
@@ -431,6 +1040,109 @@ var builtinFileInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Reads the next line like `gets`, but raises an EOFError at the end of the file.
+		//
+		// ```ruby
+		// File.open("/tmp/goby/out.txt", "r") do |f|
+		//   f.readline # => "first line\n"
+		// end
+		// ```
+		//
+		// @return [String]
+		Name: "readline",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			line, err := receiver.(*FileObject).bufReader().ReadString('\n')
+
+			if err == io.EOF && len(line) == 0 {
+				return t.vm.InitErrorObject(errors.EOFError, sourceLine, errors.EndOfFile)
+			}
+
+			if err != nil && err != io.EOF {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitStringObject(line)
+
+		},
+	},
+	{
+		// Moves the position back to the start of the file.
+		//
+		// @return [Integer]
+		Name: "rewind",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			_, err := receiver.(*FileObject).seek(0, io.SeekStart)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitIntegerObject(0)
+
+		},
+	},
+	{
+		// Moves the position to the given offset in bytes. The offset is relative to the start of the file
+		// by default, or to `File::SEEK_CUR` or `File::SEEK_END` if it's given as the second argument.
+		//
+		// ```ruby
+		// File.open("/tmp/goby/out.txt", "r") do |f|
+		//   f.seek(6)
+		//   f.read(4)
+		//   f.seek(-3, File::SEEK_END)
+		// end
+		// ```
+		//
+		// @param offset [Integer]
+		// @param whence [Integer]
+		// @return [Integer]
+		Name: "seek",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			offset, whence := 0, io.SeekStart
+
+			for i, arg := range args {
+				n, ok := arg.(*IntegerObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.IntegerClass, arg.Class().Name)
+				}
+
+				value, errObj := n.intValue(t, sourceLine)
+
+				if errObj != nil {
+					return errObj
+				}
+
+				if i == 0 {
+					offset = value
+				} else {
+					whence = value
+				}
+			}
+
+			_, err := receiver.(*FileObject).seek(int64(offset), whence)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitIntegerObject(0)
+
+		},
+	},
 	{
 		// Returns size of file in bytes.
 		//
@@ -441,9 +1153,10 @@ var builtinFileInstanceMethods = []*BuiltinMethodObject{
 		// @return [Integer]
 		Name: "size",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			file := receiver.(*FileObject).File
+			file := receiver.(*FileObject)
+			file.flush()
 
-			fileStats, err := os.Stat(file.Name())
+			fileStats, err := os.Stat(file.File.Name())
 			if err != nil {
 				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
 			}
@@ -453,14 +1166,37 @@ var builtinFileInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
+		// Writes the given objects, converting non-string objects with `to_s`, and returns the number of bytes written.
+		// The data is flushed at once, so it can be read from the other handles of the file before it's closed.
+		//
+		// ```ruby
+		// File.open("/tmp/goby/out.txt", "w") do |f|
+		//   f.write("a", 1, "\n") # => 3
+		// end
+		// ```
+		//
+		// @return [Integer]
 		Name: "write",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			file := receiver.(*FileObject).File
-			data := args[0].(*StringObject).value
-			length, err := file.Write([]byte(data))
+			file := receiver.(*FileObject)
+			length := 0
 
-			if err != nil {
-				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			for _, arg := range args {
+				var data string
+
+				switch arg := arg.(type) {
+				case *StringObject:
+					data = arg.value
+				default:
+					data = arg.ToString()
+				}
+
+				l, err := file.write(data)
+				length += l
+
+				if err != nil {
+					return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+				}
 			}
 
 			return t.vm.InitIntegerObject(length)
@@ -469,6 +1205,123 @@ var builtinFileInstanceMethods = []*BuiltinMethodObject{
 	},
 }
 
+// Class methods of File::Stat ------------------------------------------
+var builtinFileStatClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Instance methods of File::Stat ---------------------------------------
+var builtinFileStatInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Returns true if the path is a directory.
+		//
+		// @return [Boolean]
+		Name: "directory?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return toBooleanObject(receiver.(*FileStatObject).info.IsDir())
+
+		},
+	},
+	{
+		// Returns true if the path is a regular file.
+		//
+		// @return [Boolean]
+		Name: "file?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return toBooleanObject(receiver.(*FileStatObject).info.Mode().IsRegular())
+
+		},
+	},
+	{
+		// Returns the permission bits of the path.
+		//
+		// ```ruby
+		// File.stat("loop.gb").mode.to_s(8) # => "644"
+		// ```
+		//
+		// @return [Integer]
+		Name: "mode",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(int(receiver.(*FileStatObject).info.Mode().Perm()))
+
+		},
+	},
+	{
+		// Returns the last modification time, in seconds since the Unix epoch.
+		//
+		// @return [Float]
+		Name: "mtime",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.initFloatObject(unixSeconds(receiver.(*FileStatObject).info.ModTime()))
+
+		},
+	},
+	{
+		// Returns the last element of the path.
+		//
+		// @return [String]
+		Name: "name",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(receiver.(*FileStatObject).info.Name())
+
+		},
+	},
+	{
+		// Returns the size in bytes.
+		//
+		// @return [Integer]
+		Name: "size",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(int(receiver.(*FileStatObject).info.Size()))
+
+		},
+	},
+	{
+		// Returns true if the path is a symbolic link. Only a `File::Stat` returned by `File.lstat` can be a link.
+		//
+		// @return [Boolean]
+		Name: "symlink?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return toBooleanObject(receiver.(*FileStatObject).info.Mode()&os.ModeSymlink != 0)
+
+		},
+	},
+}
+
 // Internal functions ===================================================
 
 // Functions for initialization -----------------------------------------
@@ -480,11 +1333,41 @@ func (vm *VM) initFileObject(f *os.File) *FileObject {
 	}
 }
 
+// initStandardFileObject returns a file object of STDOUT or STDERR, which writes to w at once
+func (vm *VM) initStandardFileObject(f *os.File, w io.Writer) *FileObject {
+	return &FileObject{
+		BaseObj: NewBaseObject(vm.TopLevelClass(classes.FileClass)),
+		File:    f,
+		writer:  bufio.NewWriter(w),
+		output:  w,
+	}
+}
+
+func (vm *VM) initFileStatObject(path string, info os.FileInfo) *FileStatObject {
+	return &FileStatObject{
+		BaseObj: NewBaseObject(vm.TopLevelClass(classes.FileClass).getClassConstant(classes.FileStatClass)),
+		path:    path,
+		info:    info,
+	}
+}
+
+// initFileConstants sets File's constants, which are Integer objects
+func (vm *VM) initFileConstants(fc *RClass) {
+	fc.setConstant("SEEK_SET", &Pointer{Target: vm.InitIntegerObject(io.SeekStart)})
+	fc.setConstant("SEEK_CUR", &Pointer{Target: vm.InitIntegerObject(io.SeekCurrent)})
+	fc.setConstant("SEEK_END", &Pointer{Target: vm.InitIntegerObject(io.SeekEnd)})
+}
+
 func (vm *VM) initFileClass() *RClass {
 	fc := vm.initializeClass(classes.FileClass)
 	fc.setBuiltinMethods(builtinFileClassMethods, true)
 	fc.setBuiltinMethods(builtinFileInstanceMethods, false)
 
+	sc := vm.initializeClass(classes.FileStatClass)
+	sc.setBuiltinMethods(builtinFileStatClassMethods, true)
+	sc.setBuiltinMethods(builtinFileStatInstanceMethods, false)
+	fc.setClassConstant(sc)
+
 	vm.libFiles = append(vm.libFiles, "file.gb")
 
 	return fc
@@ -502,12 +1385,263 @@ func (f *FileObject) Inspect() string {
 	return f.ToString()
 }
 
-// ToJSON just delegates to `ToString`
+// ToJSON returns the quoted ToString
 func (f *FileObject) ToJSON(t *Thread) string {
-	return f.ToString()
+	return jsonString(f.ToString())
 }
 
 // Value returns file object's string format
 func (f *FileObject) Value() interface{} {
 	return f.File
 }
+
+// ToString returns the object's name as the string format
+func (s *FileStatObject) ToString() string {
+	return "#<File::Stat: " + s.path + ">"
+}
+
+// Inspect delegates to ToString
+func (s *FileStatObject) Inspect() string {
+	return s.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (s *FileStatObject) ToJSON(t *Thread) string {
+	return strconv.Quote(s.ToString())
+}
+
+// Value returns the stat's os.FileInfo
+func (s *FileStatObject) Value() interface{} {
+	return s.info
+}
+
+// Other helper functions -----------------------------------------------
+
+// bufReader returns the file's buffered reader. Buffered written data is flushed first,
+// so that it can be read.
+func (f *FileObject) bufReader() *bufio.Reader {
+	f.flush()
+
+	if f.reader == nil {
+		f.reader = bufio.NewReader(f.File)
+	}
+
+	return f.reader
+}
+
+// write writes data with the file's writer, which is flushed at once so that the data is visible
+// to the other readers of the file. Data that has been read ahead is discarded first,
+// so that data is written at the current position.
+func (f *FileObject) write(data string) (int, error) {
+	if f.reader != nil && f.reader.Buffered() > 0 {
+		_, err := f.seek(0, io.SeekCurrent)
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if f.writer == nil {
+		f.writer = bufio.NewWriter(f.File)
+	}
+
+	n, err := f.writer.WriteString(data)
+
+	if err == nil {
+		err = f.writer.Flush()
+	}
+
+	return n, err
+}
+
+// processOutput returns the writer for a child process's output to the file. Buffered data is flushed first,
+// and the file itself is returned when possible, so that the process writes to it directly.
+func (f *FileObject) processOutput() (io.Writer, error) {
+	err := f.flush()
+
+	if err == nil && f.reader != nil && f.reader.Buffered() > 0 {
+		_, err = f.seek(0, io.SeekCurrent)
+	}
+
+	if f.output != nil {
+		return f.output, err
+	}
+
+	return f.File, err
+}
+
+// flush writes the buffered data to the file
+func (f *FileObject) flush() error {
+	if f.writer == nil {
+		return nil
+	}
+
+	return f.writer.Flush()
+}
+
+// close flushes the buffered data and closes the file
+func (f *FileObject) close() error {
+	err := f.flush()
+
+	if closeErr := f.File.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// pos returns the current position, taking the buffered data into account
+func (f *FileObject) pos() (int64, error) {
+	err := f.flush()
+
+	if err != nil {
+		return 0, err
+	}
+
+	pos, err := f.File.Seek(0, io.SeekCurrent)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if f.reader != nil {
+		pos -= int64(f.reader.Buffered())
+	}
+
+	return pos, nil
+}
+
+// seek sets the current position like os.File's Seek, and discards the data that has been read ahead
+func (f *FileObject) seek(offset int64, whence int) (int64, error) {
+	err := f.flush()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if f.reader != nil {
+		if whence == io.SeekCurrent {
+			offset -= int64(f.reader.Buffered())
+		}
+
+		f.reader.Reset(f.File)
+	}
+
+	return f.File.Seek(offset, whence)
+}
+
+// yieldLines yields each line read from r, including the trailing newline.
+// The block's call frame is popped if nothing is yielded.
+func (t *Thread) yieldLines(r *bufio.Reader, blockFrame *normalCallFrame, sourceLine int) *Error {
+	yielded := false
+
+	for {
+		line, err := r.ReadString('\n')
+
+		if err != nil && err != io.EOF {
+			if !yielded {
+				t.callFrameStack.pop()
+			}
+
+			return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+		}
+
+		if line != "" {
+			t.builtinMethodYield(blockFrame, t.vm.InitStringObject(line))
+			yielded = true
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if !yielded {
+		t.callFrameStack.pop()
+	}
+
+	return nil
+}
+
+// lineEnumerator returns a lazy enumerator of the lines read from the File or StringIO
+func (t *Thread) lineEnumerator(io Object, sourceLine int) Object {
+	enumerator := t.callMethod(t.vm.TopLevelClass("FileLineEnumerator"), "new", sourceLine, io)
+	return t.callMethod(t.vm.TopLevelClass("LazyEnumerator"), "new", sourceLine, enumerator)
+}
+
+// writeOutput writes data to the object assigned to the given global variable, which is `$stdout` or `$stderr`.
+// File objects are written directly, and other objects receive `write` with the data.
+func (t *Thread) writeOutput(globalName, data string, sourceLine int) *Error {
+	return t.writeTo(t.vm.getGlobal(globalName), data, sourceLine)
+}
+
+// writeTo writes the data to a File or StringIO directly, or by calling the object's `write` method
+func (t *Thread) writeTo(output Object, data string, sourceLine int) *Error {
+	switch out := output.(type) {
+	case *FileObject:
+		_, err := out.write(data)
+
+		if err != nil {
+			return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+		}
+	case *StringIOObject:
+		return out.writeObjects(t, []Object{t.vm.InitStringObject(data)}, sourceLine)
+	default:
+		t.callMethod(out, "write", sourceLine, t.vm.InitStringObject(data))
+	}
+
+	return nil
+}
+
+// linesOf joins the objects' string formats, with a trailing line feed each
+func linesOf(objects []Object) string {
+	var lines strings.Builder
+
+	for _, obj := range objects {
+		lines.WriteString(obj.ToString())
+		lines.WriteString("\n")
+	}
+
+	return lines.String()
+}
+
+// expandPath returns the absolute path of path, which is relative to dir, or the working directory
+// if dir is empty. A leading "~" in either of them is expanded to the home directory.
+func expandPath(path, dir string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+
+		if err != nil {
+			return "", err
+		}
+
+		path = home + path[1:]
+	}
+
+	if !filepath.IsAbs(path) {
+		if dir == "" {
+			wd, err := os.Getwd()
+
+			if err != nil {
+				return "", err
+			}
+
+			dir = wd
+		}
+
+		base, err := expandPath(dir, "")
+
+		if err != nil {
+			return "", err
+		}
+
+		path = filepath.Join(base, path)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+// unixSeconds returns the given time in seconds since the Unix epoch
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
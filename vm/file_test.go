@@ -1,6 +1,8 @@
 package vm
 
 import (
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"testing"
 )
@@ -67,6 +69,83 @@ func TestFileObjectFail(t *testing.T) {
 	}
 }
 
+func TestFileStatMethods(t *testing.T) {
+	setup()
+	defer teardown()
+
+	os.Remove("/tmp/goby/link.gb")
+	os.Symlink("/tmp/goby/target.gb", "/tmp/goby/link.gb")
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`File.stat("../test_fixtures/file_test/size.gb").size`, 22},
+		{`File.stat("../test_fixtures/file_test/size.gb").name`, "size.gb"},
+		{`File.stat("../test_fixtures/file_test/size.gb").file?`, true},
+		{`File.stat("../test_fixtures/file_test").directory?`, true},
+		{`File.stat("../test_fixtures/file_test/size.gb").symlink?`, false},
+		{`File.stat("../test_fixtures/file_test/size.gb").class.name`, "Stat"},
+		{`
+		File.new("/tmp/goby/stat.txt", "w", 0755).close
+		File.chmod(0640, "/tmp/goby/stat.txt")
+		File.stat("/tmp/goby/stat.txt").mode.to_s(8)
+		`, "640"},
+		{`File.mtime("../test_fixtures/file_test/size.gb") > 0.0`, true},
+		{`File.stat("../test_fixtures/file_test/size.gb").mtime == File.mtime("../test_fixtures/file_test/size.gb")`, true},
+		{`File.directory?("../test_fixtures/file_test")`, true},
+		{`File.directory?("../test_fixtures/file_test/size.gb")`, false},
+		{`File.directory?("../test_fixtures/non-existent")`, false},
+		{`File.file?("../test_fixtures/file_test/size.gb")`, true},
+		{`File.file?("../test_fixtures/file_test")`, false},
+		{`File.symlink?("../test_fixtures/file_test/size.gb")`, false},
+		{`File.symlink?("/tmp/goby/link.gb")`, true},
+		{`File.lstat("/tmp/goby/link.gb").symlink?`, true},
+		{`
+		File.new("/tmp/goby/rename1.txt", "w", 0755).close
+		File.rename("/tmp/goby/rename1.txt", "/tmp/goby/rename2.txt")
+		[File.exist?("/tmp/goby/rename1.txt"), File.exist?("/tmp/goby/rename2.txt")]
+		`, []interface{}{false, true}},
+		{`File.expand_path("lib/../plugin", "/home/goby")`, "/home/goby/plugin"},
+		{`File.expand_path("/home/goby/./lib/")`, "/home/goby/lib"},
+		{`Dir.chdir("/tmp/goby") do File.expand_path("a.gb") end`, "/tmp/goby/a.gb"},
+		{`File.expand_path("~/a.gb") == File.join(ENV["HOME"], "a.gb")`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileStatMethodsFail(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testsFail := []errorTestCase{
+		{`File.stat("/tmp/goby/non-existent.txt")`, "IOError: stat /tmp/goby/non-existent.txt: no such file or directory", 1},
+		{`File.lstat("/tmp/goby/non-existent.txt")`, "IOError: lstat /tmp/goby/non-existent.txt: no such file or directory", 1},
+		{`File.mtime(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`File.rename("/tmp/goby/non-existent.txt", "/tmp/goby/a.txt")`, "IOError: rename /tmp/goby/non-existent.txt /tmp/goby/a.txt: no such file or directory", 1},
+		{`File.rename("/tmp/goby/a.txt")`, "ArgumentError: Expect 2 argument(s). got: 1", 1},
+		{`File.expand_path("a", 1)`, "TypeError: Expect argument #2 to be String. got: Integer", 1},
+		{`File::Stat.new`, "NoMethodError: Undefined Method 'new' for Stat", 1},
+		{`File.stat("../test_fixtures/file_test/size.gb").size(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`File.stat("../test_fixtures/file_test/size.gb").file?(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
 // Tests for class methods
 func TestFileBasenameMethod(t *testing.T) {
 	setup()
@@ -613,6 +692,200 @@ func TestFileInstanceSizeMethod(t *testing.T) {
 	v.checkSP(t, 0, 1)
 }
 
+func TestFileStreamingMethods(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		File.open("/tmp/goby/lines.txt", "w") do |f|
+		  f.write("one\ntwo\nthree")
+		end
+
+		f = File.new("/tmp/goby/lines.txt")
+		[f.gets, f.gets, f.gets, f.gets]
+		`, []interface{}{"one\n", "two\n", "three", nil}},
+		{`
+		File.open("/tmp/goby/lines.txt", "w") do |f|
+		  f.write("one\ntwo\n")
+		end
+
+		lines = []
+		File.foreach("/tmp/goby/lines.txt") do |line|
+		  lines.push(line)
+		end
+		lines
+		`, []interface{}{"one\n", "two\n"}},
+		{`
+		File.open("/tmp/goby/lines.txt", "w") do |f|
+		  f.write("one\ntwo\nthree\n")
+		end
+
+		File.foreach("/tmp/goby/lines.txt").map do |line|
+		  line.size
+		end.first(2)
+		`, []interface{}{4, 4}},
+		{`
+		File.open("/tmp/goby/lines.txt", "w") do |f|
+		  f.write("one\ntwo\n")
+		end
+
+		f = File.new("/tmp/goby/lines.txt")
+		f.gets
+		f.each_line do |line|
+		  line
+		end.pos
+		`, 8},
+		{`
+		File.open("/tmp/goby/lines.txt", "w") do |f|
+		  f.write("one\ntwo\n")
+		end
+
+		out = StringIO.new
+		$stdout = out
+		File.new("/tmp/goby/lines.txt").each_line do |line|
+		  puts(line.upcase)
+		end
+		$stdout = STDOUT
+		out.string
+		`, "ONE\n\nTWO\n\n"},
+		{`
+		File.open("/tmp/goby/bytes.bin", "w") do |f|
+		  f.write("0123456789")
+		end
+
+		f = File.new("/tmp/goby/bytes.bin", "rb")
+		a = f.read(4)
+		p1 = f.pos
+		f.seek(-2, File::SEEK_END)
+		b = f.read(5)
+		c = f.read(1)
+		f.seek(2)
+		f.seek(1, File::SEEK_CUR)
+		d = f.read(2)
+		f.rewind
+		[a, p1, b, c, d, f.read(1), f.eof?]
+		`, []interface{}{"0123", 4, "89", nil, "34", "0", false}},
+		{`
+		File.open("/tmp/goby/bytes.bin", "w") do |f|
+		  f.write("héllo")
+		end
+
+		f = File.new("/tmp/goby/bytes.bin", "rb")
+		a = f.read(2)
+		[a.encoding, a.length, f.read.encoding]
+		`, []interface{}{"ASCII-8BIT", 2, "UTF-8"}},
+		{`
+		File.open("/tmp/goby/append.txt", "w") do |f|
+		  f.write("a")
+		end
+		File.open("/tmp/goby/append.txt", "a") do |f|
+		  f.write("b", 1, "\n")
+		end
+		File.new("/tmp/goby/append.txt").read
+		`, "ab1\n"},
+		{`
+		f = File.new("/tmp/goby/flush.txt", "w")
+		f.write("hi")
+		f.puts("!")
+		result = [File.size("/tmp/goby/flush.txt"), File.new("/tmp/goby/flush.txt").read]
+		f.close
+		result
+		`, []interface{}{4, "hi!\n"}},
+		{`
+		File.open("/tmp/goby/rw.txt", "w") do |f|
+		  f.write("hello world")
+		end
+
+		result = nil
+		File.open("/tmp/goby/rw.txt", "r+") do |f|
+		  f.read(6)
+		  f.write("goby!")
+		  f.rewind
+		  result = f.gets
+		end
+		result
+		`, "hello goby!"},
+		{`
+		File.open("/tmp/goby/empty.txt", "w")
+		f = File.new("/tmp/goby/empty.txt")
+		[f.eof?, f.read(1), f.read(0)]
+		`, []interface{}{true, nil, ""}},
+		{`
+		File.open("/tmp/goby/small.txt", "w") do |f|
+		  f.write("small")
+		end
+
+		File.new("/tmp/goby/small.txt").read(2 ** 40)
+		`, "small"},
+		{`
+		File.open("/tmp/goby/empty.txt", "w")
+		lines = 0
+		File.foreach("/tmp/goby/empty.txt") do |line|
+		  lines += 1
+		end
+		lines
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileStreamingMethodsFail(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testsFail := []errorTestCase{
+		{`File.open("/tmp/goby/empty.txt", "w"); File.new("/tmp/goby/empty.txt").readline`, "EOFError: End of file reached", 1},
+		{`File.new("/tmp/goby/empty.txt", "x")`, "ArgumentError: Unknown file mode: x", 1},
+		{`File.open("/tmp/goby/empty.txt", "w"); File.new("/tmp/goby/empty.txt").read("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`File.open("/tmp/goby/empty.txt", "w"); File.new("/tmp/goby/empty.txt").read(-1)`, "ArgumentError: Expect argument to be positive value. got: -1", 1},
+		{`File.open("/tmp/goby/empty.txt", "w"); File.new("/tmp/goby/empty.txt").seek(0, "1")`, "TypeError: Expect argument #2 to be Integer. got: String", 1},
+		{`File.open("/tmp/goby/empty.txt", "w"); File.new("/tmp/goby/empty.txt").seek(-1)`, "IOError: seek /tmp/goby/empty.txt: invalid argument", 1},
+		{`File.foreach("/tmp/goby/non-existent.txt") do |line| end`, "IOError: open /tmp/goby/non-existent.txt: no such file or directory", 1},
+		{`File.foreach(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileFlushedAtExit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	v := initTestVM()
+	v.testEval(t, `
+	f = File.new("/tmp/goby/unflushed.txt", "w")
+	f.write("hello")
+	`, getFilename())
+	v.RunExitHooks()
+
+	content, err := ioutil.ReadFile("/tmp/goby/unflushed.txt")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if string(content) != "hello" {
+		t.Errorf("Expect the file's content to be %q. got: %q", "hello", string(content))
+	}
+}
+
 func TestFileWriteMethod(t *testing.T) {
 	setup()
 	defer teardown()
@@ -461,7 +461,12 @@ var builtinFloatInstanceMethods = []*BuiltinMethodObject{
 					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 				}
 
-				precision = int.value
+				var err *Error
+				precision, err = int.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
 			}
 
 			f := receiver.(*FloatObject).floatValue()
@@ -487,6 +492,18 @@ func (vm *VM) initFloatClass() *RClass {
 	ic := vm.initializeClass(classes.FloatClass)
 	ic.setBuiltinMethods(builtinFloatInstanceMethods, false)
 	ic.setBuiltinMethods(builtinFloatClassMethods, true)
+
+	// The class isn't stored under Object yet, so the constants are initialized with it directly
+	constants := map[string]float64{
+		"INFINITY": math.Inf(1),
+		"NAN":      math.NaN(),
+		"EPSILON":  math.Nextafter(1, 2) - 1,
+	}
+
+	for name, value := range constants {
+		ic.setConstant(name, &Pointer{Target: &FloatObject{BaseObj: NewBaseObject(ic), value: value}})
+	}
+
 	return ic
 }
 
@@ -557,6 +574,15 @@ func (f *FloatObject) lessThan(arg Object) bool {
 // ToString returns the object's value as the string format, in non
 // exponential format (straight number, without exponent `E<exp>`).
 func (f *FloatObject) ToString() string {
+	switch {
+	case math.IsInf(f.value, 1):
+		return "Infinity"
+	case math.IsInf(f.value, -1):
+		return "-Infinity"
+	case math.IsNaN(f.value):
+		return "NaN"
+	}
+
 	s := strconv.FormatFloat(f.value, 'f', -1, 64)
 	// Add ".0" to represent a float number
 	if !strings.Contains(s, ".") {
@@ -524,3 +524,26 @@ func TestFloatZero(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestFloatConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Float::INFINITY.to_s`, "Infinity"},
+		{`(-Float::INFINITY).to_s`, "-Infinity"},
+		{`Float::INFINITY > 10000000.0`, true},
+		{`Float::NAN.to_s`, "NaN"},
+		{`Float::NAN == Float::NAN`, false},
+		{`Float::EPSILON`, 2.220446049250313e-16},
+		{`1.0 + Float::EPSILON > 1.0`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
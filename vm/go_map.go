@@ -152,7 +152,7 @@ func (m *GoMap) Inspect() string {
 	return m.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (m *GoMap) ToJSON(t *Thread) string {
-	return m.ToString()
+	return jsonString(m.ToString())
 }
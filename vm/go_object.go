@@ -90,9 +90,9 @@ func (s *GoObject) Inspect() string {
 	return s.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (s *GoObject) ToJSON(t *Thread) string {
-	return s.ToString()
+	return jsonString(s.ToString())
 }
 
 // Other helper functions -----------------------------------------------
@@ -0,0 +1,88 @@
+package vm
+
+import (
+	"encoding/hex"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Hex encodes the bytes of Strings into hexadecimal and decodes them back, with Go's `encoding/hex` package.
+//
+// ```ruby
+// require "hex"
+//
+// Hex.encode("Goby\n")       # => "476f62790a"
+// Hex.decode("476F62790A")   # => "Goby\n"
+// ```
+//
+// - `Hex.new` is not supported.
+
+// Class methods --------------------------------------------------------
+var builtinHexClassMethods = []*BuiltinMethodObject{
+	{
+		// Decodes the hexadecimal String into a binary String. The letters can be in either case.
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "decode",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			decoded, err := hex.DecodeString(args[0].(*StringObject).value)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidHex, err.Error())
+			}
+
+			return t.vm.initBinaryStringObject(string(decoded))
+
+		},
+	},
+	{
+		// Encodes the String into lowercase hexadecimal.
+		//
+		// @param string [String]
+		// @return [String]
+		Name: "encode",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			return t.vm.InitStringObject(hex.EncodeToString([]byte(args[0].(*StringObject).value)))
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initHexClass(vm *VM) {
+	class := vm.initializeClass("Hex")
+	class.setBuiltinMethods(builtinHexClassMethods, true)
+	vm.objectClass.setClassConstant(class)
+}
@@ -0,0 +1,45 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestHexMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`require "hex";Hex.encode("Goby\n")`, "476f62790a"},
+		{`require "hex";Hex.encode("")`, ""},
+		{`require "hex";Hex.decode("476F62790a")`, "Goby\n"},
+		{`require "hex";Hex.decode("c3a9").encoding`, "ASCII-8BIT"},
+		{`require "hex";Hex.decode("c3a9").force_encoding("UTF-8")`, "é"},
+		{`require "hex";Hex.decode(Hex.encode("Hello, Goby!"))`, "Hello, Goby!"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHexMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "hex";Hex.new`, "NoMethodError: Undefined Method 'new' for Hex", 1},
+		{`require "hex";Hex.encode`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`require "hex";Hex.encode(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "hex";Hex.decode("abc")`, "ArgumentError: Invalid hex - encoding/hex: odd length hex string", 1},
+		{`require "hex";Hex.decode("zz")`, "ArgumentError: Invalid hex - encoding/hex: invalid byte: U+007A 'z'", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
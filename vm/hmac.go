@@ -0,0 +1,214 @@
+package vm
+
+import (
+	"crypto/hmac"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// HMAC computes keyed-hash message authentication codes with Go's `crypto/hmac` package.
+// The algorithm is one of "MD5", "SHA1", "SHA256" and "SHA512", or the Digest class of it.
+//
+// ```ruby
+// require "hmac"
+//
+// signature = HMAC.hexdigest("SHA256", secret, payload)
+//
+// if HMAC.secure_compare(signature, request.headers["X-Signature"])
+//   # ...
+// end
+//
+// mac = HMAC.new("SHA1", "key")
+// mac.update("The quick brown fox ")
+// mac.update("jumps over the lazy dog")
+// mac.hexdigest # => "de7c9b85b8b78aa6bc8a7a36f70a90701c9db4d9"
+// ```
+//
+// The HMAC objects have the same methods as Digest objects, like `update`, `digest`, `hexdigest`, `base64digest` and `reset`.
+
+// Class methods --------------------------------------------------------
+var builtinHMACClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the base64 encoded HMAC of the data.
+		//
+		// ```ruby
+		// HMAC.base64digest("SHA256", "key", "data") # => "UDH+PZicbRU3oBP6bnOdojRj/a7DtwE32Cjjas4iG9A="
+		// ```
+		//
+		// @param algorithm [String], key [String], data [String]
+		// @return [String]
+		Name: "base64digest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			sum, err := hmacOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(base64.StdEncoding.EncodeToString(sum))
+
+		},
+	},
+	{
+		// Returns the raw bytes of the HMAC of the data as a binary String.
+		//
+		// @param algorithm [String], key [String], data [String]
+		// @return [String]
+		Name: "digest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			sum, err := hmacOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.initBinaryStringObject(string(sum))
+
+		},
+	},
+	{
+		// Returns the hex encoded HMAC of the data.
+		//
+		// ```ruby
+		// HMAC.hexdigest("SHA256", "key", "data") # => "5031fe3d989c6d1537a013fa6e739da23463fdaec3b70137d828e36ace221bd0"
+		// ```
+		//
+		// @param algorithm [String], key [String], data [String]
+		// @return [String]
+		Name: "hexdigest",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			sum, err := hmacOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(hex.EncodeToString(sum))
+
+		},
+	},
+	{
+		// Returns an HMAC object with the key to be updated incrementally.
+		//
+		// ```ruby
+		// mac = HMAC.new("SHA256", "key")
+		// mac << "data"
+		// ```
+		//
+		// @param algorithm [String], key [String]
+		// @return [HMAC]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			h, err := newHMAC(t, args[0], args[1], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.initDigestObject(receiver.(*RClass), "HMAC", h)
+
+		},
+	},
+	{
+		// Returns true if the Strings are the same. It takes the same time regardless of where they differ,
+		// so that signatures can be verified without leaking them by timing.
+		//
+		// ```ruby
+		// HMAC.secure_compare("abc", "abc") # => true
+		// HMAC.secure_compare("abc", "abd") # => false
+		// ```
+		//
+		// @param a [String], b [String]
+		// @return [Boolean]
+		Name: "secure_compare",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			a, b := args[0].(*StringObject).value, args[1].(*StringObject).value
+			return toBooleanObject(subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initHMACClass(vm *VM) {
+	class := vm.initializeClass("HMAC")
+	class.setBuiltinMethods(builtinHMACClassMethods, true)
+	class.setBuiltinMethods(builtinDigestInstanceMethods, false)
+	vm.objectClass.setClassConstant(class)
+}
+
+// Other helper functions -----------------------------------------------
+
+// newHMAC returns the HMAC hash of the algorithm, which is a String or a Digest class, with the key
+func newHMAC(t *Thread, algorithm Object, key Object, sourceLine int) (hash.Hash, *Error) {
+	var name string
+
+	switch a := algorithm.(type) {
+	case *StringObject:
+		name = a.value
+	case *RClass:
+		name = a.Name
+	default:
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, "String or Class", algorithm.Class().Name)
+	}
+
+	fn, ok := digestAlgorithms[strings.ToLower(name)]
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownDigest, name)
+	}
+
+	k, ok := key.(*StringObject)
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.StringClass, key.Class().Name)
+	}
+
+	return hmac.New(fn, []byte(k.value)), nil
+}
+
+// hmacOf returns the HMAC of the data with the algorithm and the key
+func hmacOf(t *Thread, args []Object, sourceLine int) ([]byte, *Error) {
+	if len(args) != 3 {
+		return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 3, len(args))
+	}
+
+	h, err := newHMAC(t, args[0], args[1], sourceLine)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := args[2].(*StringObject)
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 3, classes.StringClass, args[2].Class().Name)
+	}
+
+	io.WriteString(h, data.value)
+	return h.Sum(nil), nil
+}
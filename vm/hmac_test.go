@@ -0,0 +1,73 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestHMACMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`require "hmac";HMAC.hexdigest("SHA256", "key", "data")`, "5031fe3d989c6d1537a013fa6e739da23463fdaec3b70137d828e36ace221bd0"},
+		{`require "hmac";HMAC.hexdigest("sha1", "key", "The quick brown fox jumps over the lazy dog")`, "de7c9b85b8b78aa6bc8a7a36f70a90701c9db4d9"},
+		{`require "hmac";HMAC.hexdigest("MD5", "key", "The quick brown fox jumps over the lazy dog")`, "80070713463e7749b90c2dc24911e275"},
+		{`require "hmac";HMAC.base64digest("SHA256", "key", "data")`, "UDH+PZicbRU3oBP6bnOdojRj/a7DtwE32Cjjas4iG9A="},
+		{`
+		require "hex"
+		require "hmac"
+		Hex.encode(HMAC.digest("SHA512", "key", "data")) == HMAC.hexdigest("SHA512", "key", "data")
+		`, true},
+		{`
+		require "digest"
+		require "hmac"
+		HMAC.hexdigest(Digest::SHA256, "key", "data")
+		`, "5031fe3d989c6d1537a013fa6e739da23463fdaec3b70137d828e36ace221bd0"},
+		{`
+		require "hmac"
+		mac = HMAC.new("SHA1", "key")
+		mac.update("The quick brown fox ")
+		mac << "jumps over the lazy dog"
+		mac.hexdigest
+		`, "de7c9b85b8b78aa6bc8a7a36f70a90701c9db4d9"},
+		{`
+		require "hmac"
+		mac = HMAC.new("SHA256", "key")
+		mac.update("garbage")
+		mac.reset
+		mac.update("data")
+		mac.inspect
+		`, "#<HMAC: 5031fe3d989c6d1537a013fa6e739da23463fdaec3b70137d828e36ace221bd0>"},
+		{`require "hmac";HMAC.secure_compare("abc", "abc")`, true},
+		{`require "hmac";HMAC.secure_compare("abc", "abd")`, false},
+		{`require "hmac";HMAC.secure_compare("abc", "ab")`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHMACMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "hmac";HMAC.new("SHA256")`, "ArgumentError: Expect 2 argument(s). got: 1", 1},
+		{`require "hmac";HMAC.hexdigest("SHA256", "key")`, "ArgumentError: Expect 3 argument(s). got: 2", 1},
+		{`require "hmac";HMAC.hexdigest("SHA3", "key", "data")`, "ArgumentError: Unknown digest algorithm - SHA3", 1},
+		{`require "hmac";HMAC.hexdigest(1, "key", "data")`, "TypeError: Expect argument #1 to be String or Class. got: Integer", 1},
+		{`require "hmac";HMAC.hexdigest("SHA256", nil, "data")`, "TypeError: Expect argument #2 to be String. got: Null", 1},
+		{`require "hmac";HMAC.hexdigest("SHA256", "key", 1)`, "TypeError: Expect argument #3 to be String. got: Integer", 1},
+		{`require "hmac";HMAC.secure_compare("a", 1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -108,11 +108,13 @@ func builtinHTTPClientInstanceMethods() []*BuiltinMethodObject {
 
 			},
 		}, {
-			// Sends a passed `Net::HTTP::Request` object and returns a `Net::HTTP::Response` object
+			// Sends a passed `Net::HTTP::Request` object and returns a `Net::HTTP::Response` object.
+			// An optional `Context` aborts the request with a `ContextError` when it is done.
 			Name: "exec",
 			Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-				if len(args) != 1 {
-					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+				aLen := len(args)
+				if aLen < 1 || aLen > 2 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, aLen)
 				}
 
 				typeErr := t.vm.checkArgTypes(args, sourceLine, httpRequestClass.Name)
@@ -126,8 +128,22 @@ func builtinHTTPClientInstanceMethods() []*BuiltinMethodObject {
 					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, err.Error())
 				}
 
+				if aLen == 2 {
+					ctx, ctxErr := t.vm.contextFromArg(args[1], sourceLine)
+
+					if ctxErr != nil {
+						return ctxErr
+					}
+
+					goReq = goReq.WithContext(ctx)
+				}
+
 				goResp, err := goClient.Do(goReq)
 				if err != nil {
+					if goReq.Context().Err() != nil {
+						return t.vm.initContextError(goReq.Context(), sourceLine)
+					}
+
 					return t.vm.InitErrorObject(errors.HTTPError, sourceLine, couldNotCompleteRequest, err)
 				}
 
@@ -1,6 +1,8 @@
 package vm
 
 import (
+	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/goby-lang/goby/compiler/bytecode"
@@ -12,13 +14,13 @@ type operation func(t *Thread, sourceLine int, cf *normalCallFrame, args ...inte
 
 type operationType = uint8
 
-type setType = string
-
 type instructionSet struct {
 	name         string
 	instructions []*bytecode.Instruction
 	filename     filename
 	paramTypes   *bytecode.ArgSet
+	// definedSets holds the instruction sets of method, class and block definitions, indexed by instruction
+	definedSets map[int]*instructionSet
 }
 
 var operations [bytecode.InstructionCount]operation
@@ -43,19 +45,21 @@ func init() {
 		},
 		bytecode.GetConstant: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
 			constName := args[0].(string)
-			c := t.vm.lookupConstant(cf, constName)
+			c := t.lookupConstant(cf, constName)
 
 			if c == nil {
 				t.pushErrorObject(errors.NameError, sourceLine, "uninitialized constant %s", constName)
 			}
 
-			c.isNamespace = args[1].(bool)
+			// The constant's pointer is shared, so the namespace flag is set on a new pointer.
+			// Otherwise a receiver like `Math` in `Math.sqrt(Math::PI)` would be flagged and popped as well.
+			isNamespace := args[1].(bool)
 
 			if t.Stack.top() != nil && t.Stack.top().isNamespace {
 				t.Stack.Pop()
 			}
 
-			t.Stack.Push(c)
+			t.Stack.Push(&Pointer{Target: c.Target, isNamespace: isNamespace})
 		},
 		bytecode.GetLocal: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
 			depth := args[0].(int)
@@ -101,6 +105,25 @@ func init() {
 
 			t.Stack.Push(&Pointer{Target: obj})
 		},
+		bytecode.GetGlobalVariable: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
+			variableName := args[0].(string)
+			t.Stack.Push(&Pointer{Target: t.vm.getGlobal(variableName)})
+		},
+		bytecode.SetGlobalVariable: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
+			variableName := args[0].(string)
+			p := t.Stack.Pop()
+
+			// Output is written via `write`, so the output variables only accept objects that respond to it
+			switch variableName {
+			case "$stdout", "$stderr":
+				if p.Target.findMethod("write") == nil {
+					t.pushErrorObject(errors.TypeError, sourceLine, "%s must have write method, %s given", variableName, p.Target.Class().Name)
+				}
+			}
+
+			t.vm.setGlobal(variableName, p.Target)
+			t.Stack.Push(p)
+		},
 		bytecode.SetLocal: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
 			var optioned bool
 			p := t.Stack.Pop()
@@ -149,8 +172,15 @@ func init() {
 
 		},
 		bytecode.NewRange: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
-			rangeEnd := t.Stack.Pop().Target.(*IntegerObject).value
-			rangeStart := t.Stack.Pop().Target.(*IntegerObject).value
+			rangeEnd, endErr := t.Stack.Pop().Target.(*IntegerObject).intValue(t, sourceLine)
+			rangeStart, startErr := t.Stack.Pop().Target.(*IntegerObject).intValue(t, sourceLine)
+
+			for _, err := range []*Error{startErr, endErr} {
+				if err != nil {
+					t.Stack.Push(&Pointer{Target: err})
+					panic(err.Message())
+				}
+			}
 
 			t.Stack.Push(&Pointer{Target: t.vm.initRangeObject(rangeStart, rangeEnd)})
 
@@ -309,9 +339,9 @@ func init() {
 		bytecode.DefMethod: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
 			argCount := args[0].(int)
 			methodName := t.Stack.Pop().Target.(*StringObject).value
-			is, ok := t.getMethodIS(methodName, cf.FileName())
+			is := cf.definedSet()
 
-			if !ok {
+			if is == nil {
 				t.pushErrorObject(errors.InternalError, sourceLine, "Can't get method %s's instruction set.", methodName)
 			}
 
@@ -322,7 +352,7 @@ func init() {
 		bytecode.DefSingletonMethod: func(t *Thread, sourceLine int, cf *normalCallFrame, args ...interface{}) {
 			argCount := args[0].(int)
 			methodName := t.Stack.Pop().Target.(*StringObject).value
-			is, _ := t.getMethodIS(methodName, cf.FileName())
+			is := cf.definedSet()
 			method := &MethodObject{Name: methodName, argc: argCount, instructionSet: is, BaseObj: NewBaseObject(t.vm.TopLevelClass(classes.MethodClass))}
 
 			t.vm.defineSingletonMethodOn(t.Stack.Pop().Target, method)
@@ -331,36 +361,44 @@ func init() {
 			subject := strings.Split(args[0].(string), ":")
 			subjectType, subjectName := subject[0], subject[1]
 
-			classPtr := cf.lookupConstantUnderAllScope(subjectName)
+			classPtr := t.vm.lookupOrDefineClass(func() *Pointer {
+				classPtr := cf.lookupConstantUnderAllScope(subjectName)
 
-			if classPtr == nil {
-				var class *RClass
-				if subjectType == "module" {
-					class = t.vm.initializeModule(subjectName)
-				} else {
-					class = t.vm.initializeClass(subjectName)
-				}
+				if classPtr == nil {
+					var class *RClass
+					if subjectType == "module" {
+						class = t.vm.initializeModule(subjectName)
+					} else {
+						class = t.vm.initializeClass(subjectName)
+					}
 
-				classPtr = cf.storeConstant(class.Name, class)
+					classPtr = cf.storeConstant(class.Name, class)
 
-				if len(args) >= 2 {
-					superClassName := args[1].(string)
-					superClass := t.vm.lookupConstant(cf, superClassName)
-					inheritedClass, ok := superClass.Target.(*RClass)
+					if len(args) >= 2 {
+						superClassName := args[1].(string)
+						superClass := t.lookupConstant(cf, superClassName)
+						inheritedClass, ok := superClass.Target.(*RClass)
 
-					if !ok {
-						t.pushErrorObject(errors.InternalError, sourceLine, "Constant %s is not a class. got: %s", superClassName, string(superClass.Target.Class().ReturnName()))
-					}
+						if !ok {
+							t.pushErrorObject(errors.InternalError, sourceLine, "Constant %s is not a class. got: %s", superClassName, string(superClass.Target.Class().ReturnName()))
+						}
 
-					if inheritedClass.isModule {
-						t.pushErrorObject(errors.InternalError, sourceLine, "Module inheritance is not supported: %s", inheritedClass.Name)
-					}
+						if inheritedClass.isModule {
+							t.pushErrorObject(errors.InternalError, sourceLine, "Module inheritance is not supported: %s", inheritedClass.Name)
+						}
 
-					class.inherits(inheritedClass)
+						class.inherits(inheritedClass)
+					}
 				}
-			}
 
-			is := t.getClassIS(subjectName, cf.FileName())
+				return classPtr
+			})
+
+			is := cf.definedSet()
+
+			if is == nil {
+				panic(fmt.Sprintf("Can't find class %s's instructions", subjectName))
+			}
 
 			t.Stack.Pop()
 			c := newNormalCallFrame(is, cf.FileName(), sourceLine)
@@ -400,7 +438,7 @@ func init() {
 			receiver := t.Stack.data[receiverPr].Target
 
 			// Find Block
-			blockFrame := t.retrieveBlock(cf.FileName(), blockFlag, cf.SourceLine())
+			blockFrame := t.retrieveBlock(cf, blockFlag)
 
 			if blockFrame != nil {
 				blockFrame.ep = cf
@@ -501,6 +539,8 @@ func (v *VM) InitObjectFromGoType(value interface{}) Object {
 		return v.InitIntegerObject(int(val))
 	case int32:
 		return v.InitIntegerObject(int(val))
+	case *big.Int:
+		return v.initIntegerObjectFromBigInt(val)
 	case float64:
 		return v.initFloatObject(val)
 	case []uint8:
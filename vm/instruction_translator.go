@@ -1,13 +1,16 @@
 package vm
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/goby-lang/goby/compiler/bytecode"
 )
 
 // instructionTranslator is responsible for parsing bytecodes
 type instructionTranslator struct {
 	vm         *VM
-	setTable   map[setType]map[string][]*instructionSet
+	setTable   map[*bytecode.InstructionSet]*instructionSet
 	blockTable map[string]*instructionSet
 	filename   filename
 	program    *instructionSet
@@ -17,10 +20,7 @@ type instructionTranslator struct {
 func newInstructionTranslator(file filename) *instructionTranslator {
 	it := &instructionTranslator{filename: file}
 	it.blockTable = make(map[string]*instructionSet)
-	it.setTable = map[setType]map[string][]*instructionSet{
-		bytecode.MethodDef: make(map[string][]*instructionSet),
-		bytecode.ClassDef:  make(map[string][]*instructionSet),
-	}
+	it.setTable = make(map[*bytecode.InstructionSet]*instructionSet)
 
 	return it
 }
@@ -30,14 +30,13 @@ func (it *instructionTranslator) setMetadata(is *instructionSet, set *bytecode.I
 	n := set.Name()
 
 	is.name = n
+	it.setTable[set] = is
 
 	switch t {
 	case bytecode.Program:
 		it.program = is
 	case bytecode.Block:
 		it.blockTable[n] = is
-	default:
-		it.setTable[t][n] = append(it.setTable[t][n], is)
 	}
 }
 
@@ -48,4 +47,38 @@ func (it *instructionTranslator) transferInstructionSets(sets []*bytecode.Instru
 		is.paramTypes = set.ArgTypes()
 		it.setMetadata(is, set)
 	}
+
+	for _, set := range sets {
+		it.resolveInstructionSets(it.setTable[set])
+	}
+}
+
+// resolveInstructionSets links every method, class and block definition in the given instruction set
+// to the instruction set it defines. The links are read-only once translated,
+// so the same code can be evaluated by multiple threads at the same time.
+func (it *instructionTranslator) resolveInstructionSets(is *instructionSet) {
+	is.definedSets = make(map[int]*instructionSet)
+
+	for index, i := range is.instructions {
+		switch i.Opcode {
+		case bytecode.DefMethod, bytecode.DefSingletonMethod, bytecode.DefClass:
+			is.definedSets[index] = it.setTable[i.DefinedSet()]
+		case bytecode.Send:
+			blockFlag, ok := i.Params[2].(string)
+
+			if !ok || len(blockFlag) == 0 {
+				continue
+			}
+
+			// The block's name is its index, for example "block:1"'s name is "1"
+			blockName := strings.Split(blockFlag, ":")[1]
+			block, ok := it.blockTable[blockName]
+
+			if !ok {
+				panic(fmt.Sprintf("Can't find block %s", blockName))
+			}
+
+			is.definedSets[index] = block
+		}
+	}
 }
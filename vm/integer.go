@@ -2,6 +2,7 @@ package vm
 
 import (
 	"math"
+	"math/big"
 	"strconv"
 
 	"github.com/goby-lang/goby/vm/classes"
@@ -15,13 +16,30 @@ import (
 // 2 * 2 # => 4
 // ```
 //
+// Integers are promoted to arbitrary-precision integers when a result doesn't fit in 64 bits,
+// and are demoted back when it does.
+//
+// ```ruby
+// 2 ** 70           # => 1180591620717411303424
+// 2 ** 70 / 2 ** 69 # => 2
+// ```
+//
 // - `Integer.new` is not supported.
 type IntegerObject struct {
 	*BaseObj
 	value int
-	flag  int
+	// bigValue holds the value when it doesn't fit in an int, and is nil otherwise
+	bigValue *big.Int
+	flag     int
 }
 
+// minInt is the smallest value an int can hold
+const minInt = -1 << (strconv.IntSize - 1)
+
+// maxIntegerBits is the largest number of bits `**` and `<<` can produce, so that a huge operand raises an error
+// instead of exhausting the memory
+const maxIntegerBits = 1 << 23
+
 /*
 This is enum defined for integer's flag
 */
@@ -57,22 +75,21 @@ var builtinIntegerClassMethods = []*BuiltinMethodObject{
 // Instance methods -----------------------------------------------------
 var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 	{
-		// Returns the sum of self and another Numeric.
+		// Returns the sum of self and another Numeric. The result is promoted to a big integer
+		// when it doesn't fit in 64 bits.
 		//
 		// ```Ruby
 		// 1 + 2 # => 3
+		// 9223372036854775807 + 1 # => 9223372036854775808
 		// ```
 		// @return [Numeric]
 		Name: "+",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			intOperation := func(leftValue int, rightValue int) int {
-				return leftValue + rightValue
-			}
 			floatOperation := func(leftValue float64, rightValue float64) float64 {
 				return leftValue + rightValue
 			}
 
-			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], addInt, (*big.Int).Add, floatOperation, (*Decimal).Add, sourceLine, false)
 
 		},
 	},
@@ -85,17 +102,18 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 		// @return [Numeric]
 		Name: "%",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			intOperation := func(leftValue int, rightValue int) int {
-				return leftValue % rightValue
+			intOperation := func(leftValue int, rightValue int) (int, bool) {
+				return leftValue % rightValue, true
 			}
 			floatOperation := math.Mod
 
-			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, true)
+			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, (*big.Int).Rem, floatOperation, nil, sourceLine, true)
 
 		},
 	},
 	{
-		// Returns the subtraction of another Numeric from self.
+		// Returns the subtraction of another Numeric from self. The result is promoted to a big integer
+		// when it doesn't fit in 64 bits.
 		//
 		// ```Ruby
 		// 1 - 1 # => 0
@@ -103,19 +121,17 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 		// @return [Numeric]
 		Name: "-",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			intOperation := func(leftValue int, rightValue int) int {
-				return leftValue - rightValue
-			}
 			floatOperation := func(leftValue float64, rightValue float64) float64 {
 				return leftValue - rightValue
 			}
 
-			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], subInt, (*big.Int).Sub, floatOperation, (*Decimal).Sub, sourceLine, false)
 
 		},
 	},
 	{
-		// Returns self multiplying another Numeric.
+		// Returns self multiplying another Numeric. The result is promoted to a big integer
+		// when it doesn't fit in 64 bits.
 		//
 		// ```Ruby
 		// 2 * 10 # => 20
@@ -123,32 +139,32 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 		// @return [Numeric]
 		Name: "*",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			intOperation := func(leftValue int, rightValue int) int {
-				return leftValue * rightValue
-			}
 			floatOperation := func(leftValue float64, rightValue float64) float64 {
 				return leftValue * rightValue
 			}
 
-			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], mulInt, (*big.Int).Mul, floatOperation, (*Decimal).Mul, sourceLine, false)
 
 		},
 	},
 	{
-		// Returns self squaring another Numeric.
+		// Returns self squaring another Numeric. The result is promoted to a big integer
+		// when it doesn't fit in 64 bits. A RangeError is raised if the result would have more than 2 ** 23 bits.
 		//
 		// ```Ruby
-		// 2 ** 8 # => 256
+		// 2 ** 8  # => 256
+		// 2 ** 70 # => 1180591620717411303424
 		// ```
 		// @return [Numeric]
 		Name: "**",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			intOperation := func(leftValue int, rightValue int) int {
-				return int(math.Pow(float64(leftValue), float64(rightValue)))
-			}
 			floatOperation := math.Pow
 
-			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, false)
+			if exponent, ok := args[0].(*IntegerObject); ok && receiver.(*IntegerObject).powTooBig(exponent) {
+				return t.vm.InitErrorObject(errors.RangeError, sourceLine, errors.ExponentTooBig, exponent.ToString())
+			}
+
+			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], powInt, powBigInt, floatOperation, nil, sourceLine, false)
 
 		},
 	},
@@ -161,15 +177,135 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 		// @return [Numeric]
 		Name: "/",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			intOperation := func(leftValue int, rightValue int) (int, bool) {
+				// The only overflow: the smallest int divided by -1
+				if leftValue == minInt && rightValue == -1 {
+					return 0, false
+				}
 
-			intOperation := func(leftValue int, rightValue int) int {
-				return leftValue / rightValue
+				return leftValue / rightValue, true
 			}
 			floatOperation := func(leftValue float64, rightValue float64) float64 {
 				return leftValue / rightValue
 			}
 
-			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, floatOperation, sourceLine, true)
+			return receiver.(*IntegerObject).arithmeticOperation(t, args[0], intOperation, (*big.Int).Quo, floatOperation, (*Decimal).Quo, sourceLine, true)
+
+		},
+	},
+	{
+		// Returns the bitwise AND of self and another Integer.
+		//
+		// ```Ruby
+		// 12 & 10 # => 8
+		// ```
+		// @return [Integer]
+		Name: "&",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			intOperation := func(leftValue int, rightValue int) int {
+				return leftValue & rightValue
+			}
+
+			return receiver.(*IntegerObject).bitwiseOperation(t, args[0], intOperation, (*big.Int).And, sourceLine)
+
+		},
+	},
+	{
+		// Returns the bitwise OR of self and another Integer.
+		//
+		// ```Ruby
+		// 12 | 10 # => 14
+		// ```
+		// @return [Integer]
+		Name: "|",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			intOperation := func(leftValue int, rightValue int) int {
+				return leftValue | rightValue
+			}
+
+			return receiver.(*IntegerObject).bitwiseOperation(t, args[0], intOperation, (*big.Int).Or, sourceLine)
+
+		},
+	},
+	{
+		// Returns the bitwise exclusive OR of self and another Integer.
+		//
+		// ```Ruby
+		// 12 ^ 10 # => 6
+		// ```
+		// @return [Integer]
+		Name: "^",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			intOperation := func(leftValue int, rightValue int) int {
+				return leftValue ^ rightValue
+			}
+
+			return receiver.(*IntegerObject).bitwiseOperation(t, args[0], intOperation, (*big.Int).Xor, sourceLine)
+
+		},
+	},
+	{
+		// Returns the bitwise NOT of self, which is `-self - 1`.
+		//
+		// ```Ruby
+		// ~5  # => -6
+		// ~-1 # => 0
+		// ```
+		// @return [Integer]
+		Name: "~",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			i := receiver.(*IntegerObject)
+
+			if i.bigValue != nil {
+				return t.vm.initIntegerObjectFromBigInt(new(big.Int).Not(i.bigValue))
+			}
+
+			return t.vm.InitIntegerObject(^i.value)
+
+		},
+	},
+	{
+		// Returns self shifted left by the given number of bits, or shifted right if the number is negative.
+		// The result is promoted to a big integer when it doesn't fit in 64 bits.
+		// A RangeError is raised if the result would have more than 2 ** 23 bits.
+		//
+		// ```Ruby
+		// 1 << 4   # => 16
+		// 1 << 64  # => 18446744073709551616
+		// 16 << -2 # => 4
+		// ```
+		// @return [Integer]
+		Name: "<<",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			return receiver.(*IntegerObject).shift(t, args[0], true, sourceLine)
+
+		},
+	},
+	{
+		// Returns self shifted right by the given number of bits, or shifted left if the number is negative.
+		// Negative numbers are shifted arithmetically, so the result is rounded towards negative infinity.
+		//
+		// ```Ruby
+		// 16 >> 2  # => 4
+		// -16 >> 2 # => -4
+		// -1 >> 10 # => -1
+		// ```
+		// @return [Integer]
+		Name: ">>",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			return receiver.(*IntegerObject).shift(t, args[0], false, sourceLine)
 
 		},
 	},
@@ -291,19 +427,9 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 
 			switch rightObject := rightObject.(type) {
 			case *IntegerObject:
-				leftValue := receiver.(*IntegerObject).value
-				rightValue := rightObject.value
-
-				if leftValue < rightValue {
-					return t.vm.InitIntegerObject(-1)
-				}
-				if leftValue > rightValue {
-					return t.vm.InitIntegerObject(1)
-				}
-
-				return t.vm.InitIntegerObject(0)
+				return t.vm.InitIntegerObject(receiver.(*IntegerObject).compare(rightObject))
 			case *FloatObject:
-				leftValue := float64(receiver.(*IntegerObject).value)
+				leftValue := receiver.(*IntegerObject).floatValue()
 				rightValue := rightObject.value
 
 				if leftValue < rightValue {
@@ -335,7 +461,7 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 			}
 
 			i := receiver.(*IntegerObject)
-			even := i.value%2 == 0
+			even := i.bigInt().Bit(0) == 0
 
 			if even {
 				return TRUE
@@ -377,7 +503,7 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 			}
 
 			r := receiver.(*IntegerObject)
-			newFloat := t.vm.initFloatObject(float64(r.value))
+			newFloat := t.vm.initFloatObject(r.floatValue())
 			return newFloat
 
 		},
@@ -400,21 +526,43 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
-		// Returns a `String` representation of self.
+		// Returns a `String` representation of self. An optional base from 2 to 36 can be passed,
+		// which is 10 by default.
 		//
 		// ```Ruby
-		// 100.to_s # => "100"
+		// 100.to_s     # => "100"
+		// 10.to_s(2)   # => "1010"
+		// 255.to_s(16) # => "ff"
 		// ```
+		// @param base [Integer]
 		// @return [String]
 		Name: "to_s",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			if len(args) != 0 {
-				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			i := receiver.(*IntegerObject)
+
+			if len(args) == 0 {
+				return t.vm.InitStringObject(i.ToString())
+			}
+
+			base, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 			}
 
-			int := receiver.(*IntegerObject)
+			if base.bigValue != nil || base.value < 2 || base.value > 36 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidRadix, base.Value())
+			}
+
+			if i.bigValue != nil {
+				return t.vm.InitStringObject(i.bigValue.Text(base.value))
+			}
 
-			return t.vm.InitStringObject(strconv.Itoa(int.value))
+			return t.vm.InitStringObject(strconv.FormatInt(int64(i.value), base.value))
 
 		},
 	},
@@ -431,8 +579,7 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			i := receiver.(*IntegerObject)
-			return t.vm.InitIntegerObject(i.value + 1)
+			return receiver.(*IntegerObject).arithmeticOperation(t, t.vm.InitIntegerObject(1), addInt, (*big.Int).Add, nil, nil, sourceLine, false)
 
 		},
 	},
@@ -451,7 +598,7 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 			}
 
 			i := receiver.(*IntegerObject)
-			odd := i.value%2 != 0
+			odd := i.bigInt().Bit(0) != 0
 			if odd {
 				return TRUE
 			}
@@ -473,8 +620,240 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			i := receiver.(*IntegerObject)
-			return t.vm.InitIntegerObject(i.value - 1)
+			return receiver.(*IntegerObject).arithmeticOperation(t, t.vm.InitIntegerObject(1), subInt, (*big.Int).Sub, nil, nil, sourceLine, false)
+
+		},
+	},
+	{
+		// Returns the number of bits of self's value, excluding the sign bit.
+		//
+		// ```ruby
+		// 255.bit_length    # => 8
+		// 256.bit_length    # => 9
+		// (-256).bit_length # => 8
+		// ```
+		// @return [Integer]
+		Name: "bit_length",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			n := receiver.(*IntegerObject).bigInt()
+
+			// For negative numbers, the bits of -n - 1 are counted, so that -256 takes 8 bits like 255
+			if n.Sign() < 0 {
+				n = new(big.Int).Not(n)
+			}
+
+			return t.vm.InitIntegerObject(n.BitLen())
+
+		},
+	},
+	{
+		// Returns the digits of self in the given base (10 by default), from the least significant digit.
+		// Raises `Math::DomainError` if self is negative.
+		//
+		// ```ruby
+		// 1234.digits     # => [4, 3, 2, 1]
+		// 255.digits(16)  # => [15, 15]
+		// ```
+		// @param base [Integer]
+		// @return [Array]
+		Name: "digits",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			base := big.NewInt(10)
+
+			if len(args) == 1 {
+				b, ok := args[0].(*IntegerObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+				}
+
+				if b.bigInt().Cmp(big.NewInt(2)) < 0 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidRadix, b.Value())
+				}
+
+				base = b.bigInt()
+			}
+
+			n := new(big.Int).Set(receiver.(*IntegerObject).bigInt())
+
+			if n.Sign() < 0 {
+				return t.vm.InitErrorObject(errors.DomainError, sourceLine, errors.OutOfDomain, "digits")
+			}
+
+			digits := []Object{}
+
+			for {
+				digit := new(big.Int)
+				n.QuoRem(n, base, digit)
+				digits = append(digits, t.vm.initIntegerObjectFromBigInt(digit))
+
+				if n.Sign() == 0 {
+					break
+				}
+			}
+
+			return t.vm.InitArrayObject(digits)
+
+		},
+	},
+	{
+		// Returns the greatest common divisor of self and the given Integer, which is always positive or zero.
+		//
+		// ```ruby
+		// 12.gcd(18) # => 6
+		// 3.gcd(-7)  # => 1
+		// ```
+		// @param n [Integer]
+		// @return [Integer]
+		Name: "gcd",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			n, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+			}
+
+			return t.vm.initIntegerObjectFromBigInt(gcd(receiver.(*IntegerObject).bigInt(), n.bigInt()))
+
+		},
+	},
+	{
+		// Returns the least common multiple of self and the given Integer, which is always positive or zero.
+		//
+		// ```ruby
+		// 4.lcm(6)  # => 12
+		// 3.lcm(-7) # => 21
+		// ```
+		// @param n [Integer]
+		// @return [Integer]
+		Name: "lcm",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			n, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+			}
+
+			a, b := receiver.(*IntegerObject).bigInt(), n.bigInt()
+
+			if a.Sign() == 0 || b.Sign() == 0 {
+				return t.vm.InitIntegerObject(0)
+			}
+
+			lcm := new(big.Int).Mul(a, b)
+			lcm.Abs(lcm)
+			lcm.Quo(lcm, gcd(a, b))
+
+			return t.vm.initIntegerObjectFromBigInt(lcm)
+
+		},
+	},
+	{
+		// Returns self raised to the power of the given Numeric. If a modulus is given, returns
+		// `(self ** n) % mod` without computing the (possibly huge) power first.
+		//
+		// ```ruby
+		// 2.pow(10)        # => 1024
+		// 2.pow(-1)        # => 0.5
+		// 3.pow(200, 7)    # => 2
+		// ```
+		// @param n [Numeric]
+		// @param mod [Integer]
+		// @return [Numeric]
+		Name: "pow",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			base := receiver.(*IntegerObject)
+
+			if len(args) == 1 {
+				switch exp := args[0].(type) {
+				case *IntegerObject:
+					if exp.bigInt().Sign() < 0 {
+						return t.vm.initFloatObject(math.Pow(base.floatValue(), exp.floatValue()))
+					}
+
+					return base.arithmeticOperation(t, exp, powInt, powBigInt, nil, nil, sourceLine, false)
+				case *FloatObject:
+					return t.vm.initFloatObject(math.Pow(base.floatValue(), exp.value))
+				default:
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+				}
+			}
+
+			exp, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.IntegerClass, args[0].Class().Name)
+			}
+
+			mod, ok := args[1].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.IntegerClass, args[1].Class().Name)
+			}
+
+			if exp.bigInt().Sign() < 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, exp.Value())
+			}
+
+			if mod.isZero() {
+				return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+			}
+
+			m := new(big.Int).Abs(mod.bigInt())
+			result := new(big.Int).Mod(base.bigInt(), m)
+			result.Exp(result, exp.bigInt(), m)
+
+			// The result has the same sign as the modulus, like `%` does
+			if mod.bigInt().Sign() < 0 && result.Sign() != 0 {
+				result.Add(result, mod.bigInt())
+			}
+
+			return t.vm.initIntegerObjectFromBigInt(result)
+
+		},
+	},
+	{
+		// Returns the integer square root of self, which is the largest Integer whose square is less than or
+		// equal to self. Raises `Math::DomainError` if self is negative.
+		//
+		// ```ruby
+		// 16.sqrt # => 4
+		// 24.sqrt # => 4
+		// ```
+		// @return [Integer]
+		Name: "sqrt",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			n := receiver.(*IntegerObject).bigInt()
+
+			if n.Sign() < 0 {
+				return t.vm.InitErrorObject(errors.DomainError, sourceLine, errors.OutOfDomain, "isqrt")
+			}
+
+			return t.vm.initIntegerObjectFromBigInt(new(big.Int).Sqrt(n))
 
 		},
 	},
@@ -521,8 +900,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = i
 			return newInt
 
@@ -535,8 +919,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = i8
 			return newInt
 
@@ -549,8 +938,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = i16
 			return newInt
 
@@ -563,8 +957,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = i32
 			return newInt
 
@@ -577,8 +976,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = i64
 			return newInt
 
@@ -591,8 +995,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = ui
 			return newInt
 
@@ -605,8 +1014,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = ui8
 			return newInt
 
@@ -619,8 +1033,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = ui16
 			return newInt
 
@@ -633,8 +1052,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = ui32
 			return newInt
 
@@ -647,8 +1071,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = ui64
 			return newInt
 
@@ -661,8 +1090,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = f32
 			return newInt
 
@@ -675,8 +1109,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			newInt := t.vm.InitIntegerObject(r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			newInt := t.vm.InitIntegerObject(value)
 			newInt.flag = f64
 			return newInt
 
@@ -689,8 +1128,13 @@ var builtinIntegerInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			r := receiver.(*IntegerObject)
-			return t.vm.initGoObject(&r.value)
+			value, err := receiver.(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.initGoObject(&value)
 
 		},
 	},
@@ -709,6 +1153,17 @@ func (vm *VM) InitIntegerObject(value int) *IntegerObject {
 	}
 }
 
+// initIntegerObjectFromBigInt initializes an IntegerObject, which holds the big integer only if it doesn't fit in an int
+func (vm *VM) initIntegerObjectFromBigInt(value *big.Int) *IntegerObject {
+	if value.IsInt64() && int64(int(value.Int64())) == value.Int64() {
+		return vm.InitIntegerObject(int(value.Int64()))
+	}
+
+	integer := vm.InitIntegerObject(0)
+	integer.bigValue = value
+	return integer
+}
+
 func (vm *VM) initIntegerClass() *RClass {
 	ic := vm.initializeClass(classes.IntegerClass)
 	ic.setBuiltinMethods(builtinIntegerInstanceMethods, false)
@@ -719,39 +1174,110 @@ func (vm *VM) initIntegerClass() *RClass {
 
 // Polymorphic helper functions -----------------------------------------
 
-// Value returns the object
+// Value returns the object's value, which is an `int`, or a `*big.Int` if the value doesn't fit in an int
 func (i *IntegerObject) Value() interface{} {
+	if i.bigValue != nil {
+		return i.bigValue
+	}
+
 	return i.value
 }
 
 // Numeric interface
 func (i *IntegerObject) floatValue() float64 {
+	if i.bigValue != nil {
+		f, _ := new(big.Float).SetInt(i.bigValue).Float64()
+		return f
+	}
+
 	return float64(i.value)
 }
 
+// bigInt returns the value as a big integer
+func (i *IntegerObject) bigInt() *big.Int {
+	if i.bigValue != nil {
+		return i.bigValue
+	}
+
+	return big.NewInt(int64(i.value))
+}
+
+// intValue returns the value as an int, or a RangeError if it's a big integer which doesn't fit in an int.
+// Builtin methods use it to read sizes and indexes, since `value` is 0 for big integers.
+func (i *IntegerObject) intValue(t *Thread, sourceLine int) (int, *Error) {
+	if i.bigValue != nil {
+		return 0, t.vm.InitErrorObject(errors.RangeError, sourceLine, errors.IntegerTooBig, i.bigValue.String())
+	}
+
+	return i.value, nil
+}
+
+// powTooBig reports whether self ** exponent would have more than maxIntegerBits bits
+func (i *IntegerObject) powTooBig(exponent *IntegerObject) bool {
+	// The absolute value of self is at least 2 ** bits
+	bits := i.bigInt().BitLen() - 1
+
+	if bits <= 0 || exponent.bigInt().Sign() <= 0 {
+		return false
+	}
+
+	return exponent.bigValue != nil || exponent.value > maxIntegerBits/bits
+}
+
+func (i *IntegerObject) isZero() bool {
+	return i.bigValue == nil && i.value == 0
+}
+
+// compare returns -1, 0 or 1 if self is smaller than, equal to or larger than the given Integer
+func (i *IntegerObject) compare(right *IntegerObject) int {
+	if i.bigValue == nil && right.bigValue == nil {
+		switch {
+		case i.value < right.value:
+			return -1
+		case i.value > right.value:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return i.bigInt().Cmp(right.bigInt())
+}
+
 // TODO: Remove instruction argument
 // Apply the passed arithmetic operation, while performing type conversion.
+// intOperation reports whether its result fits in an int; if it doesn't, or if either operand is
+// a big integer, bigOperation is applied instead. decimalOperation can be nil if the operation
+// doesn't support Decimal operands.
 func (i *IntegerObject) arithmeticOperation(
 	t *Thread,
 	rightObject Object,
-	intOperation func(leftValue int, rightValue int) int,
+	intOperation func(leftValue int, rightValue int) (int, bool),
+	bigOperation func(result, leftValue, rightValue *big.Int) *big.Int,
 	floatOperation func(leftValue float64, rightValue float64) float64,
+	decimalOperation func(result, leftValue, rightValue *Decimal) *Decimal,
 	sourceLine int,
 	division bool,
 ) Object {
 	switch rightObject := rightObject.(type) {
 	case *IntegerObject:
-		leftValue := i.value
-		rightValue := rightObject.value
-		if division && rightValue == 0 {
+		if division && rightObject.isZero() {
 			return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
 		}
 
-		result := intOperation(leftValue, rightValue)
+		if i.bigValue == nil && rightObject.bigValue == nil {
+			result, ok := intOperation(i.value, rightObject.value)
 
-		return t.vm.InitIntegerObject(result)
+			if ok {
+				return t.vm.InitIntegerObject(result)
+			}
+		}
+
+		result := bigOperation(new(big.Int), i.bigInt(), rightObject.bigInt())
+
+		return t.vm.initIntegerObjectFromBigInt(result)
 	case *FloatObject:
-		leftValue := float64(i.value)
+		leftValue := i.floatValue()
 		rightValue := rightObject.value
 
 		if division && rightValue == 0 {
@@ -761,21 +1287,111 @@ func (i *IntegerObject) arithmeticOperation(
 		result := floatOperation(leftValue, rightValue)
 
 		return t.vm.initFloatObject(result)
+	case *DecimalObject:
+		if decimalOperation == nil {
+			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer or Float", rightObject.Class().Name)
+		}
+
+		if division && rightObject.value.Sign() == 0 {
+			return t.vm.InitErrorObject(errors.ZeroDivisionError, sourceLine, errors.DividedByZero)
+		}
+
+		result := decimalOperation(new(Decimal), intToDecimal(i), rightObject.value)
+
+		return t.vm.initDecimalObject(result)
 	default:
 		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", rightObject.Class().Name)
 	}
 }
 
+// Apply the passed bitwise operation to self and another Integer.
+// bigOperation is applied instead if either operand is a big integer.
+func (i *IntegerObject) bitwiseOperation(
+	t *Thread,
+	rightObject Object,
+	intOperation func(leftValue int, rightValue int) int,
+	bigOperation func(result, leftValue, rightValue *big.Int) *big.Int,
+	sourceLine int,
+) Object {
+	r, ok := rightObject.(*IntegerObject)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, rightObject.Class().Name)
+	}
+
+	if i.bigValue == nil && r.bigValue == nil {
+		return t.vm.InitIntegerObject(intOperation(i.value, r.value))
+	}
+
+	return t.vm.initIntegerObjectFromBigInt(bigOperation(new(big.Int), i.bigInt(), r.bigInt()))
+}
+
+// shift shifts self by the given number of bits, to the left if left is true and to the right otherwise.
+// A negative number of bits shifts in the opposite direction.
+func (i *IntegerObject) shift(t *Thread, rightObject Object, left bool, sourceLine int) Object {
+	r, ok := rightObject.(*IntegerObject)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, rightObject.Class().Name)
+	}
+
+	if r.bigValue != nil || r.value == minInt {
+		// Shifting right by such a width leaves only the sign
+		if left == (r.bigInt().Sign() < 0) {
+			if i.bigInt().Sign() < 0 {
+				return t.vm.InitIntegerObject(-1)
+			}
+
+			return t.vm.InitIntegerObject(0)
+		}
+
+		if i.isZero() {
+			return t.vm.InitIntegerObject(0)
+		}
+
+		return t.vm.InitErrorObject(errors.RangeError, sourceLine, errors.ShiftWidthTooBig, r.ToString())
+	}
+
+	n := r.value
+
+	if !left {
+		n = -n
+	}
+
+	if n >= 0 {
+		if i.bigValue == nil && n < strconv.IntSize {
+			result := i.value << uint(n)
+
+			if result>>uint(n) == i.value {
+				return t.vm.InitIntegerObject(result)
+			}
+		}
+
+		if !i.isZero() && n > maxIntegerBits-i.bigInt().BitLen() {
+			return t.vm.InitErrorObject(errors.RangeError, sourceLine, errors.ShiftWidthTooBig, r.ToString())
+		}
+
+		return t.vm.initIntegerObjectFromBigInt(new(big.Int).Lsh(i.bigInt(), uint(n)))
+	}
+
+	if i.bigValue == nil {
+		if -n >= strconv.IntSize {
+			n = -(strconv.IntSize - 1)
+		}
+
+		return t.vm.InitIntegerObject(i.value >> uint(-n))
+	}
+
+	return t.vm.initIntegerObjectFromBigInt(new(big.Int).Rsh(i.bigValue, uint(-n)))
+}
+
 // Apply an equality test, returning true if the objects are considered equal,
 // and false otherwise.
 // See comment on numericComparison().
 func (i *IntegerObject) equalTo(rightObject Object) bool {
 	switch rightObject := rightObject.(type) {
 	case *IntegerObject:
-		leftValue := i.value
-		rightValue := rightObject.value
-
-		return leftValue == rightValue
+		return i.compare(rightObject) == 0
 	case *FloatObject:
 		leftValue := i.floatValue()
 		rightValue := rightObject.value
@@ -797,6 +1413,10 @@ func (i *IntegerObject) numericComparison(
 ) bool {
 	switch rightObject := rightObject.(type) {
 	case *IntegerObject:
+		if i.bigValue != nil || rightObject.bigValue != nil {
+			return intComparison(i.compare(rightObject), 0)
+		}
+
 		leftValue := i.value
 		rightValue := rightObject.value
 
@@ -817,6 +1437,10 @@ func (i *IntegerObject) numericComparison(
 
 // ToString returns the object's name as the string format
 func (i *IntegerObject) ToString() string {
+	if i.bigValue != nil {
+		return i.bigValue.String()
+	}
+
 	return strconv.Itoa(i.value)
 }
 
@@ -832,7 +1456,7 @@ func (i *IntegerObject) ToJSON(t *Thread) string {
 
 // equal checks if the integer values between receiver and argument are equal
 func (i *IntegerObject) equal(e *IntegerObject) bool {
-	return i.value == e.value
+	return i.compare(e) == 0
 }
 
 func (i *IntegerObject) lessThan(arg Object) bool {
@@ -845,3 +1469,74 @@ func (i *IntegerObject) lessThan(arg Object) bool {
 
 	return i.numericComparison(arg, intComparison, floatComparison)
 }
+
+// gcd returns the greatest common divisor of a and b, which is always positive or zero
+func gcd(a, b *big.Int) *big.Int {
+	return new(big.Int).GCD(nil, nil, new(big.Int).Abs(a), new(big.Int).Abs(b))
+}
+
+// addInt returns a + b, and reports whether the result doesn't overflow
+func addInt(a, b int) (int, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+// subInt returns a - b, and reports whether the result doesn't overflow
+func subInt(a, b int) (int, bool) {
+	c := a - b
+	return c, (c < a) == (b > 0)
+}
+
+// mulInt returns a * b, and reports whether the result doesn't overflow
+func mulInt(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	c := a * b
+
+	if (a == -1 && b == minInt) || (b == -1 && a == minInt) || c/b != a {
+		return c, false
+	}
+
+	return c, true
+}
+
+// powInt returns a ** b, and reports whether the result doesn't overflow.
+// A negative exponent gives the truncated result of the float power.
+func powInt(a, b int) (int, bool) {
+	if b < 0 {
+		return int(math.Pow(float64(a), float64(b))), true
+	}
+
+	result := 1
+
+	for ; b > 0; b >>= 1 {
+		var ok bool
+
+		if b&1 == 1 {
+			if result, ok = mulInt(result, a); !ok {
+				return 0, false
+			}
+		}
+
+		if b > 1 {
+			if a, ok = mulInt(a, a); !ok {
+				return 0, false
+			}
+		}
+	}
+
+	return result, true
+}
+
+// powBigInt sets z to x ** y and returns z. A negative exponent gives the truncated result of the float power.
+func powBigInt(z, x, y *big.Int) *big.Int {
+	if y.Sign() < 0 {
+		xf, _ := new(big.Float).SetInt(x).Float64()
+		yf, _ := new(big.Float).SetInt(y).Float64()
+		return z.SetInt64(int64(math.Pow(xf, yf)))
+	}
+
+	return z.Exp(x, y, nil)
+}
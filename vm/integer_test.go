@@ -378,3 +378,262 @@ func TestIntegerDupMethod(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestIntegerMathMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`12.gcd(18)`, 6},
+		{`3.gcd(-7)`, 1},
+		{`0.gcd(5)`, 5},
+		{`4.lcm(6)`, 12},
+		{`3.lcm(-7)`, 21},
+		{`0.lcm(7)`, 0},
+		{`2.pow(10)`, 1024},
+		{`2.pow(0)`, 1},
+		{`2.pow(-1)`, 0.5},
+		{`4.pow(0.5)`, 2.0},
+		{`3.pow(200, 7)`, 2},
+		{`(-3).pow(3, 5)`, 3},
+		{`3.pow(3, -5)`, -3},
+		{`255.bit_length`, 8},
+		{`256.bit_length`, 9},
+		{`0.bit_length`, 0},
+		{`(-1).bit_length`, 0},
+		{`(-256).bit_length`, 8},
+		{`16.sqrt`, 4},
+		{`24.sqrt`, 4},
+		{`0.sqrt`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBigPromotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(2 ** 70).to_s`, "1180591620717411303424"},
+		{`(9223372036854775807 + 1).to_s`, "9223372036854775808"},
+		{`(-9223372036854775807 - 1 - 1).to_s`, "-9223372036854775809"},
+		{`(4294967296 * 4294967296).to_s`, "18446744073709551616"},
+		{`((2 ** 70) - (2 ** 70) + 1).to_s`, "1"},
+		{`(2 ** 70).class.name`, "Integer"},
+		{`(2 ** 70) / (2 ** 69)`, 2},
+		{`(2 ** 70) % 7`, 2},
+		{`(2 ** 70) - ((2 ** 70) - 5)`, 5},
+		{`(9223372036854775807 + 1).pred`, 9223372036854775807},
+		{`(2 ** 70) > (2 ** 69)`, true},
+		{`(2 ** 70) < 1`, false},
+		{`(2 ** 70) == (2 ** 70)`, true},
+		{`(2 ** 70) == (2 ** 69) * 2`, true},
+		{`(2 ** 70) <=> (2 ** 71)`, -1},
+		{`(2 ** 64).even?`, true},
+		{`((2 ** 64) + 1).odd?`, true},
+		{`(2 ** 64).to_f`, 18446744073709551616.0},
+		{`((2 ** 70).to_d + "0.5".to_d).to_s`, "1180591620717411303424.5"},
+		{`((2 ** 70).to_d * 2).to_i.to_s`, "2361183241434822606848"},
+		{`(2 ** 64).sqrt`, 4294967296},
+		{`(2 ** 70).bit_length`, 71},
+		{`(2 ** 70).gcd((2 ** 65) * 3).to_s`, "36893488147419103232"},
+		{`(2 ** 70).pow(1, 1000)`, 424},
+		{`(2 ** 64).digits(2 ** 32)`, []interface{}{0, 0, 1}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBigArgumentFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2, 3][2 ** 70]`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`[1, 2, 3].first(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`[1, 2, 3] * (2 ** 64)`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`"ab" * (2 ** 64)`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`exit(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`2 ** (2 ** 70)`, "RangeError: Exponent too big. got: 1180591620717411303424", 1},
+		{`3 ** 10000000`, "RangeError: Exponent too big. got: 10000000", 1},
+		{`(1..(2 ** 70))`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`"abc"[2 ** 70]`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`"abc".slice(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`"abc".byteslice(0, 2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`(2 ** 70).to_int64`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`(2 ** 64).to_int`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`(2 ** 64).ptr`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`1.5.round(2 ** 64)`, "RangeError: Integer too big to convert into int. got: 18446744073709551616", 1},
+		{`"abc".insert(2 ** 70, "d")`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`"abc".ljust(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`[1, 2, 3].values_at(0, 2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`Array.new(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`(1..3).include?(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`(1..3).step(2 ** 70) do |i| end`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`sleep(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`Process.kill("TERM", 2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`Process.kill(2 ** 70, 1)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`Random.new.bytes(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`require "json";JSON.generate([1], { indent: 2 ** 70 })`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`StringIO.new("abc").seek(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`StringIO.new("abc").read(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`require "concurrent/executor";Concurrent::Executor.new(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`require "crypto";Crypto.bcrypt("secret", { cost: 2 ** 70 })`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerPowWithTrivialBase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1 ** (2 ** 70)`, 1},
+		{`0 ** (2 ** 70)`, 0},
+		{`(-1) ** (2 ** 70)`, 1},
+		{`(2 ** 10000).bit_length`, 10001},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`12 & 10`, 8},
+		{`12 | 10`, 14},
+		{`12 ^ 10`, 6},
+		{`~5`, -6},
+		{`~-1`, 0},
+		{`-6 & 7`, 2},
+		{`1 << 4`, 16},
+		{`16 << -2`, 4},
+		{`16 >> 2`, 4},
+		{`-16 >> 2`, -4},
+		{`-1 >> 100`, -1},
+		{`1 >> 100`, 0},
+		{`(1 << 64).to_s`, "18446744073709551616"},
+		{`(1 << 64) >> 63`, 2},
+		{`((1 << 64) | 1).to_s`, "18446744073709551617"},
+		{`(1 << 64) & 255`, 0},
+		{`((1 << 70) ^ (1 << 70))`, 0},
+		{`(~(1 << 64)).to_s`, "-18446744073709551617"},
+		{`0 << 10000000`, 0},
+		{`0 << (1 << 70)`, 0},
+		{`1 | 2 & 3`, 3},
+		{`1 + 1 << 2`, 8},
+		{`1 << 2 > 3`, true},
+		{`3 & 1 == 1`, true},
+		{`
+		a = 0
+		[1, 4].each do |n|
+		  a = a | n
+		end
+		a
+		`, 5},
+		{`10.to_s(2)`, "1010"},
+		{`255.to_s(16)`, "ff"},
+		{`-255.to_s(16)`, "-ff"},
+		{`(1 << 64).to_s(16)`, "10000000000000000"},
+		{`35.to_s(36)`, "z"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBitwiseOperatorsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1 & 1.0`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`1 | "a"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1 << 1.5`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`1 << (1 << 70)`, "RangeError: Shift width too big. got: 1180591620717411303424", 1},
+		{`1 << 10000000`, "RangeError: Shift width too big. got: 10000000", 1},
+		{`(1 << 64) >> -10000000`, "RangeError: Shift width too big. got: -10000000", 1},
+		{`10.to_s(1)`, "ArgumentError: Invalid radix. got: 1", 1},
+		{`10.to_s(37)`, "ArgumentError: Invalid radix. got: 37", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerDigitsMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []interface{}
+	}{
+		{`1234.digits`, []interface{}{4, 3, 2, 1}},
+		{`0.digits`, []interface{}{0}},
+		{`255.digits(16)`, []interface{}{15, 15}},
+		{`5.digits(2)`, []interface{}{1, 0, 1}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		verifyArrayObject(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerMathMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`12.gcd`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`12.gcd(1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`12.lcm("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`2.pow`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`2.pow("1")`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`2.pow(3, 0)`, "ZeroDivisionError: Divided by 0", 1},
+		{`2.pow(-3, 5)`, "ArgumentError: Expect argument to be positive value. got: -3", 1},
+		{`2.pow(3, 1.5)`, "TypeError: Expect argument #2 to be Integer. got: Float", 1},
+		{`(-1).digits`, "Math::DomainError: Numerical argument is out of domain - \"digits\"", 1},
+		{`10.digits(1)`, "ArgumentError: Invalid radix. got: 1", 1},
+		{`(-4).sqrt`, "Math::DomainError: Numerical argument is out of domain - \"isqrt\"", 1},
+		{`4.bit_length(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -10,8 +10,8 @@ import (
 )
 
 // InitIssueReportVM initializes a vm in test mode for issue reporting
-func InitIssueReportVM(dir string, args []string) (*VM, error) {
-	v, err := New(dir, args)
+func InitIssueReportVM(dir string, args []string, options ...Option) (*VM, error) {
+	v, err := New(dir, args, options...)
 	v.mode = parser.TestMode
 
 	return v, err
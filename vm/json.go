@@ -1,21 +1,171 @@
 package vm
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
 )
 
-type jsonObj map[string]interface{}
+// JSON parses JSON into Goby objects, and generates JSON from them.
+//
+// ```ruby
+// require "json"
+//
+// JSON.parse('{"name": "Stan", "age": 23, "height": 1.8}') # => { name: "Stan", age: 23, height: 1.8 }
+// JSON.generate({ name: "Stan", tags: ["a", "b"] })        # => '{"name":"Stan","tags":["a","b"]}'
+// puts(JSON.pretty_generate({ name: "Stan" }))
+// # {
+// #   "name": "Stan"
+// # }
+// ```
+//
+// JSON numbers are parsed as Integers unless they have a fraction or an exponent, and Floats are generated
+// with a fraction, so they keep their classes in a round trip.
+//
+// Objects other than Hash, Array, String, Integer, Float, Boolean and nil are generated with the `as_json` or
+// `to_json` method defined in their classes, or as their `to_s` otherwise:
+//
+// ```ruby
+// class User
+//   def initialize(name)
+//     @name = name
+//   end
+//
+//   def as_json
+//     { name: @name }
+//   end
+// end
+//
+// JSON.generate([User.new("Stan")]) # => '[{"name":"Stan"}]'
+// ```
+//
+// - `JSON.new` is not supported.
+
+// jsonMaxNesting is how deep arrays and hashes can be nested when generating JSON
+const jsonMaxNesting = 100
 
 // Class methods --------------------------------------------------------
 var builtinJSONClassMethods = []*BuiltinMethodObject{
 	{
+		// Yields each element of the top-level array, or each value of a stream of JSON values like JSON Lines,
+		// decoding one value at a time. The source is a String, a File or a StringIO.
+		//
+		// ```ruby
+		// File.open("events.json") do |f|
+		//   JSON.each(f) do |event|
+		//     puts(event["name"])
+		//   end
+		// end
+		// ```
+		//
+		// @param source [String, File, StringIO], options [Hash]
+		// @return [Null]
+		Name: "each",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			var r io.Reader
+
+			switch source := args[0].(type) {
+			case *StringObject:
+				r = strings.NewReader(source.value)
+			case *FileObject:
+				r = source.bufReader()
+			case *StringIOObject:
+				if err := source.checkOpen(t, sourceLine); err != nil {
+					return err
+				}
+
+				r = source
+			default:
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, "String, File or StringIO", args[0].Class().Name)
+			}
+
+			if err := checkJSONParseOptions(t, args[1:], sourceLine); err != nil {
+				return err
+			}
+
+			if blockFrame == nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+			}
+
+			yielded := false
+
+			err := eachJSONValue(r, func(v interface{}) {
+				yielded = true
+				t.builtinMethodYield(blockFrame, t.vm.objectFromJSONValue(v))
+			})
+
+			if !yielded {
+				t.callFrameStack.pop()
+			}
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Can't parse json: %s", err.Error())
+			}
+
+			return NULL
+
+		},
+	},
+	{
+		// Returns the JSON of the object. With the `indent` option, which is a String or the number of spaces,
+		// the JSON is indented like `pretty_generate`.
+		//
+		// ```ruby
+		// JSON.generate({ a: [1, 2.0, nil] }) # => '{"a":[1,2.0,null]}'
+		// ```
+		//
+		// @param object [Object], options [Hash]
+		// @return [String]
+		Name: "generate",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			indent, err := jsonIndentOf(t, args[1:], "", sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.generateJSON(args[0], indent, sourceLine)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Parses the JSON string. Numbers without a fraction or an exponent are parsed as Integers.
+		//
+		// The `symbolize_names` option is accepted for compatibility with Ruby. Since symbols are Strings in Goby,
+		// the keys can be accessed with symbols like `h[:name]` either way.
+		//
+		// ```ruby
+		// JSON.parse('[1, 1.0, "a", null]') # => [1, 1.0, "a", nil]
+		// ```
+		//
+		// @param json [String], options [Hash]
+		// @return [Object]
 		Name: "parse",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			if len(args) != 1 {
-				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
 			}
 
 			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
@@ -24,30 +174,55 @@ var builtinJSONClassMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			jsonString := args[0].Value().(string)
+			if err := checkJSONParseOptions(t, args[1:], sourceLine); err != nil {
+				return err
+			}
 
-			var obj jsonObj
-			var objs []jsonObj
+			jsonString := args[0].(*StringObject).value
+			decoder := json.NewDecoder(strings.NewReader(jsonString))
+			decoder.UseNumber()
 
-			err := json.Unmarshal([]byte(jsonString), &obj)
+			var v interface{}
+			err := decoder.Decode(&v)
+
+			if err == nil {
+				// Only whitespace can follow the value
+				if _, extraErr := decoder.Token(); extraErr != io.EOF {
+					err = json.Unmarshal([]byte(jsonString), &v)
+				}
+			}
 
 			if err != nil {
-				err = json.Unmarshal([]byte(jsonString), &objs)
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Can't parse string `%s` as json: %s", jsonString, err.Error())
+			}
 
-				if err != nil {
-					return t.vm.InitErrorObject(errors.InternalError, sourceLine, "Can't parse string `%s` as json: %s", jsonString, err.Error())
-				}
+			return t.vm.objectFromJSONValue(v)
 
-				var objects []Object
+		},
+	},
+	{
+		// Returns the JSON of the object indented with the `indent` option, which is a String or the number of
+		// spaces. It's indented with 2 spaces by default.
+		//
+		// ```ruby
+		// JSON.pretty_generate({ a: [1] }, { indent: "\t" }) # => "{\n\t\"a\": [\n\t\t1\n\t]\n}"
+		// ```
+		//
+		// @param object [Object], options [Hash]
+		// @return [String]
+		Name: "pretty_generate",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
 
-				for _, obj := range objs {
-					objects = append(objects, t.vm.convertJSONToHashObj(obj))
-				}
+			indent, err := jsonIndentOf(t, args[1:], "  ", sourceLine)
 
-				return t.vm.InitArrayObject(objects)
+			if err != nil {
+				return err
 			}
 
-			return t.vm.convertJSONToHashObj(obj)
+			return t.generateJSON(args[0], indent, sourceLine)
 
 		},
 	},
@@ -64,32 +239,69 @@ var builtinJSONClassMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			jsonString := args[0].Value().(string)
+			return toBooleanObject(json.Valid([]byte(args[0].(*StringObject).value)))
 
-			var obj jsonObj
-			var objs []jsonObj
+		},
+	},
+}
 
-			err := json.Unmarshal([]byte(jsonString), &obj)
+// Instance methods -----------------------------------------------------
+var builtinJSONInstanceMethods = []*BuiltinMethodObject{}
 
-			if err != nil {
-				err = json.Unmarshal([]byte(jsonString), &objs)
+// builtinJSONObjectMethods are added to Object by `require "json"`
+var builtinJSONObjectMethods = []*BuiltinMethodObject{
+	{
+		// Returns the object that represents the receiver in JSON, which is the receiver itself for Hash, Array,
+		// String, Integer, Float, Boolean and nil, and the result of `to_s` for other objects.
+		// Classes can override it to be generated as other objects.
+		//
+		// ```ruby
+		// (1..3).as_json # => "(1..3)"
+		// ```
+		//
+		// @return [Object]
+		Name: "as_json",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
 
-				if err != nil {
-					return FALSE
-				}
+			switch receiver.(type) {
+			case *HashObject, *ArrayObject, *StringObject, *IntegerObject, *FloatObject, *BooleanObject, *NullObject:
+				return receiver
+			}
+
+			return t.vm.InitStringObject(receiver.ToString())
 
-				return TRUE
+		},
+	},
+	{
+		// Returns the JSON of the receiver, just like `JSON.generate`.
+		//
+		// ```ruby
+		// [1, "a", nil].to_json # => '[1,"a",null]'
+		// ```
+		//
+		// @param options [Hash]
+		// @return [String]
+		Name: "to_json",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			indent, err := jsonIndentOf(t, args, "", sourceLine)
+
+			if err != nil {
+				return err
 			}
 
-			return TRUE
+			return t.generateJSON(receiver, indent, sourceLine)
 
 		},
 	},
 }
 
-// Instance methods -----------------------------------------------------
-var builtinJSONInstanceMethods = []*BuiltinMethodObject{}
-
 // Internal functions ===================================================
 
 // Functions for initialization -----------------------------------------
@@ -98,53 +310,305 @@ func initJSONClass(vm *VM) {
 	class := vm.initializeClass("JSON")
 	class.setBuiltinMethods(builtinJSONClassMethods, true)
 	class.setBuiltinMethods(builtinJSONInstanceMethods, false)
+	class.setClassConstant(vm.initializeClass("GeneratorError"))
+	class.setClassConstant(vm.initializeClass("NestingError"))
 	vm.objectClass.setClassConstant(class)
+	vm.objectClass.setBuiltinMethods(builtinJSONObjectMethods, false)
 }
 
 // Polymorphic helper functions -----------------------------------------
 
-func (v *VM) convertJSONToHashObj(j jsonObj) Object {
-	objectMap := map[string]Object{}
+// objectFromJSONValue converts the value decoded with json.Number for numbers
+func (vm *VM) objectFromJSONValue(value interface{}) Object {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		pairs := map[string]Object{}
+
+		for key, elem := range v {
+			pairs[key] = vm.objectFromJSONValue(elem)
+		}
+
+		return vm.InitHashObject(pairs)
+	case []interface{}:
+		elems := []Object{}
 
-	for key, jsonValue := range j {
-		switch jsonValue := jsonValue.(type) {
-		// Array of json objects
-		case []map[string]interface{}:
-			objs := []Object{}
+		for _, elem := range v {
+			elems = append(elems, vm.objectFromJSONValue(elem))
+		}
+
+		return vm.InitArrayObject(elems)
+	case json.Number:
+		s := v.String()
 
-			for _, value := range jsonValue {
-				objs = append(objs, v.convertJSONToHashObj(value))
+		if !strings.ContainsAny(s, ".eE") {
+			if i, ok := new(big.Int).SetString(s, 10); ok {
+				return vm.initIntegerObjectFromBigInt(i)
 			}
+		}
+
+		f, _ := v.Float64()
+		return vm.initFloatObject(f)
+	default:
+		return vm.InitObjectFromGoType(value)
+	}
+}
 
-			objectMap[key] = v.InitArrayObject(objs)
-		case []interface{}:
-			objs := []Object{}
+// Other helper functions -----------------------------------------------
 
-			for _, elem := range jsonValue {
-				switch e := elem.(type) {
-				case map[string]interface{}:
-					objs = append(objs, v.convertJSONToHashObj(e))
-				default:
-					objs = append(objs, v.InitObjectFromGoType(e))
+// generateJSON returns the JSON of the object as a String, indented if the indent isn't empty
+func (t *Thread) generateJSON(obj Object, indent string, sourceLine int) Object {
+	v, err := t.jsonValueOf(obj, 0, sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+
+	if err := encoder.Encode(v); err != nil {
+		return t.vm.InitErrorObject(errors.JSONGeneratorError, sourceLine, err.Error())
+	}
+
+	return t.vm.InitStringObject(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// jsonValueOf converts the object to a value that can be encoded. Numbers are converted to json.Number,
+// so that Integers and Floats are generated as they are.
+func (t *Thread) jsonValueOf(obj Object, depth int, sourceLine int) (interface{}, *Error) {
+	if depth > jsonMaxNesting {
+		return nil, t.vm.InitErrorObject(errors.JSONNestingError, sourceLine, "nesting of %d is too deep", depth)
+	}
+
+	switch obj := obj.(type) {
+	case *NullObject:
+		return nil, nil
+	case *BooleanObject:
+		return obj.value, nil
+	case *StringObject:
+		return obj.value, nil
+	case *IntegerObject:
+		return json.Number(obj.ToString()), nil
+	case *FloatObject:
+		if math.IsInf(obj.value, 0) || math.IsNaN(obj.value) {
+			return nil, t.vm.InitErrorObject(errors.JSONGeneratorError, sourceLine, "%s not allowed in JSON", obj.ToString())
+		}
+
+		s := strconv.FormatFloat(obj.value, 'g', -1, 64)
+
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+
+		return json.Number(s), nil
+	case *HashObject:
+		return t.jsonValueOfPairs(obj.Pairs, depth, sourceLine)
+	case *ConcurrentHashObject:
+		pairs := map[string]Object{}
+
+		obj.internalMap.Range(func(key, value interface{}) bool {
+			pairs[key.(string)] = value.(Object)
+			return true
+		})
+
+		return t.jsonValueOfPairs(pairs, depth, sourceLine)
+	case *ArrayObject:
+		return t.jsonValueOfElements(obj.Elements, depth, sourceLine)
+	case *ConcurrentArrayObject:
+		return t.jsonValueOfElements(obj.InternalArray.Elements, depth, sourceLine)
+	}
+
+	// Methods defined in Goby take precedence over the built-in ones
+	if _, ok := obj.findMethod("as_json").(*MethodObject); ok {
+		return t.jsonValueOf(t.callMethod(obj, "as_json", sourceLine), depth+1, sourceLine)
+	}
+
+	if _, ok := obj.findMethod("to_json").(*MethodObject); ok {
+		s, ok := t.callMethod(obj, "to_json", sourceLine).(*StringObject)
+
+		if !ok || !json.Valid([]byte(s.value)) {
+			return nil, t.vm.InitErrorObject(errors.JSONGeneratorError, sourceLine, "%s#to_json returned invalid JSON", obj.Class().Name)
+		}
+
+		return json.RawMessage(s.value), nil
+	}
+
+	if s := obj.ToJSON(t); json.Valid([]byte(s)) {
+		return json.RawMessage(s), nil
+	}
+
+	return obj.ToString(), nil
+}
+
+func (t *Thread) jsonValueOfPairs(pairs map[string]Object, depth int, sourceLine int) (interface{}, *Error) {
+	m := map[string]interface{}{}
+
+	for key, value := range pairs {
+		v, err := t.jsonValueOf(value, depth+1, sourceLine)
+
+		if err != nil {
+			return nil, err
+		}
+
+		m[key] = v
+	}
+
+	return m, nil
+}
+
+func (t *Thread) jsonValueOfElements(elems []Object, depth int, sourceLine int) (interface{}, *Error) {
+	values := []interface{}{}
+
+	for _, elem := range elems {
+		v, err := t.jsonValueOf(elem, depth+1, sourceLine)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// eachJSONValue decodes the elements of the top-level array, or the values of a stream of JSON values one by one.
+// The elements of an array are decoded from the stream as they're read, so a large array isn't loaded at once.
+func eachJSONValue(r io.Reader, fn func(interface{})) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	for {
+		if !decoder.More() {
+			// The decoder doesn't report an unexpected closing delimiter until a token is read
+			if _, err := decoder.Token(); err != io.EOF {
+				return err
+			}
+
+			return nil
+		}
+
+		if nextJSONValueIsArray(decoder) {
+			// Reads the opening bracket
+			if _, err := decoder.Token(); err != nil {
+				return err
+			}
+
+			for decoder.More() {
+				var v interface{}
+
+				if err := decoder.Decode(&v); err != nil {
+					return err
 				}
+
+				fn(v)
 			}
 
-			objectMap[key] = v.InitArrayObject(objs)
-			// Single json object
-		case map[string]interface{}:
-			objectMap[key] = v.convertJSONToHashObj(jsonValue)
-		case float64:
-			// TODO: Find a better way to distinguish between Float & Integer because default GO JSON package
-			// TODO: support only for parsing float out regardless of integer or float type data of JSON value
-			if jsonValue == float64(int(jsonValue)) {
-				objectMap[key] = v.InitIntegerObject(int(jsonValue))
-			} else {
-				objectMap[key] = v.initFloatObject(jsonValue)
+			// Reads the closing bracket
+			if _, err := decoder.Token(); err != nil {
+				return err
 			}
+
+			continue
+		}
+
+		var v interface{}
+
+		if err := decoder.Decode(&v); err != nil {
+			return err
+		}
+
+		fn(v)
+	}
+}
+
+// nextJSONValueIsArray reports whether the next value is an array. It's called after the decoder's More,
+// which has buffered the first byte of the value.
+func nextJSONValueIsArray(decoder *json.Decoder) bool {
+	buffered := decoder.Buffered()
+	b := make([]byte, 1)
+
+	for {
+		if _, err := buffered.Read(b); err != nil {
+			return false
+		}
+
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+
+		return b[0] == '['
+	}
+}
+
+// jsonIndentOf returns the `indent` option, which is a String or the number of spaces
+func jsonIndentOf(t *Thread, args []Object, defaultIndent string, sourceLine int) (string, *Error) {
+	if len(args) == 0 {
+		return defaultIndent, nil
+	}
+
+	options, ok := args[0].(*HashObject)
+
+	if !ok {
+		return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+	}
+
+	indent := defaultIndent
+
+	for _, key := range options.sortedKeys() {
+		if key != "indent" {
+			return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+		}
+
+		switch v := options.Pairs[key].(type) {
+		case *StringObject:
+			indent = v.value
+		case *IntegerObject:
+			value, err := v.intValue(t, sourceLine)
+
+			if err != nil {
+				return "", err
+			}
+
+			if value < 0 {
+				return "", t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, value)
+			}
+
+			indent = strings.Repeat(" ", value)
 		default:
-			objectMap[key] = v.InitObjectFromGoType(jsonValue)
+			return "", t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "String or Integer", v.Class().Name)
+		}
+	}
+
+	return indent, nil
+}
+
+// checkJSONParseOptions checks the options of parsing, which are accepted for compatibility with Ruby
+func checkJSONParseOptions(t *Thread, args []Object, sourceLine int) *Error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	options, ok := args[0].(*HashObject)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+	}
+
+	for _, key := range options.sortedKeys() {
+		if key != "symbolize_names" {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
 		}
 	}
 
-	return v.InitHashObject(objectMap)
+	return nil
+}
+
+// jsonString returns the string quoted as a JSON string
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
 }
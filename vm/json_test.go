@@ -1,6 +1,11 @@
 package vm
 
-import "testing"
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
 
 func TestJSONValidateMethod(t *testing.T) {
 	tests := []struct {
@@ -97,10 +102,13 @@ func TestJSONObjectParsing(t *testing.T) {
 
 func TestJSONParseFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`require "json";JSON.parse`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
-		{`require "json";JSON.parse('{"Name": "Stan"}', '{"Name": "hachi8833"}')`, "ArgumentError: Expect 1 argument(s). got: 2", 1},
+		{`require "json";JSON.parse`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`require "json";JSON.parse('{"Name": "Stan"}', '{"Name": "hachi8833"}')`, "TypeError: Expect argument to be Hash. got: String", 1},
+		{`require "json";JSON.parse('{}', {}, {})`, "ArgumentError: Expect 1 to 2 argument(s). got: 3", 1},
+		{`require "json";JSON.parse('{}', { max_nesting: 10 })`, "ArgumentError: Unknown option - max_nesting", 1},
 		{`require "json";JSON.parse(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`require "json";JSON.parse('invalid')`, "InternalError: Can't parse string `invalid` as json: invalid character 'i' looking for beginning of value", 1},
+		{`require "json";JSON.parse('{} []')`, "InternalError: Can't parse string `{} []` as json: invalid character '[' after top-level value", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -160,3 +168,281 @@ func TestJSONObjectArrayParsing(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestJSONParseValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "json"
+		JSON.parse('[1, 1.0, 2.5e3, -3, "a", true, null]')
+		`, []interface{}{1, 1.0, 2500.0, -3, "a", true, nil}},
+		{`
+		require "json"
+		JSON.parse('1.0').class.name
+		`, "Float"},
+		{`
+		require "json"
+		JSON.parse('123456789012345678901234567890').to_s
+		`, "123456789012345678901234567890"},
+		{`
+		require "json"
+		JSON.parse(' "foo" ')
+		`, "foo"},
+		{`
+		require "json"
+		h = JSON.parse('{"name": "Stan"}', { symbolize_names: true })
+		h[:name]
+		`, "Stan"},
+		{`
+		require "json"
+		JSON.validate('[1, 2]')
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestJSONGenerate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "json"
+		JSON.generate({ b: [nil, 1, true, 2.0, 1.5], a: "<x>" })
+		`, `{"a":"<x>","b":[null,1,true,2.0,1.5]}`},
+		{`
+		require "json"
+		JSON.generate("foo")
+		`, `"foo"`},
+		{`
+		require "json"
+		JSON.generate(1..3)
+		`, `"(1..3)"`},
+		{`
+		require "json"
+		JSON.generate([1, { a: [] }], { indent: 2 })
+		`, "[\n  1,\n  {\n    \"a\": []\n  }\n]"},
+		{`
+		require "json"
+		JSON.pretty_generate({ a: [1] })
+		`, "{\n  \"a\": [\n    1\n  ]\n}"},
+		{`
+		require "json"
+		JSON.pretty_generate({ a: 1 }, { indent: "\t" })
+		`, "{\n\t\"a\": 1\n}"},
+		{`
+		require "json"
+		JSON.parse(JSON.generate({ i: 1, f: 1.0 }))["f"].class.name
+		`, "Float"},
+		{`
+		require "json"
+		[1, "a", nil].to_json
+		`, `[1,"a",null]`},
+		{`
+		require "json"
+		"a\"b".to_json
+		`, `"a\"b"`},
+		{`
+		require "json"
+		[1].to_json({ indent: " " })
+		`, "[\n 1\n]"},
+		{`
+		require "json"
+		[1, "a"].as_json
+		`, []interface{}{1, "a"}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestJSONGenerateCustomClasses(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "json"
+
+		class User
+		  def initialize(name)
+		    @name = name
+		  end
+
+		  def as_json
+		    { name: @name }
+		  end
+		end
+
+		JSON.generate([User.new("Stan")])
+		`, `[{"name":"Stan"}]`},
+		{`
+		require "json"
+
+		class Point
+		  def to_json
+		    "[1,2]"
+		  end
+		end
+
+		JSON.generate({ point: Point.new })
+		`, `{"point":[1,2]}`},
+		{`
+		require "json"
+
+		class Point
+		  def as_json
+		    [1, 2]
+		  end
+		end
+
+		Point.new.to_json
+		`, `[1,2]`},
+		{`
+		require "json"
+
+		class Foo
+		end
+
+		JSON.parse(JSON.generate([Foo.new]))[0].class.name
+		`, "String"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestJSONEach(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "json"
+		ids = []
+		JSON.each('[{"id": 1}, {"id": 2}, {"id": 3}]') do |item|
+		  ids.push(item["id"])
+		end
+		ids
+		`, []interface{}{1, 2, 3}},
+		{`
+		require "json"
+		values = []
+		JSON.each('{"a": 1}' + "\n" + '2.5' + "\n" + '"x"') do |v|
+		  values.push(v)
+		end
+		values.length
+		`, 3},
+		{`
+		require "json"
+		JSON.each('[]') do |v|
+		  raise("unreachable")
+		end
+		`, nil},
+		{`
+		require "json"
+		File.open("/tmp/goby/items.json", "w") do |f|
+		  f.write("[1, 2.0, [3]]")
+		end
+
+		items = []
+		File.open("/tmp/goby/items.json", "r") do |f|
+		  JSON.each(f) do |item|
+		    items.push(item)
+		  end
+		end
+		items
+		`, []interface{}{1, 2.0, []interface{}{3}}},
+	}
+
+	setup()
+	defer teardown()
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestJSONGenerateFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "json";JSON.new`, "NoMethodError: Undefined Method 'new' for JSON", 1},
+		{`require "json";JSON.generate`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`require "json";JSON.generate(1, 2)`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`require "json";JSON.generate(1, { space: " " })`, "ArgumentError: Unknown option - space", 1},
+		{`require "json";JSON.generate(1, { indent: nil })`, "TypeError: Expect argument to be String or Integer. got: Null", 1},
+		{`require "json";JSON.generate(1, { indent: -1 })`, "ArgumentError: Expect argument to be positive value. got: -1", 1},
+		{`require "json";JSON.generate(Float::INFINITY)`, "JSON::GeneratorError: Infinity not allowed in JSON", 1},
+		{`require "json";a = [];b = [a];a.push(b);JSON.generate(a)`, "JSON::NestingError: nesting of 101 is too deep", 1},
+		{`
+		require "json"
+
+		class Foo
+		  def to_json
+		    "{"
+		  end
+		end
+
+		JSON.generate([Foo.new])
+		`, "JSON::GeneratorError: Foo#to_json returned invalid JSON", 1},
+		{`require "json";[].to_json(1, 2)`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
+		{`require "json";JSON.each(1) do |v| end`, "TypeError: Expect argument #1 to be String, File or StringIO. got: Integer", 1},
+		{`require "json";JSON.each("[1]")`, "InternalError: Can't yield without a block", 1},
+		{`require "json";JSON.each("[1, }") do |v| end`, "InternalError: Can't parse json: invalid character ',' looking for beginning of value", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestJSONEachStreamsArrayElements(t *testing.T) {
+	broken := errors.New("broken stream")
+	r := io.MultiReader(strings.NewReader("[1, 2, "), &errorReader{err: broken})
+
+	var values []interface{}
+	err := eachJSONValue(r, func(v interface{}) {
+		values = append(values, v)
+	})
+
+	if err != broken {
+		t.Fatalf("Expect error %v. got: %v", broken, err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expect 2 values to be yielded before the stream broke. got: %d", len(values))
+	}
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,473 @@
+package vm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// LoggerObject writes leveled log entries with structured key/value fields to `$stdout`, a File,
+// or any object that has a `write` method.
+//
+// ```ruby
+// require "logger"
+//
+// logger = Logger.new(STDERR, { level: "debug", fields: { app: "blog" } })
+// logger.info("Server started", { port: 8080 })
+// # time=2026-10-18T10:00:00+09:00 level=INFO msg="Server started" app=blog port=8080
+//
+// json = Logger.new({ format: "json" })
+// json.warn("Disk is almost full", { usage: 0.95 })
+// # {"time":"2026-10-18T10:00:00+09:00","level":"WARN","msg":"Disk is almost full","usage":0.95}
+// ```
+//
+// Options of `Logger.new`:
+//
+// - `level`: the minimum level to be written, which is "debug", "info" (default), "warn", "error" or "fatal"
+// - `format`: "text" (default), or "json" for writing an object per line
+// - `fields`: the fields added to every entry
+// - `time`: whether to add the time of entries, true by default
+//
+// A `Net::SimpleServer` whose `logger` is set writes its access logs to the logger.
+type LoggerObject struct {
+	*BaseObj
+	output Object
+	level  int
+	format string
+	fields map[string]Object
+	time   bool
+	// mutex is shared by the loggers created by `with`, so their entries don't interleave
+	mutex *sync.Mutex
+}
+
+const (
+	logDebug = iota
+	logInfo
+	logWarn
+	logError
+	logFatal
+)
+
+var logLevels = []string{"debug", "info", "warn", "error", "fatal"}
+
+// Class methods --------------------------------------------------------
+var builtinLoggerClassMethods = []*BuiltinMethodObject{
+	{
+		// Creates a logger that writes to the output, or `$stdout` by default, with the options.
+		//
+		// ```ruby
+		// Logger.new
+		// Logger.new(File.new("app.log", "a"), { level: "warn" })
+		// Logger.new({ format: "json", time: false })
+		// ```
+		//
+		// @param output [Object], options [Hash]
+		// @return [Logger]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 2, len(args))
+			}
+
+			l := &LoggerObject{
+				BaseObj: NewBaseObject(receiver.(*RClass)),
+				output:  t.vm.getGlobal("$stdout"),
+				level:   logInfo,
+				format:  "text",
+				fields:  map[string]Object{},
+				time:    true,
+				mutex:   &sync.Mutex{},
+			}
+
+			if len(args) > 0 {
+				if _, ok := args[0].(*HashObject); !ok {
+					l.output = args[0]
+					args = args[1:]
+				}
+			}
+
+			if len(args) == 0 {
+				return l
+			}
+
+			options, ok := args[0].(*HashObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+			}
+
+			for _, key := range options.sortedKeys() {
+				value := options.Pairs[key]
+
+				switch key {
+				case "level":
+					level, err := logLevelOf(t, value, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					l.level = level
+				case "format":
+					s, ok := value.(*StringObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, value.Class().Name)
+					}
+
+					if s.value != "text" && s.value != "json" {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidLogFormat, s.value)
+					}
+
+					l.format = s.value
+				case "fields":
+					fields, ok := value.(*HashObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, value.Class().Name)
+					}
+
+					l.fields = fields.copy().(*HashObject).Pairs
+				case "time":
+					b, ok := value.(*BooleanObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.BooleanClass, value.Class().Name)
+					}
+
+					l.time = b.value
+				default:
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+				}
+			}
+
+			return l
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinLoggerInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Writes the message with the fields at the "debug" level.
+		//
+		// ```ruby
+		// logger.debug("Cache missed", { key: "user:1" })
+		// ```
+		//
+		// @param message [String], fields [Hash]
+		// @return [Boolean]
+		Name: "debug",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*LoggerObject).logWithArgs(t, logDebug, args, sourceLine)
+
+		},
+	},
+	{
+		// Writes the message with the fields at the "error" level.
+		//
+		// @param message [String], fields [Hash]
+		// @return [Boolean]
+		Name: "error",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*LoggerObject).logWithArgs(t, logError, args, sourceLine)
+
+		},
+	},
+	{
+		// Writes the message with the fields at the "fatal" level. It doesn't exit the program.
+		//
+		// @param message [String], fields [Hash]
+		// @return [Boolean]
+		Name: "fatal",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*LoggerObject).logWithArgs(t, logFatal, args, sourceLine)
+
+		},
+	},
+	{
+		// Writes the message with the fields at the "info" level.
+		//
+		// ```ruby
+		// logger.info("User signed in", { id: 1 })
+		// ```
+		//
+		// @param message [String], fields [Hash]
+		// @return [Boolean]
+		Name: "info",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*LoggerObject).logWithArgs(t, logInfo, args, sourceLine)
+
+		},
+	},
+	{
+		// Returns the minimum level to be written.
+		//
+		// ```ruby
+		// Logger.new.level # => "info"
+		// ```
+		//
+		// @return [String]
+		Name: "level",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(logLevels[receiver.(*LoggerObject).level])
+
+		},
+	},
+	{
+		// Sets the minimum level to be written.
+		//
+		// ```ruby
+		// logger.level = "debug"
+		// ```
+		//
+		// @param level [String]
+		// @return [String]
+		Name: "level=",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			level, err := logLevelOf(t, args[0], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			receiver.(*LoggerObject).level = level
+			return args[0]
+
+		},
+	},
+	{
+		// Writes the message with the fields at the "warn" level.
+		//
+		// @param message [String], fields [Hash]
+		// @return [Boolean]
+		Name: "warn",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*LoggerObject).logWithArgs(t, logWarn, args, sourceLine)
+
+		},
+	},
+	{
+		// Returns a new logger with the fields added to the receiver's. It writes to the same output at the same level.
+		//
+		// ```ruby
+		// request_logger = logger.with({ request_id: "abc" })
+		// request_logger.info("Done") # => time=... level=INFO msg=Done request_id=abc
+		// ```
+		//
+		// @param fields [Hash]
+		// @return [Logger]
+		Name: "with",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			fields, ok := args[0].(*HashObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+			}
+
+			l := receiver.(*LoggerObject)
+			child := *l
+			child.BaseObj = NewBaseObject(l.class)
+			child.fields = mergeLogFields(l.fields, fields.Pairs)
+
+			return &child
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initLoggerClass(vm *VM) {
+	c := vm.initializeClass("Logger")
+	c.setBuiltinMethods(builtinLoggerClassMethods, true)
+	c.setBuiltinMethods(builtinLoggerInstanceMethods, false)
+	vm.objectClass.setClassConstant(c)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the logger's level and format
+func (l *LoggerObject) ToString() string {
+	return fmt.Sprintf("#<Logger level=%s format=%s>", logLevels[l.level], l.format)
+}
+
+// Inspect delegates to ToString
+func (l *LoggerObject) Inspect() string {
+	return l.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (l *LoggerObject) ToJSON(t *Thread) string {
+	return strconv.Quote(l.ToString())
+}
+
+// Value returns the logger's output
+func (l *LoggerObject) Value() interface{} {
+	return l.output
+}
+
+// Other helper functions -----------------------------------------------
+
+// logWithArgs logs the message and the fields given as the arguments of the level's method
+func (l *LoggerObject) logWithArgs(t *Thread, level int, args []Object, sourceLine int) Object {
+	if len(args) < 1 || len(args) > 2 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+	}
+
+	fields := map[string]Object{}
+
+	if len(args) == 2 {
+		h, ok := args[1].(*HashObject)
+
+		if !ok {
+			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.HashClass, args[1].Class().Name)
+		}
+
+		fields = h.Pairs
+	}
+
+	if err := l.log(t, level, args[0].ToString(), fields, sourceLine); err != nil {
+		return err
+	}
+
+	return TRUE
+}
+
+// log writes an entry if the level is enabled
+func (l *LoggerObject) log(t *Thread, level int, message string, fields map[string]Object, sourceLine int) *Error {
+	if level < l.level {
+		return nil
+	}
+
+	entry := l.formatEntry(t, level, message, mergeLogFields(l.fields, fields))
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := t.writeTo(l.output, entry, sourceLine); err != nil {
+		return err
+	}
+
+	// Entries written to a file are flushed at once, so that they're not lost if the program crashes
+	if f, ok := l.output.(*FileObject); ok {
+		if err := f.flush(); err != nil {
+			return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// formatEntry formats an entry as a line of `key=value` pairs, or a JSON object
+func (l *LoggerObject) formatEntry(t *Thread, level int, message string, fields map[string]Object) string {
+	keys := []string{}
+	values := []string{}
+
+	if l.time {
+		keys = append(keys, "time")
+		values = append(values, time.Now().Format(time.RFC3339))
+	}
+
+	keys = append(keys, "level", "msg")
+	values = append(values, strings.ToUpper(logLevels[level]), message)
+
+	var b strings.Builder
+
+	if l.format == "json" {
+		b.WriteString("{")
+
+		for i, key := range keys {
+			b.WriteString(jsonString(key) + ":" + jsonString(values[i]) + ",")
+		}
+
+		for _, key := range sortedLogFields(fields) {
+			b.WriteString(jsonString(key) + ":" + fields[key].ToJSON(t) + ",")
+		}
+
+		return strings.TrimSuffix(b.String(), ",") + "}\n"
+	}
+
+	for i, key := range keys {
+		b.WriteString(key + "=" + logfmtValue(values[i]) + " ")
+	}
+
+	for _, key := range sortedLogFields(fields) {
+		b.WriteString(key + "=" + logfmtValue(fields[key].ToString()) + " ")
+	}
+
+	return strings.TrimSuffix(b.String(), " ") + "\n"
+}
+
+// mergeLogFields returns the fields with the extra fields added, which override the fields of the same keys
+func mergeLogFields(fields, extra map[string]Object) map[string]Object {
+	merged := map[string]Object{}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	for k, v := range extra {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+func sortedLogFields(fields map[string]Object) []string {
+	keys := []string{}
+
+	for k := range fields {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// logfmtValue quotes the value if it's empty, or contains spaces, quotes or "="
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// logLevelOf returns the level of the given name like "info"
+func logLevelOf(t *Thread, obj Object, sourceLine int) (int, *Error) {
+	s, ok := obj.(*StringObject)
+
+	if !ok {
+		return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, obj.Class().Name)
+	}
+
+	for i, name := range logLevels {
+		if strings.ToLower(s.value) == name {
+			return i, nil
+		}
+	}
+
+	return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidLogLevel, s.value)
+}
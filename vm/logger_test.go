@@ -0,0 +1,201 @@
+package vm
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/goby-lang/goby/compiler/parser"
+)
+
+func TestLoggerMethods(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "logger"
+		Logger.new.level
+		`, "info"},
+		{`
+		require "logger"
+		l = Logger.new(STDOUT, { level: "WARN" })
+		l.level
+		`, "warn"},
+		{`
+		require "logger"
+		l = Logger.new
+		l.level = "error"
+		l.level
+		`, "error"},
+		{`
+		require "logger"
+		l = Logger.new({ level: "error" })
+		l.with({ id: 1 }).level
+		`, "error"},
+		{`
+		require "logger"
+		Logger.new({ level: "error" }).to_s
+		`, "#<Logger level=error format=text>"},
+		{`
+		require "logger"
+		Logger.new({ level: "error" }).info("not written")
+		`, true},
+		{`
+		require "logger"
+		l = Logger.new(File.new("/tmp/goby/logger.log", "w"), { time: false })
+		l.info("started")
+		l.error("failed")
+		File.new("/tmp/goby/logger.log").read
+		`, "level=INFO msg=started\nlevel=ERROR msg=failed\n"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestLoggerMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "logger";Logger.new(STDOUT, {}, 1)`, "ArgumentError: Expect 2 or less argument(s). got: 3", 1},
+		{`require "logger";Logger.new(STDOUT, 1)`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`require "logger";Logger.new({ level: "verbose" })`, "ArgumentError: Invalid log level - verbose", 1},
+		{`require "logger";Logger.new({ level: 1 })`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "logger";Logger.new({ format: "xml" })`, "ArgumentError: Invalid log format - xml", 1},
+		{`require "logger";Logger.new({ fields: 1 })`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`require "logger";Logger.new({ time: "no" })`, "TypeError: Expect argument to be Boolean. got: String", 1},
+		{`require "logger";Logger.new({ color: true })`, "ArgumentError: Unknown option - color", 1},
+		{`require "logger";Logger.new.info`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`require "logger";Logger.new.info("foo", 1)`, "TypeError: Expect argument #2 to be Hash. got: Integer", 1},
+		{`require "logger";Logger.new.with(1)`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`require "logger";Logger.new.level = "trace"`, "ArgumentError: Invalid log level - trace", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestLoggerOutput(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput string
+	}{
+		{`
+		l = Logger.new({ time: false })
+		l.debug("foo")
+		l.info("Server started", { port: 8080, host: "localhost" })
+		l.warn("")
+		l.error("a=b", { note: "say \"hi\"" })
+		l.fatal(1)
+		`, "level=INFO msg=\"Server started\" host=localhost port=8080\nlevel=WARN msg=\"\"\nlevel=ERROR msg=\"a=b\" note=\"say \\\"hi\\\"\"\nlevel=FATAL msg=1\n"},
+		{`
+		l = Logger.new($stdout, { level: "debug", format: "json", time: false, fields: { app: "blog" } })
+		l.debug("foo", { ids: [1, 2], ok: true, user: nil })
+		l.with({ app: "api", rate: 0.5 }).info("bar")
+		`, "{\"level\":\"DEBUG\",\"msg\":\"foo\",\"app\":\"blog\",\"ids\":[1, 2],\"ok\":true,\"user\":null}\n{\"level\":\"INFO\",\"msg\":\"bar\",\"app\":\"api\",\"rate\":0.5}\n"},
+		{`
+		class Recorder
+		  attr_reader("output")
+
+		  def initialize
+		    @output = ""
+		  end
+
+		  def write(str)
+		    @output = @output + str
+		  end
+		end
+
+		r = Recorder.new
+		l = Logger.new(r, { time: false, fields: { id: 1 } })
+		l.info("foo")
+		l.with({ id: 2 }).info("bar")
+		print(r.output)
+		`, "level=INFO msg=foo id=1\nlevel=INFO msg=bar id=2\n"},
+	}
+
+	for i, tt := range tests {
+		var out bytes.Buffer
+		dir, _ := os.Getwd()
+		v, err := New(dir, []string{}, WithOutput(&out))
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		v.mode = parser.TestMode
+		v.testEval(t, "require \"logger\"\n"+tt.input, getFilename())
+
+		if out.String() != tt.expectedOutput {
+			t.Errorf("At case %d expect output to be %q. got: %q", i, tt.expectedOutput, out.String())
+		}
+
+		v.checkCFP(t, i, 0)
+	}
+}
+
+func TestLoggerTime(t *testing.T) {
+	var out bytes.Buffer
+	dir, _ := os.Getwd()
+	v, err := New(dir, []string{}, WithOutput(&out))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	v.mode = parser.TestMode
+	v.testEval(t, `
+	require "logger"
+	Logger.new.info("foo")
+	Logger.new({ format: "json" }).info("foo")
+	`, getFilename())
+
+	expected := regexp.MustCompile(`^time=\d{4}-\d{2}-\d{2}T\S+ level=INFO msg=foo\n\{"time":"\d{4}-\d{2}-\d{2}T[^"]+","level":"INFO","msg":"foo"\}\n$`)
+
+	if !expected.MatchString(out.String()) {
+		t.Errorf("Expect output to match %s. got: %q", expected, out.String())
+	}
+}
+
+func TestLoggerServerAccessLog(t *testing.T) {
+	var out bytes.Buffer
+	dir, _ := os.Getwd()
+	v, err := New(dir, []string{}, WithOutput(&out))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	v.mode = parser.TestMode
+	server := v.testEval(t, `
+	require "logger"
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(4000)
+	server.logger = Logger.new({ time: false })
+	server
+	`, getFilename())
+
+	logServerAccess(&v.mainThread, server, httptest.NewRequest("GET", "/posts", nil), 200)
+	logServer(&v.mainThread, server, "SimpleServer gracefully stopped")
+
+	expected := "level=INFO msg=Request method=GET path=/posts proto=HTTP/1.1 status=200\nlevel=INFO msg=\"SimpleServer gracefully stopped\"\n"
+
+	if out.String() != expected {
+		t.Errorf("Expect output to be %q. got: %q", expected, out.String())
+	}
+}
@@ -0,0 +1,444 @@
+package vm
+
+import (
+	"math"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Math is a module that provides the basic trigonometric, logarithmic and exponential functions,
+// which mirror the ones in Go's `math` package. All the functions accept Integer or Float arguments
+// and return a Float.
+//
+// A `Math::DomainError` is raised when an argument is out of the function's domain.
+//
+// ```ruby
+// Math.sqrt(16)       # => 4.0
+// Math.sin(Math::PI / 2) # => 1.0
+// Math.log(Math::E)   # => 1.0
+// Math.sqrt(-1)       # => Math::DomainError: Numerical argument is out of domain - "sqrt"
+// ```
+//
+// - `Math.new` is not supported.
+
+// Class methods --------------------------------------------------------
+var builtinMathClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the arc cosine of the given number, in radians.
+		//
+		// ```ruby
+		// Math.acos(1) # => 0.0
+		// ```
+		//
+		// @param x [Numeric] between -1 and 1
+		// @return [Float]
+		Name: "acos",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "acos", math.Acos)
+
+		},
+	},
+	{
+		// Returns the inverse hyperbolic cosine of the given number.
+		//
+		// ```ruby
+		// Math.acosh(1) # => 0.0
+		// ```
+		//
+		// @param x [Numeric] greater than or equal to 1
+		// @return [Float]
+		Name: "acosh",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "acosh", math.Acosh)
+
+		},
+	},
+	{
+		// Returns the arc sine of the given number, in radians.
+		//
+		// ```ruby
+		// Math.asin(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric] between -1 and 1
+		// @return [Float]
+		Name: "asin",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "asin", math.Asin)
+
+		},
+	},
+	{
+		// Returns the inverse hyperbolic sine of the given number.
+		//
+		// ```ruby
+		// Math.asinh(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "asinh",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "asinh", math.Asinh)
+
+		},
+	},
+	{
+		// Returns the arc tangent of the given number, in radians.
+		//
+		// ```ruby
+		// Math.atan(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "atan",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "atan", math.Atan)
+
+		},
+	},
+	{
+		// Returns the arc tangent of y/x, in radians. The signs of both arguments determine the quadrant of the result.
+		//
+		// ```ruby
+		// Math.atan2(0, -1) # => 3.141592653589793
+		// ```
+		//
+		// @param y [Numeric]
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "atan2",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction2(t, sourceLine, args, "atan2", math.Atan2)
+
+		},
+	},
+	{
+		// Returns the inverse hyperbolic tangent of the given number.
+		//
+		// ```ruby
+		// Math.atanh(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric] between -1 and 1
+		// @return [Float]
+		Name: "atanh",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "atanh", math.Atanh)
+
+		},
+	},
+	{
+		// Returns the cube root of the given number.
+		//
+		// ```ruby
+		// Math.cbrt(27) # => 3.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "cbrt",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "cbrt", math.Cbrt)
+
+		},
+	},
+	{
+		// Returns the cosine of the given angle in radians.
+		//
+		// ```ruby
+		// Math.cos(0) # => 1.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "cos",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "cos", math.Cos)
+
+		},
+	},
+	{
+		// Returns the hyperbolic cosine of the given number.
+		//
+		// ```ruby
+		// Math.cosh(0) # => 1.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "cosh",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "cosh", math.Cosh)
+
+		},
+	},
+	{
+		// Returns e raised to the power of the given number.
+		//
+		// ```ruby
+		// Math.exp(0) # => 1.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "exp",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "exp", math.Exp)
+
+		},
+	},
+	{
+		// Returns the gamma function of the given number.
+		//
+		// ```ruby
+		// Math.gamma(5) # => 24.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "gamma",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "gamma", math.Gamma)
+
+		},
+	},
+	{
+		// Returns the hypotenuse of a right-angle triangle with sides x and y, which is `sqrt(x**2 + y**2)`.
+		//
+		// ```ruby
+		// Math.hypot(3, 4) # => 5.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @param y [Numeric]
+		// @return [Float]
+		Name: "hypot",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction2(t, sourceLine, args, "hypot", math.Hypot)
+
+		},
+	},
+	{
+		// Returns the natural logarithm of the given number. If the base is given, returns the logarithm in that base.
+		//
+		// ```ruby
+		// Math.log(1)      # => 0.0
+		// Math.log(8, 2)   # => 3.0
+		// Math.log(0)      # => -Infinity
+		// ```
+		//
+		// @param x [Numeric] greater than or equal to 0
+		// @param base [Numeric] optional
+		// @return [Float]
+		Name: "log",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) == 2 {
+				return mathFunction2(t, sourceLine, args, "log", func(x, base float64) float64 {
+					return math.Log(x) / math.Log(base)
+				})
+			}
+
+			return mathFunction(t, sourceLine, args, "log", math.Log)
+
+		},
+	},
+	{
+		// Returns the base 10 logarithm of the given number.
+		//
+		// ```ruby
+		// Math.log10(1000) # => 3.0
+		// ```
+		//
+		// @param x [Numeric] greater than or equal to 0
+		// @return [Float]
+		Name: "log10",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "log10", math.Log10)
+
+		},
+	},
+	{
+		// Returns the base 2 logarithm of the given number.
+		//
+		// ```ruby
+		// Math.log2(8) # => 3.0
+		// ```
+		//
+		// @param x [Numeric] greater than or equal to 0
+		// @return [Float]
+		Name: "log2",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "log2", math.Log2)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Returns x raised to the power of y.
+		//
+		// ```ruby
+		// Math.pow(2, 10)  # => 1024.0
+		// Math.pow(4, 0.5) # => 2.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @param y [Numeric]
+		// @return [Float]
+		Name: "pow",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction2(t, sourceLine, args, "pow", math.Pow)
+
+		},
+	},
+	{
+		// Returns the sine of the given angle in radians.
+		//
+		// ```ruby
+		// Math.sin(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "sin",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "sin", math.Sin)
+
+		},
+	},
+	{
+		// Returns the hyperbolic sine of the given number.
+		//
+		// ```ruby
+		// Math.sinh(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "sinh",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "sinh", math.Sinh)
+
+		},
+	},
+	{
+		// Returns the square root of the given number.
+		//
+		// ```ruby
+		// Math.sqrt(9) # => 3.0
+		// ```
+		//
+		// @param x [Numeric] greater than or equal to 0
+		// @return [Float]
+		Name: "sqrt",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "sqrt", math.Sqrt)
+
+		},
+	},
+	{
+		// Returns the tangent of the given angle in radians.
+		//
+		// ```ruby
+		// Math.tan(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "tan",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "tan", math.Tan)
+
+		},
+	},
+	{
+		// Returns the hyperbolic tangent of the given number.
+		//
+		// ```ruby
+		// Math.tanh(0) # => 0.0
+		// ```
+		//
+		// @param x [Numeric]
+		// @return [Float]
+		Name: "tanh",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return mathFunction(t, sourceLine, args, "tanh", math.Tanh)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initMathModule() *RClass {
+	m := vm.initializeModule(classes.MathModule)
+	m.setBuiltinMethods(builtinMathClassMethods, true)
+	m.setConstant("PI", &Pointer{Target: vm.initFloatObject(math.Pi)})
+	m.setConstant("E", &Pointer{Target: vm.initFloatObject(math.E)})
+
+	domainError := vm.initializeClass("DomainError")
+	m.setClassConstant(domainError)
+
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// mathFunction applies the given function to a Numeric argument, and raises `Math::DomainError`
+// when the function isn't defined for the argument
+func mathFunction(t *Thread, sourceLine int, args []Object, name string, fn func(float64) float64) Object {
+	if len(args) != 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	x, ok := args[0].(Numeric)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+	}
+
+	result := fn(x.floatValue())
+
+	if math.IsNaN(result) && !math.IsNaN(x.floatValue()) {
+		return t.vm.InitErrorObject(errors.DomainError, sourceLine, errors.OutOfDomain, name)
+	}
+
+	return t.vm.initFloatObject(result)
+}
+
+// mathFunction2 is the same as mathFunction, but for functions that take two Numeric arguments
+func mathFunction2(t *Thread, sourceLine int, args []Object, name string, fn func(float64, float64) float64) Object {
+	if len(args) != 2 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+	}
+
+	x, ok := args[0].(Numeric)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, "Numeric", args[0].Class().Name)
+	}
+
+	y, ok := args[1].(Numeric)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, "Numeric", args[1].Class().Name)
+	}
+
+	result := fn(x.floatValue(), y.floatValue())
+
+	if math.IsNaN(result) && !math.IsNaN(x.floatValue()) && !math.IsNaN(y.floatValue()) {
+		return t.vm.InitErrorObject(errors.DomainError, sourceLine, errors.OutOfDomain, name)
+	}
+
+	return t.vm.initFloatObject(result)
+}
@@ -0,0 +1,91 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestMathModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Math.class.name`, "Module"},
+		{`Math::PI`, 3.141592653589793},
+		{`Math::E`, 2.718281828459045},
+		{`Math::DomainError.name`, "DomainError"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMathFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Math.sqrt(9)`, 3.0},
+		{`Math.sqrt(2.25)`, 1.5},
+		{`Math.cbrt(27)`, 3.0},
+		{`Math.sin(0)`, 0.0},
+		{`Math.cos(0)`, 1.0},
+		{`Math.tan(0)`, 0.0},
+		{`Math.asin(0)`, 0.0},
+		{`Math.acos(1)`, 0.0},
+		{`Math.atan(0)`, 0.0},
+		{`Math.atan2(0, -1)`, 3.141592653589793},
+		{`Math.sinh(0)`, 0.0},
+		{`Math.cosh(0)`, 1.0},
+		{`Math.tanh(0)`, 0.0},
+		{`Math.asinh(0)`, 0.0},
+		{`Math.acosh(1)`, 0.0},
+		{`Math.atanh(0)`, 0.0},
+		{`Math.exp(0)`, 1.0},
+		{`Math.log(1)`, 0.0},
+		{`Math.log(Math::E)`, 1.0},
+		{`Math.log(8, 2)`, 3.0},
+		{`Math.log(0).to_s`, "-Infinity"},
+		{`Math.log2(8)`, 3.0},
+		{`Math.log10(1000)`, 3.0},
+		{`Math.hypot(3, 4)`, 5.0},
+		{`Math.pow(2, 10)`, 1024.0},
+		{`Math.pow(4, 0.5)`, 2.0},
+		{`Math.gamma(5)`, 24.0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMathFunctionsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Math.sqrt(-1)`, "Math::DomainError: Numerical argument is out of domain - \"sqrt\"", 1},
+		{`Math.log(-1)`, "Math::DomainError: Numerical argument is out of domain - \"log\"", 1},
+		{`Math.log(-8, 2)`, "Math::DomainError: Numerical argument is out of domain - \"log\"", 1},
+		{`Math.acos(2)`, "Math::DomainError: Numerical argument is out of domain - \"acos\"", 1},
+		{`Math.acosh(0)`, "Math::DomainError: Numerical argument is out of domain - \"acosh\"", 1},
+		{`Math.sqrt`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`Math.sqrt("4")`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`Math.hypot(3)`, "ArgumentError: Expect 2 argument(s). got: 1", 1},
+		{`Math.atan2(1, "1")`, "TypeError: Expect argument #2 to be Numeric. got: String", 1},
+		{`Math.new`, "NoMethodError: Undefined Method 'new' for Math", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
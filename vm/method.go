@@ -41,9 +41,9 @@ func (m *MethodObject) Inspect() string {
 	return m.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (m *MethodObject) ToJSON(t *Thread) string {
-	return m.ToString()
+	return jsonString(m.ToString())
 }
 
 // Value returns method object's string format
@@ -111,9 +111,9 @@ func (bim *BuiltinMethodObject) Inspect() string {
 	return bim.ToString()
 }
 
-// ToJSON just delegates to `ToString`
+// ToJSON returns the quoted ToString
 func (bim *BuiltinMethodObject) ToJSON(t *Thread) string {
-	return bim.ToString()
+	return jsonString(bim.ToString())
 }
 
 // Value returns builtin method object's function
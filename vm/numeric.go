@@ -1,5 +1,7 @@
 package vm
 
+import "time"
+
 // Numeric currently represents a class that support some numeric conversions.
 // At this stage, it's not meant to be a Goby class in a strict sense, but only
 // a convenient interface.
@@ -7,3 +9,8 @@ type Numeric interface {
 	floatValue() float64
 	lessThan(object Object) bool
 }
+
+// secondsToDuration converts a number of seconds to Go's `time.Duration`
+func secondsToDuration(n Numeric) time.Duration {
+	return time.Duration(n.floatValue() * float64(time.Second))
+}
@@ -3,6 +3,9 @@ package vm
 import (
 	"fmt"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"unsafe"
 
 	"reflect"
 
@@ -35,6 +38,9 @@ type BaseObj struct {
 	class             *RClass
 	singletonClass    *RClass
 	InstanceVariables *environment
+	// lock is a *reentrantLock only set by `thread_safe!`, and serializes the calls of builtin methods on the object.
+	// It's accessed atomically because `thread_safe!` can be called while other threads are using the object.
+	lock unsafe.Pointer
 }
 
 // NewBaseObject creates a BaseObj
@@ -131,6 +137,14 @@ func (b *BaseObj) findMethodMissing(searchAncestor bool) (method Object) {
 	return
 }
 
+func (b *BaseObj) threadSafeLock() *reentrantLock {
+	return (*reentrantLock)(atomic.LoadPointer(&b.lock))
+}
+
+func (b *BaseObj) makeThreadSafe() {
+	atomic.CompareAndSwapPointer(&b.lock, nil, unsafe.Pointer(newReentrantLock()))
+}
+
 // ID returns the BaseObj's id
 func (b *BaseObj) ID() int {
 	return b.id
@@ -150,6 +164,46 @@ func (b *BaseObj) equalTo(with Object) bool {
 	return false
 }
 
+// reentrantLock =======================================================
+
+// reentrantLock is a mutex that can be acquired again by the thread holding it.
+// This is needed because a builtin method like `Array#each` yields to a block while holding the lock,
+// and the block may call other methods on the same object.
+type reentrantLock struct {
+	mutex *sync.Mutex
+	cond  *sync.Cond
+	owner int64
+	count int
+}
+
+func newReentrantLock() *reentrantLock {
+	m := &sync.Mutex{}
+	return &reentrantLock{mutex: m, cond: sync.NewCond(m)}
+}
+
+func (l *reentrantLock) lock(threadID int64) {
+	l.mutex.Lock()
+
+	for l.count > 0 && l.owner != threadID {
+		l.cond.Wait()
+	}
+
+	l.owner = threadID
+	l.count++
+	l.mutex.Unlock()
+}
+
+func (l *reentrantLock) unlock() {
+	l.mutex.Lock()
+	l.count--
+
+	if l.count == 0 {
+		l.cond.Signal()
+	}
+
+	l.mutex.Unlock()
+}
+
 // Pointer ==============================================================
 
 // Pointer is used to point to an object. Variables should hold pointer instead of holding a object directly.
@@ -187,18 +241,18 @@ func (ro *RObject) Inspect() string {
 	return "#<" + ro.class.Name + ":" + fmt.Sprint(ro.ID()) + " " + iv + ">"
 }
 
-// ToJSON just delegates to ToString
+// ToJSON calls the `to_json` method defined in the class, or returns the quoted ToString
 func (ro *RObject) ToJSON(t *Thread) string {
-	customToJSONMethod := ro.findMethod("to_json").(*MethodObject)
+	customToJSONMethod, ok := ro.findMethod("to_json").(*MethodObject)
 
-	if customToJSONMethod != nil {
+	if ok {
 		t.Stack.Push(&Pointer{Target: ro})
 		callObj := newCallObject(ro, customToJSONMethod, t.Stack.pointer, 0, &bytecode.ArgSet{}, nil, customToJSONMethod.instructionSet.instructions[0].SourceLine())
 		t.evalMethodObject(callObj)
 		result := t.Stack.Pop().Target
 		return result.ToString()
 	}
-	return ro.ToString()
+	return jsonString(ro.ToString())
 }
 
 // Value returns object's string format
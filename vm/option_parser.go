@@ -0,0 +1,749 @@
+package vm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// OptionParserObject parses command line options into an `OptionParser::Result`.
+// Flags are declared with their long names, and a Hash of the `short` name, the `help` text and the `default` value.
+// `-h` and `--help` print the generated help text and exit the program.
+//
+// ```ruby
+// require "option_parser"
+//
+// parser = OptionParser.new("deploy", "Deploys the app")
+// parser.bool("verbose", { short: "v", help: "Prints more logs" })
+// parser.string("env", { short: "e", help: "Target environment", default: "staging" })
+// parser.int("port", { help: "Port to listen", default: 8080 })
+// parser.list("tag", { short: "t", help: "Tags to deploy" })
+// parser.command("rollback", "Rolls back the last release") do |cmd|
+//   cmd.int("steps", { default: 1 })
+// end
+//
+// result = parser.parse(["-v", "--env=production", "-t", "web", "-t", "api", "rollback", "--steps", "2"])
+// result["env"]   # => "production"
+// result["tag"]   # => ["web", "api"]
+// result.command  # => "rollback"
+// result["steps"] # => 2
+// ```
+//
+// Long flags can be given as `--name value` or `--name=value`, and short flags as `-n value` or `-nvalue`.
+// Bool flags can be combined like `-vq`, and turned off like `--no-verbose`. Arguments after `--` are not parsed.
+// An invalid option raises `OptionParser::ParseError`.
+type OptionParserObject struct {
+	*BaseObj
+	name        string
+	description string
+	flags       []*optionFlag
+	commands    []*OptionParserObject
+}
+
+// OptionParserResultObject holds the parsed options, the positional arguments and the command.
+type OptionParserResultObject struct {
+	*BaseObj
+	values    map[string]Object
+	arguments []string
+	command   string
+}
+
+// optionFlag is a flag declared by `bool`, `string`, `int` or `list`
+type optionFlag struct {
+	name         string
+	short        string
+	help         string
+	kind         string
+	defaultValue Object
+}
+
+const (
+	optionParserClass  = "OptionParser"
+	optionParserResult = "Result"
+)
+
+// Class methods --------------------------------------------------------
+var builtinOptionParserClassMethods = []*BuiltinMethodObject{
+	{
+		// Creates a parser with the program's name and description, which are shown in the help text.
+		//
+		// @param name [String], description [String]
+		// @return [OptionParser]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 2, len(args))
+			}
+
+			strs := []string{"", ""}
+
+			for i, arg := range args {
+				s, ok := arg.(*StringObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.StringClass, arg.Class().Name)
+				}
+
+				strs[i] = s.value
+			}
+
+			return t.vm.initOptionParserObject(strs[0], strs[1])
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinOptionParserInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Declares a flag that is true when given. It's false by default.
+		//
+		// ```ruby
+		// parser.bool("force", { short: "f", help: "Overwrites existing files" })
+		// ```
+		//
+		// @param name [String], options [Hash]
+		// @return [OptionParser]
+		Name: "bool",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*OptionParserObject).declare(t, "bool", args, sourceLine)
+
+		},
+	},
+	{
+		// Declares a subcommand, which is yielded to the block to declare its own flags. Returns the subcommand's parser.
+		// The subcommand is selected by the first positional argument.
+		//
+		// ```ruby
+		// parser.command("deploy", "Deploys the app") do |cmd|
+		//   cmd.bool("dry_run")
+		// end
+		// ```
+		//
+		// @param name [String], description [String]
+		// @return [OptionParser]
+		Name: "command",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			strs := []string{"", ""}
+
+			for i, arg := range args {
+				s, ok := arg.(*StringObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.StringClass, arg.Class().Name)
+				}
+
+				strs[i] = s.value
+			}
+
+			p := receiver.(*OptionParserObject)
+			cmd := t.vm.initOptionParserObject(strs[0], strs[1])
+			p.commands = append(p.commands, cmd)
+
+			if blockFrame != nil {
+				t.builtinMethodYield(blockFrame, cmd)
+			}
+
+			return cmd
+
+		},
+	},
+	{
+		// Returns the help text.
+		//
+		// ```ruby
+		// puts(parser.help)
+		// # Usage: deploy [options]
+		// #
+		// # Deploys the app
+		// #
+		// # Options:
+		// #   -e, --env ENV    Target environment (default: staging)
+		// #   -h, --help       Shows this help
+		// ```
+		//
+		// @return [String]
+		Name: "help",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitStringObject(receiver.(*OptionParserObject).helpText())
+
+		},
+	},
+	{
+		// Declares a flag that takes an Integer value. It's nil by default.
+		//
+		// ```ruby
+		// parser.int("workers", { short: "w", default: 4 })
+		// ```
+		//
+		// @param name [String], options [Hash]
+		// @return [OptionParser]
+		Name: "int",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*OptionParserObject).declare(t, "int", args, sourceLine)
+
+		},
+	},
+	{
+		// Declares a flag that can be given multiple times, whose values are collected into an Array. It's empty by default.
+		//
+		// ```ruby
+		// parser.list("exclude", { short: "x" })
+		// parser.parse(["-x", "tmp", "-x", "log"])["exclude"] # => ["tmp", "log"]
+		// ```
+		//
+		// @param name [String], options [Hash]
+		// @return [OptionParser]
+		Name: "list",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*OptionParserObject).declare(t, "list", args, sourceLine)
+
+		},
+	},
+	{
+		// Parses the given arguments, or `ARGV` by default, and returns an `OptionParser::Result`.
+		// If `-h` or `--help` is given, prints the help text of the parser or the selected command, and exits.
+		//
+		// ```ruby
+		// result = parser.parse
+		// result.arguments # => the positional arguments
+		// ```
+		//
+		// @param arguments [Array]
+		// @return [OptionParser::Result]
+		Name: "parse",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			argv := t.vm.args
+
+			if len(args) == 1 {
+				arr, ok := args[0].(*ArrayObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.ArrayClass, args[0].Class().Name)
+				}
+
+				argv = []string{}
+
+				for _, elem := range arr.Elements {
+					s, ok := elem.(*StringObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, elem.Class().Name)
+					}
+
+					argv = append(argv, s.value)
+				}
+			}
+
+			result, helpParser, err := receiver.(*OptionParserObject).parse(t, argv)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.ParseError, sourceLine, "%s", err.Error())
+			}
+
+			if helpParser != nil {
+				if err := t.writeOutput("$stdout", helpParser.helpText(), sourceLine); err != nil {
+					return err
+				}
+
+				t.vm.exit(0)
+			}
+
+			return result
+
+		},
+	},
+	{
+		// Declares a flag that takes a String value. It's nil by default.
+		//
+		// ```ruby
+		// parser.string("config", { short: "c", help: "Config file", default: "config.yml" })
+		// ```
+		//
+		// @param name [String], options [Hash]
+		// @return [OptionParser]
+		Name: "string",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*OptionParserObject).declare(t, "string", args, sourceLine)
+
+		},
+	},
+}
+
+// Class methods --------------------------------------------------------
+var builtinOptionParserResultClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinOptionParserResultInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Returns the value of the flag, or nil if the flag isn't declared.
+		//
+		// @param name [String]
+		// @return [Object]
+		Name: "[]",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			name, ok := args[0].(*StringObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+			}
+
+			v, ok := receiver.(*OptionParserResultObject).values[name.value]
+
+			if !ok {
+				return NULL
+			}
+
+			return v
+
+		},
+	},
+	{
+		// Returns the positional arguments.
+		//
+		// @return [Array]
+		Name: "arguments",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.initStringsArray(receiver.(*OptionParserResultObject).arguments)
+
+		},
+	},
+	{
+		// Returns the name of the selected command, or nil.
+		//
+		// @return [String]
+		Name: "command",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			r := receiver.(*OptionParserResultObject)
+
+			if r.command == "" {
+				return NULL
+			}
+
+			return t.vm.InitStringObject(r.command)
+
+		},
+	},
+	{
+		// Returns the flags' values as a Hash.
+		//
+		// @return [Hash]
+		Name: "to_h",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			pairs := map[string]Object{}
+
+			for name, v := range receiver.(*OptionParserResultObject).values {
+				pairs[name] = v
+			}
+
+			return t.vm.InitHashObject(pairs)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initOptionParserClass(vm *VM) {
+	pc := vm.initializeClass(optionParserClass)
+	pc.setBuiltinMethods(builtinOptionParserClassMethods, true)
+	pc.setBuiltinMethods(builtinOptionParserInstanceMethods, false)
+
+	rc := vm.initializeClass(optionParserResult)
+	rc.setBuiltinMethods(builtinOptionParserResultClassMethods, true)
+	rc.setBuiltinMethods(builtinOptionParserResultInstanceMethods, false)
+	pc.setClassConstant(rc)
+
+	pc.setClassConstant(vm.initializeClass("ParseError"))
+	vm.objectClass.setClassConstant(pc)
+}
+
+func (vm *VM) initOptionParserObject(name, description string) *OptionParserObject {
+	return &OptionParserObject{
+		BaseObj:     NewBaseObject(vm.objectClass.getClassConstant(optionParserClass)),
+		name:        name,
+		description: description,
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the parser's name
+func (p *OptionParserObject) ToString() string {
+	return "#<OptionParser: " + p.name + ">"
+}
+
+// Inspect delegates to ToString
+func (p *OptionParserObject) Inspect() string {
+	return p.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (p *OptionParserObject) ToJSON(t *Thread) string {
+	return strconv.Quote(p.ToString())
+}
+
+// Value returns the parser's name
+func (p *OptionParserObject) Value() interface{} {
+	return p.name
+}
+
+// ToString returns the parsed values
+func (r *OptionParserResultObject) ToString() string {
+	names := []string{}
+
+	for name := range r.values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	pairs := []string{}
+
+	for _, name := range names {
+		pairs = append(pairs, name+": "+r.values[name].Inspect())
+	}
+
+	return "#<OptionParser::Result " + strings.Join(pairs, ", ") + ">"
+}
+
+// Inspect delegates to ToString
+func (r *OptionParserResultObject) Inspect() string {
+	return r.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (r *OptionParserResultObject) ToJSON(t *Thread) string {
+	return strconv.Quote(r.ToString())
+}
+
+// Value returns the parsed values
+func (r *OptionParserResultObject) Value() interface{} {
+	return r.values
+}
+
+// Other helper functions -----------------------------------------------
+
+// declare adds a flag of the kind with the arguments of `bool`, `string`, `int` or `list`
+func (p *OptionParserObject) declare(t *Thread, kind string, args []Object, sourceLine int) Object {
+	if len(args) < 1 || len(args) > 2 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+	}
+
+	name, ok := args[0].(*StringObject)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.StringClass, args[0].Class().Name)
+	}
+
+	flag := &optionFlag{name: name.value, kind: kind}
+
+	if len(args) == 2 {
+		options, ok := args[1].(*HashObject)
+
+		if !ok {
+			return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.HashClass, args[1].Class().Name)
+		}
+
+		for _, key := range options.sortedKeys() {
+			value := options.Pairs[key]
+
+			switch key {
+			case "short", "help":
+				s, ok := value.(*StringObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, value.Class().Name)
+				}
+
+				if key == "short" {
+					flag.short = s.value
+				} else {
+					flag.help = s.value
+				}
+			case "default":
+				expected := map[string]string{"bool": classes.BooleanClass, "string": classes.StringClass, "int": classes.IntegerClass, "list": classes.ArrayClass}[kind]
+
+				if value.Class().Name != expected {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, expected, value.Class().Name)
+				}
+
+				flag.defaultValue = value
+			default:
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+			}
+		}
+	}
+
+	p.flags = append(p.flags, flag)
+	return p
+}
+
+// parse parses the arguments. If help is requested, the parser whose help text should be shown is returned instead.
+func (p *OptionParserObject) parse(t *Thread, argv []string) (*OptionParserResultObject, *OptionParserObject, error) {
+	result := &OptionParserResultObject{
+		BaseObj: NewBaseObject(p.class.getClassConstant(optionParserResult)),
+		values:  map[string]Object{},
+	}
+
+	current := p
+	flags := map[string]*optionFlag{}
+	p.setDefaults(t, result, flags)
+
+	for i := 0; i < len(argv); i++ {
+		arg := argv[i]
+
+		switch {
+		case arg == "--":
+			result.arguments = append(result.arguments, argv[i+1:]...)
+			return result, nil, nil
+		case arg == "-h" || arg == "--help":
+			return nil, current, nil
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := splitOption(arg[2:])
+			flag, ok := flags[name]
+
+			if !ok && strings.HasPrefix(name, "no-") && !hasValue {
+				if f, found := flags[name[3:]]; found && f.kind == "bool" {
+					result.values[f.name] = FALSE
+					continue
+				}
+			}
+
+			if !ok {
+				return nil, nil, fmt.Errorf("Unknown option: --%s", name)
+			}
+
+			if flag.kind == "bool" {
+				if hasValue {
+					return nil, nil, fmt.Errorf("Option --%s doesn't take a value", name)
+				}
+
+				result.values[flag.name] = TRUE
+				continue
+			}
+
+			if !hasValue {
+				i++
+
+				if i == len(argv) {
+					return nil, nil, fmt.Errorf("Missing value for option: --%s", name)
+				}
+
+				value = argv[i]
+			}
+
+			if err := flag.set(t, result, value); err != nil {
+				return nil, nil, err
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			shorts := arg[1:]
+
+			for j := 0; j < len(shorts); j++ {
+				flag, ok := flags["-"+shorts[j:j+1]]
+
+				if !ok {
+					return nil, nil, fmt.Errorf("Unknown option: -%s", shorts[j:j+1])
+				}
+
+				if flag.kind == "bool" {
+					result.values[flag.name] = TRUE
+					continue
+				}
+
+				// The rest of the argument, or the next argument, is the value
+				value := strings.TrimPrefix(shorts[j+1:], "=")
+
+				if value == "" {
+					i++
+
+					if i == len(argv) {
+						return nil, nil, fmt.Errorf("Missing value for option: -%s", flag.short)
+					}
+
+					value = argv[i]
+				}
+
+				if err := flag.set(t, result, value); err != nil {
+					return nil, nil, err
+				}
+
+				break
+			}
+		default:
+			cmd := current.findCommand(arg)
+
+			if result.command == "" && len(result.arguments) == 0 && cmd != nil {
+				current = cmd
+				result.command = cmd.name
+				cmd.setDefaults(t, result, flags)
+				continue
+			}
+
+			result.arguments = append(result.arguments, arg)
+		}
+	}
+
+	return result, nil, nil
+}
+
+// setDefaults sets the flags' default values to the result, and registers the flags by their names
+func (p *OptionParserObject) setDefaults(t *Thread, result *OptionParserResultObject, flags map[string]*optionFlag) {
+	for _, flag := range p.flags {
+		flags[flag.name] = flag
+
+		if flag.short != "" {
+			flags["-"+flag.short] = flag
+		}
+
+		switch {
+		case flag.defaultValue != nil:
+			result.values[flag.name] = flag.defaultValue
+		case flag.kind == "bool":
+			result.values[flag.name] = FALSE
+		case flag.kind == "list":
+			result.values[flag.name] = t.vm.InitArrayObject([]Object{})
+		default:
+			result.values[flag.name] = NULL
+		}
+	}
+}
+
+func (p *OptionParserObject) findCommand(name string) *OptionParserObject {
+	for _, cmd := range p.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+// set sets the flag's value to the result. The values of a list flag are appended to the default ones.
+func (f *optionFlag) set(t *Thread, result *OptionParserResultObject, value string) error {
+	switch f.kind {
+	case "int":
+		n, err := strconv.Atoi(value)
+
+		if err != nil {
+			return fmt.Errorf("Invalid value for option: --%s %s", f.name, value)
+		}
+
+		result.values[f.name] = t.vm.InitIntegerObject(n)
+	case "list":
+		current := result.values[f.name].(*ArrayObject)
+		elems := append([]Object{}, current.Elements...)
+		result.values[f.name] = t.vm.InitArrayObject(append(elems, t.vm.InitStringObject(value)))
+	default:
+		result.values[f.name] = t.vm.InitStringObject(value)
+	}
+
+	return nil
+}
+
+// helpText generates the usage, the description, the flags and the commands of the parser
+func (p *OptionParserObject) helpText() string {
+	var b strings.Builder
+
+	usage := "Usage: " + p.name + " [options]"
+
+	if len(p.commands) > 0 {
+		usage += " <command>"
+	}
+
+	b.WriteString(usage + "\n")
+
+	if p.description != "" {
+		b.WriteString("\n" + p.description + "\n")
+	}
+
+	flags := append(append([]*optionFlag{}, p.flags...), &optionFlag{name: "help", short: "h", help: "Shows this help", kind: "bool"})
+	labels := []string{}
+	width := 0
+
+	for _, flag := range flags {
+		label := "    --" + flag.name
+
+		if flag.short != "" {
+			label = "-" + flag.short + ", --" + flag.name
+		}
+
+		if flag.kind != "bool" {
+			label += " " + strings.ToUpper(flag.name)
+		}
+
+		labels = append(labels, label)
+
+		if len(label) > width {
+			width = len(label)
+		}
+	}
+
+	for _, cmd := range p.commands {
+		if len(cmd.name) > width {
+			width = len(cmd.name)
+		}
+	}
+
+	b.WriteString("\nOptions:\n")
+
+	for i, flag := range flags {
+		help := flag.help
+
+		if flag.defaultValue != nil {
+			help = strings.TrimSpace(help + " (default: " + flag.defaultValue.ToString() + ")")
+		}
+
+		if flag.kind == "list" {
+			help = strings.TrimSpace(help + " (can be given multiple times)")
+		}
+
+		b.WriteString(strings.TrimRight(fmt.Sprintf("  %-*s  %s", width, labels[i], help), " ") + "\n")
+	}
+
+	if len(p.commands) > 0 {
+		b.WriteString("\nCommands:\n")
+
+		for _, cmd := range p.commands {
+			b.WriteString(strings.TrimRight(fmt.Sprintf("  %-*s  %s", width, cmd.name, cmd.description), " ") + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// splitOption splits "name=value" into the name and the value
+func splitOption(s string) (name, value string, hasValue bool) {
+	i := strings.Index(s, "=")
+
+	if i < 0 {
+		return s, "", false
+	}
+
+	return s[:i], s[i+1:], true
+}
@@ -0,0 +1,112 @@
+package vm
+
+import (
+	"testing"
+)
+
+const optionParserTestSetup = `
+require "option_parser"
+
+parser = OptionParser.new("deploy", "Deploys the app")
+parser.bool("verbose", { short: "v", help: "Prints more logs" })
+parser.bool("quiet", { short: "q" })
+parser.string("env", { short: "e", help: "Target environment", default: "staging" })
+parser.int("port", { short: "p", default: 8080 })
+parser.list("tag", { short: "t", help: "Tags to deploy" })
+parser.command("rollback", "Rolls back the last release") do |cmd|
+  cmd.int("steps", { default: 1 })
+end
+`
+
+func TestOptionParserParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		r = parser.parse([])
+		[r["verbose"], r["env"], r["port"], r["tag"], r["steps"], r.command, r.arguments]
+		`, []interface{}{false, "staging", 8080, []interface{}{}, nil, nil, []interface{}{}}},
+		{`
+		r = parser.parse(["--verbose", "--env", "production", "--port=3000", "--tag", "web", "--tag=api"])
+		[r["verbose"], r["env"], r["port"], r["tag"]]
+		`, []interface{}{true, "production", 3000, []interface{}{"web", "api"}}},
+		{`
+		r = parser.parse(["-vq", "-eproduction", "-p", "3000", "-t", "web"])
+		[r["verbose"], r["quiet"], r["env"], r["port"], r["tag"]]
+		`, []interface{}{true, true, "production", 3000, []interface{}{"web"}}},
+		{`
+		r = parser.parse(["-v", "--no-verbose"])
+		r["verbose"]
+		`, false},
+		{`
+		r = parser.parse(["-v", "rollback", "--steps", "3", "now"])
+		[r.command, r["verbose"], r["steps"], r.arguments]
+		`, []interface{}{"rollback", true, 3, []interface{}{"now"}}},
+		{`
+		r = parser.parse(["rollback"])
+		[r.command, r["steps"]]
+		`, []interface{}{"rollback", 1}},
+		{`
+		r = parser.parse(["app", "rollback"])
+		[r.command, r.arguments]
+		`, []interface{}{nil, []interface{}{"app", "rollback"}}},
+		{`
+		r = parser.parse(["-v", "--", "-q", "--env"])
+		[r["quiet"], r.arguments]
+		`, []interface{}{false, []interface{}{"-q", "--env"}}},
+		{`
+		r = parser.parse(["-e", "prod"])
+		r.to_h["env"]
+		`, "prod"},
+		{`
+		r = parser.parse([])
+		r["unknown"]
+		`, nil},
+		{`parser.command("status").parse(["x"]).arguments`, []interface{}{"x"}},
+		{`parser.help`, "Usage: deploy [options] <command>\n\nDeploys the app\n\nOptions:\n" +
+			"  -v, --verbose    Prints more logs\n" +
+			"  -q, --quiet\n" +
+			"  -e, --env ENV    Target environment (default: staging)\n" +
+			"  -p, --port PORT  (default: 8080)\n" +
+			"  -t, --tag TAG    Tags to deploy (can be given multiple times)\n" +
+			"  -h, --help       Shows this help\n" +
+			"\nCommands:\n" +
+			"  rollback         Rolls back the last release\n"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, optionParserTestSetup+tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestOptionParserFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`parser.parse(["--foo"])`, "OptionParser::ParseError: Unknown option: --foo", 1},
+		{`parser.parse(["-x"])`, "OptionParser::ParseError: Unknown option: -x", 1},
+		{`parser.parse(["--env"])`, "OptionParser::ParseError: Missing value for option: --env", 1},
+		{`parser.parse(["-e"])`, "OptionParser::ParseError: Missing value for option: -e", 1},
+		{`parser.parse(["--port", "abc"])`, "OptionParser::ParseError: Invalid value for option: --port abc", 1},
+		{`parser.parse(["--verbose=yes"])`, "OptionParser::ParseError: Option --verbose doesn't take a value", 1},
+		{`parser.parse(["--steps", "1"])`, "OptionParser::ParseError: Unknown option: --steps", 1},
+		{`parser.parse("-v")`, "TypeError: Expect argument to be Array. got: String", 1},
+		{`parser.parse([1])`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`parser.bool("force", { long: "f" })`, "ArgumentError: Unknown option - long", 1},
+		{`parser.int("workers", { default: "4" })`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`parser.string(1)`, "TypeError: Expect argument #1 to be String. got: Integer", 1},
+		{`parser.string("name", "n")`, "TypeError: Expect argument #2 to be Hash. got: String", 1},
+		{`OptionParser::Result.new`, "NoMethodError: Undefined Method 'new' for Result", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, optionParserTestSetup+tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
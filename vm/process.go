@@ -0,0 +1,539 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Process is a module for running other programs and signalling processes.
+//
+// A command given as a single String is run by the shell, while a command given as multiple Strings
+// is run directly with the rest as its arguments. A Hash can be given after the command for the options:
+//
+// - `env`: a Hash of environment variables, which are added to the current ones
+// - `dir`: the working directory of the command
+// - `stdin`: a File, or a String that is piped to the command
+// - `stdout`, `stderr`: the File that the command's output is written to, which are `$stdout` and `$stderr` by default
+//
+// ```ruby
+// pid = Process.spawn("docker", "build", ".", { env: { DOCKER_BUILDKIT: "1" } })
+// status = Process.wait(pid)
+// status.success? # => true
+//
+// out, err, status = Process.capture("git rev-parse HEAD")
+// ```
+//
+// `Kernel#system` and backticks run a command and wait for it, and their status is returned by `Process.last_status`.
+//
+// - `Process.new` is not supported.
+
+// ProcessStatusObject holds the status of a finished child process, which is returned by `Process.wait`.
+//
+// ```ruby
+// status = Process.wait(Process.spawn("exit 3"))
+// status.exit_status # => 3
+// status.success?    # => false
+// ```
+//
+// - `Process::Status.new` is not supported.
+type ProcessStatusObject struct {
+	*BaseObj
+	state *os.ProcessState
+}
+
+// Class methods --------------------------------------------------------
+var builtinProcessClassMethods = []*BuiltinMethodObject{
+	{
+		// Runs the command with the options like `spawn` and waits for it. Returns the standard output,
+		// the standard error and the `Process::Status` of the command.
+		// The `stdout` and `stderr` options are ignored.
+		//
+		// ```ruby
+		// out, err, status = Process.capture("tr a-z A-Z", { stdin: "goby" })
+		// out             # => "GOBY"
+		// status.success? # => true
+		// ```
+		//
+		// @param command [String], options [Hash]
+		// @return [Array]
+		Name: "capture",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			cmd, err := t.processCommand(args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			status, err := t.runProcess(cmd, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitArrayObject([]Object{
+				t.vm.InitStringObject(stdout.String()),
+				t.vm.InitStringObject(stderr.String()),
+				status,
+			})
+
+		},
+	},
+	{
+		// Sends the signal to the process. The signal is a name like "TERM" or "SIGTERM", or a number.
+		//
+		// ```ruby
+		// pid = Process.spawn("sleep 10")
+		// Process.kill("TERM", pid)
+		// Process.wait(pid).signaled? # => true
+		// ```
+		//
+		// @param signal [String], pid [Integer]
+		// @return [Integer]
+		Name: "kill",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 2, len(args))
+			}
+
+			sig, err := signalOf(t, args[0], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			pid, ok := args[1].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.IntegerClass, args[1].Class().Name)
+			}
+
+			pidValue, err := pid.intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			p, e := os.FindProcess(pidValue)
+
+			if e == nil {
+				e = p.Signal(sig)
+			}
+
+			if e != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+			}
+
+			return t.vm.InitIntegerObject(1)
+
+		},
+	},
+	{
+		// Returns the `Process::Status` of the last command waited in the current thread, or nil.
+		//
+		// ```ruby
+		// `git diff --quiet`
+		// Process.last_status.success? # => false if there are changes
+		// ```
+		//
+		// @return [Process::Status]
+		Name: "last_status",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if t.lastStatus == nil {
+				return NULL
+			}
+
+			return t.lastStatus
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Returns the process ID of the current process.
+		//
+		// ```ruby
+		// Process.pid # => 12345
+		// ```
+		//
+		// @return [Integer]
+		Name: "pid",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitIntegerObject(os.Getpid())
+
+		},
+	},
+	{
+		// Starts the command without waiting for it, and returns its process ID.
+		// The process should be waited with `Process.wait`.
+		//
+		// ```ruby
+		// log = File.new("/tmp/build.log", "w")
+		// pid = Process.spawn("make", "build", { dir: "/src/app", stdout: log, stderr: log })
+		// Process.wait(pid)
+		// ```
+		//
+		// @param command [String], options [Hash]
+		// @return [Integer]
+		Name: "spawn",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			cmd, err := t.processCommand(args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			e := cmd.Start()
+
+			if e != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+			}
+
+			t.vm.childrenMutex.Lock()
+
+			if t.vm.children == nil {
+				t.vm.children = map[int]*exec.Cmd{}
+			}
+
+			t.vm.children[cmd.Process.Pid] = cmd
+			t.vm.childrenMutex.Unlock()
+
+			return t.vm.InitIntegerObject(cmd.Process.Pid)
+
+		},
+	},
+	{
+		// Waits for the spawned process to finish, and returns its `Process::Status`.
+		//
+		// ```ruby
+		// pid = Process.spawn("exit 1")
+		// Process.wait(pid).exit_status # => 1
+		// ```
+		//
+		// @param pid [Integer]
+		// @return [Process::Status]
+		Name: "wait",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			pid, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+			}
+
+			pidValue, err := pid.intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			// Only one of the threads waiting for the same process gets it, the others raise an error
+			t.vm.childrenMutex.Lock()
+			cmd, ok := t.vm.children[pidValue]
+			delete(t.vm.children, pidValue)
+			t.vm.childrenMutex.Unlock()
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NoChildProcess, pidValue)
+			}
+
+			return t.waitProcess(cmd, sourceLine)
+
+		},
+	},
+}
+
+var builtinProcessStatusClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinProcessStatusInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Returns the exit status of the process, or nil if it was terminated by a signal.
+		//
+		// @return [Integer]
+		Name: "exit_status",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s := receiver.(*ProcessStatusObject)
+
+			if s.signaled() {
+				return NULL
+			}
+
+			return t.vm.InitIntegerObject(s.state.ExitCode())
+
+		},
+	},
+	{
+		// Returns the process ID.
+		//
+		// @return [Integer]
+		Name: "pid",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitIntegerObject(receiver.(*ProcessStatusObject).state.Pid())
+
+		},
+	},
+	{
+		// Returns true if the process was terminated by a signal.
+		//
+		// @return [Boolean]
+		Name: "signaled?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return toBooleanObject(receiver.(*ProcessStatusObject).signaled())
+
+		},
+	},
+	{
+		// Returns true if the process exited with status 0.
+		//
+		// @return [Boolean]
+		Name: "success?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return toBooleanObject(receiver.(*ProcessStatusObject).state.Success())
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initProcessModule() *RClass {
+	m := vm.initializeModule(classes.ProcessModule)
+	m.setBuiltinMethods(builtinProcessClassMethods, true)
+
+	sc := vm.initializeClass(classes.ProcessStatusClass)
+	sc.setBuiltinMethods(builtinProcessStatusClassMethods, true)
+	sc.setBuiltinMethods(builtinProcessStatusInstanceMethods, false)
+	m.setClassConstant(sc)
+
+	return m
+}
+
+func (vm *VM) initProcessStatusObject(state *os.ProcessState) *ProcessStatusObject {
+	return &ProcessStatusObject{
+		BaseObj: NewBaseObject(vm.TopLevelClass(classes.ProcessModule).getClassConstant(classes.ProcessStatusClass)),
+		state:   state,
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the process ID and how the process finished
+func (s *ProcessStatusObject) ToString() string {
+	return fmt.Sprintf("pid %d %s", s.state.Pid(), s.state.String())
+}
+
+// Inspect returns the status's description
+func (s *ProcessStatusObject) Inspect() string {
+	return "#<Process::Status: " + s.ToString() + ">"
+}
+
+// ToJSON returns the quoted ToString
+func (s *ProcessStatusObject) ToJSON(t *Thread) string {
+	return fmt.Sprintf("%q", s.ToString())
+}
+
+// Value returns the status's os.ProcessState
+func (s *ProcessStatusObject) Value() interface{} {
+	return s.state
+}
+
+// Other helper functions -----------------------------------------------
+
+func (s *ProcessStatusObject) signaled() bool {
+	ws, ok := s.state.Sys().(syscall.WaitStatus)
+	return ok && ws.Signaled()
+}
+
+// processCommand builds a command from the arguments and the trailing options Hash.
+// The command's standard input and outputs default to the current ones.
+func (t *Thread) processCommand(args []Object, sourceLine int) (*exec.Cmd, *Error) {
+	var options *HashObject
+
+	if len(args) > 0 {
+		if h, ok := args[len(args)-1].(*HashObject); ok {
+			options = h
+			args = args[:len(args)-1]
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentMore, 1, 0)
+	}
+
+	command := []string{}
+
+	for i, arg := range args {
+		s, ok := arg.(*StringObject)
+
+		if !ok {
+			return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.StringClass, arg.Class().Name)
+		}
+
+		command = append(command, s.value)
+	}
+
+	var cmd *exec.Cmd
+
+	if len(command) == 1 {
+		cmd = exec.Command("/bin/sh", "-c", command[0])
+	} else {
+		cmd = exec.Command(command[0], command[1:]...)
+	}
+
+	cmd.Stdin = os.Stdin
+	stdout, e := t.outputWriter("$stdout")
+
+	if e == nil {
+		cmd.Stdout = stdout
+		cmd.Stderr, e = t.outputWriter("$stderr")
+	}
+
+	if e != nil {
+		return nil, t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+	}
+
+	if options == nil {
+		return cmd, nil
+	}
+
+	for _, key := range options.sortedKeys() {
+		value := options.Pairs[key]
+
+		switch key {
+		case "env":
+			env, ok := value.(*HashObject)
+
+			if !ok {
+				return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, value.Class().Name)
+			}
+
+			cmd.Env = os.Environ()
+
+			for _, name := range env.sortedKeys() {
+				cmd.Env = append(cmd.Env, name+"="+env.Pairs[name].ToString())
+			}
+		case "dir":
+			dir, ok := value.(*StringObject)
+
+			if !ok {
+				return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, value.Class().Name)
+			}
+
+			cmd.Dir = dir.value
+		case "stdin":
+			switch in := value.(type) {
+			case *StringObject:
+				cmd.Stdin = strings.NewReader(in.value)
+			case *FileObject:
+				cmd.Stdin = in.bufReader()
+			default:
+				return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "File or String", value.Class().Name)
+			}
+		case "stdout", "stderr":
+			f, ok := value.(*FileObject)
+
+			if !ok {
+				return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.FileClass, value.Class().Name)
+			}
+
+			w, e := f.processOutput()
+
+			if e != nil {
+				return nil, t.vm.InitErrorObject(errors.IOError, sourceLine, e.Error())
+			}
+
+			if key == "stdout" {
+				cmd.Stdout = w
+			} else {
+				cmd.Stderr = w
+			}
+		default:
+			return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+		}
+	}
+
+	return cmd, nil
+}
+
+// runProcess starts the command and waits for it
+func (t *Thread) runProcess(cmd *exec.Cmd, sourceLine int) (*ProcessStatusObject, *Error) {
+	err := cmd.Start()
+
+	if err != nil {
+		return nil, t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	status, ok := t.waitProcess(cmd, sourceLine).(*ProcessStatusObject)
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.IOError, sourceLine, "Can't wait for process %d", cmd.Process.Pid)
+	}
+
+	return status, nil
+}
+
+// waitProcess waits for the started command, and returns its status which is also kept as the thread's last status
+func (t *Thread) waitProcess(cmd *exec.Cmd, sourceLine int) Object {
+	err := cmd.Wait()
+
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	// Output that is written to objects other than files is written after the process finishes
+	for _, w := range []io.Writer{cmd.Stdout, cmd.Stderr} {
+		if out, ok := w.(*bufferedOutput); ok {
+			if err := t.writeOutput(out.globalName, out.String(), sourceLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	t.lastStatus = t.vm.initProcessStatusObject(cmd.ProcessState)
+	return t.lastStatus
+}
+
+// outputWriter returns a writer for a child process's output to `$stdout` or `$stderr`
+func (t *Thread) outputWriter(globalName string) (io.Writer, error) {
+	if f, ok := t.vm.getGlobal(globalName).(*FileObject); ok {
+		return f.processOutput()
+	}
+
+	return &bufferedOutput{globalName: globalName}, nil
+}
+
+// bufferedOutput keeps a child process's output to a Goby object, which is written after the process finishes
+type bufferedOutput struct {
+	bytes.Buffer
+	globalName string
+}
@@ -0,0 +1,156 @@
+package vm
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/goby-lang/goby/compiler/parser"
+)
+
+func TestProcessMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Process.pid`, os.Getpid()},
+		{"`echo foo`", "foo\n"},
+		{"`printf '%s' \"a b\"`", "a b"},
+		{"`exit 3`\nProcess.last_status.exit_status", 3},
+		{`system("true")`, true},
+		{`system("false")`, false},
+		{`system("goby_not_existing_command", "--help")`, nil},
+		{`
+		system("test", "-d", "/tmp")
+		Process.last_status.success?
+		`, true},
+		{`
+		out, err, status = Process.capture("tr a-z A-Z; echo bar >&2", { stdin: "foo" })
+		[out, err, status.success?]
+		`, []interface{}{"FOO", "bar\n", true}},
+		{`
+		out, err, status = Process.capture("printf '%s' $GOBY_PROCESS_TEST", { env: { GOBY_PROCESS_TEST: "foo" } })
+		out
+		`, "foo"},
+		{`
+		out, err, status = Process.capture("pwd", { dir: "/" })
+		out
+		`, "/\n"},
+		{`
+		out, err, status = Process.capture("echo", "-n", "a", "b")
+		out
+		`, "a b"},
+		{`
+		pid = Process.spawn("exit 2")
+		status = Process.wait(pid)
+		[status.pid == pid, status.exit_status, status.success?, status.signaled?]
+		`, []interface{}{true, 2, false, false}},
+		{`
+		pid = Process.spawn("sleep", "10")
+		Process.kill("TERM", pid)
+		status = Process.wait(pid)
+		[status.signaled?, status.exit_status]
+		`, []interface{}{true, nil}},
+		{`
+		pid = Process.spawn("sleep", "10")
+		Process.kill(9, pid)
+		Process.wait(pid).to_s.include?("killed")
+		`, true},
+		{`
+		File.open("/tmp/goby/process.txt", "w") do |f|
+		  Process.wait(Process.spawn("echo foo", { stdout: f }))
+		end
+		File.new("/tmp/goby/process.txt").read
+		`, "foo\n"},
+	}
+
+	setup()
+	defer teardown()
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestProcessMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Process.new`, "NoMethodError: Undefined Method 'new' for Process", 1},
+		{`Process::Status.new`, "NoMethodError: Undefined Method 'new' for Status", 1},
+		{`Process.spawn`, "ArgumentError: Expect 1 or more argument(s). got: 0", 1},
+		{`Process.spawn("ls", 1)`, "TypeError: Expect argument #2 to be String. got: Integer", 1},
+		{`Process.spawn("ls", { foo: 1 })`, "ArgumentError: Unknown option - foo", 1},
+		{`Process.spawn("ls", { env: 1 })`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`Process.spawn("ls", { stdout: "out.txt" })`, "TypeError: Expect argument to be File. got: String", 1},
+		{`Process.spawn("goby_not_existing_command", "--help")`, `IOError: exec: "goby_not_existing_command": executable file not found in $PATH`, 1},
+		{`Process.wait(0)`, "ArgumentError: No child process - 0", 1},
+		{`Process.kill("FOO", 0)`, "ArgumentError: Unknown signal - FOO", 1},
+		{`Process.kill("TERM")`, "ArgumentError: Expect 2 argument(s). got: 1", 1},
+		{"send(\"`\", \"ls\", \"-l\")", "ArgumentError: Expect 1 argument(s). got: 2", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestProcessOutput(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{`system("echo foo; echo bar >&2")`, "foo\n", "bar\n"},
+		{"`echo foo; echo bar >&2`", "", "bar\n"},
+		{`Process.wait(Process.spawn("echo", "foo"))`, "foo\n", ""},
+		{`
+		class Recorder
+		  attr_reader("output")
+
+		  def initialize
+		    @output = ""
+		  end
+
+		  def write(str)
+		    @output = @output + str
+		  end
+		end
+
+		r = Recorder.new
+		$stdout = r
+		system("echo foo")
+		$stdout = STDOUT
+		print(r.output.upcase)
+		`, "FOO\n", ""},
+	}
+
+	for i, tt := range tests {
+		var out, errOut bytes.Buffer
+		dir, _ := os.Getwd()
+		v, err := New(dir, []string{}, WithOutput(&out), WithErrorOutput(&errOut))
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		v.mode = parser.TestMode
+		v.testEval(t, tt.input, getFilename())
+
+		if out.String() != tt.expectedOutput {
+			t.Errorf("At case %d expect output to be %q. got: %q", i, tt.expectedOutput, out.String())
+		}
+
+		if errOut.String() != tt.expectedError {
+			t.Errorf("At case %d expect error output to be %q. got: %q", i, tt.expectedError, errOut.String())
+		}
+
+		v.checkCFP(t, i, 0)
+	}
+}
@@ -0,0 +1,399 @@
+package vm
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// RandomObject generates pseudo-random numbers from a seed, so the same seed always generates the same sequence.
+// Unlike `SecureRandom`, it's not suitable for security purposes.
+//
+// ```ruby
+// r = Random.new(42)
+// r.rand         # => 0.3730283610466326
+// r.rand(10)     # => 7
+// r.rand(1..6)   # => 3
+//
+// [1, 2, 3, 4].shuffle({ random: Random.new(42) }) # => always in the same order
+// ```
+//
+// `Object#rand`, `Array#shuffle` and `Array#sample` use the default generator, which is seeded with `goby -seed`
+// for reproducible runs, or randomly otherwise:
+//
+// ```bash
+// $ goby -seed 1234 simulation.gb
+// ```
+type RandomObject struct {
+	*BaseObj
+	seed  int64
+	rng   *rand.Rand
+	mutex sync.Mutex
+}
+
+// Class methods --------------------------------------------------------
+var builtinRandomClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns random bytes of the length from the default generator as a binary String.
+		//
+		// @param length [Integer]
+		// @return [String]
+		Name: "bytes",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.random.bytes(t, args, sourceLine)
+
+		},
+	},
+	{
+		// Returns a new generator with the seed, or with a random seed if it's not given.
+		//
+		// ```ruby
+		// Random.new(42).rand(100) == Random.new(42).rand(100) # => true
+		// ```
+		//
+		// @param seed [Integer]
+		// @return [Random]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			seed, err := randomSeedOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.initRandomObject(seed)
+
+		},
+	},
+	{
+		// Returns a random seed.
+		//
+		// @return [Integer]
+		Name: "new_seed",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(int(newRandomSeed()))
+
+		},
+	},
+	{
+		// Returns a random number from the default generator, just like `Random#rand`.
+		//
+		// @param max [Integer, Float, Range]
+		// @return [Integer, Float]
+		Name: "rand",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.random.rand(t, args, sourceLine)
+
+		},
+	},
+	{
+		// Returns the seed of the default generator, which can be given to `goby -seed` to reproduce the run.
+		//
+		// ```ruby
+		// puts("Random seed: #{Random.seed}")
+		// ```
+		//
+		// @return [Integer]
+		Name: "seed",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(int(t.vm.random.getSeed()))
+
+		},
+	},
+	{
+		// Reseeds the default generator with the seed, or with a random seed if it's not given.
+		// Returns the previous seed.
+		//
+		// ```ruby
+		// Random.srand(1234)
+		// ```
+		//
+		// @param seed [Integer]
+		// @return [Integer]
+		Name: "srand",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			seed, err := randomSeedOf(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitIntegerObject(int(t.vm.random.reseed(seed)))
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinRandomInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Returns random bytes of the length as a binary String.
+		//
+		// ```ruby
+		// Random.new(42).bytes(8)
+		// ```
+		//
+		// @param length [Integer]
+		// @return [String]
+		Name: "bytes",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*RandomObject).bytes(t, args, sourceLine)
+
+		},
+	},
+	{
+		// Returns a random number. Without the argument, it's a Float between 0.0 and 1.0.
+		// With an Integer or a Float, it's between 0 and the number, excluding the number itself.
+		// With a Range, it's an Integer in the Range, including both ends.
+		//
+		// ```ruby
+		// r = Random.new(42)
+		// r.rand       # => 0.3730283610466326
+		// r.rand(10)   # => 7
+		// r.rand(2.5)  # => 1.510234628896605
+		// r.rand(1..6) # => 1
+		// ```
+		//
+		// @param max [Integer, Float, Range]
+		// @return [Integer, Float]
+		Name: "rand",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return receiver.(*RandomObject).rand(t, args, sourceLine)
+
+		},
+	},
+	{
+		// Returns the seed of the generator.
+		//
+		// ```ruby
+		// Random.new(42).seed # => 42
+		// ```
+		//
+		// @return [Integer]
+		Name: "seed",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(int(receiver.(*RandomObject).getSeed()))
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initRandomClass() *RClass {
+	rc := vm.initializeClass(classes.RandomClass)
+	rc.setBuiltinMethods(builtinRandomClassMethods, true)
+	rc.setBuiltinMethods(builtinRandomInstanceMethods, false)
+	vm.random = &RandomObject{BaseObj: NewBaseObject(rc), seed: vm.randomSeed, rng: rand.New(rand.NewSource(vm.randomSeed))}
+	return rc
+}
+
+func (vm *VM) initRandomObject(seed int64) *RandomObject {
+	return &RandomObject{
+		BaseObj: NewBaseObject(vm.TopLevelClass(classes.RandomClass)),
+		seed:    seed,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the object's name as the string format
+func (r *RandomObject) ToString() string {
+	return "#<Random>"
+}
+
+// Inspect delegates to ToString
+func (r *RandomObject) Inspect() string {
+	return r.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (r *RandomObject) ToJSON(t *Thread) string {
+	return jsonString(r.ToString())
+}
+
+// Value returns the generator
+func (r *RandomObject) Value() interface{} {
+	return r.rng
+}
+
+// Other helper functions -----------------------------------------------
+
+// rand returns a random number less than the Integer or Float argument, or in the Range argument
+func (r *RandomObject) rand(t *Thread, args []Object, sourceLine int) Object {
+	if len(args) > 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(args) == 0 {
+		return t.vm.initFloatObject(r.rng.Float64())
+	}
+
+	switch max := args[0].(type) {
+	case *IntegerObject:
+		if max.bigValue != nil || max.value <= 0 {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidArgument, max.Inspect())
+		}
+
+		return t.vm.InitIntegerObject(r.rng.Intn(max.value))
+	case *FloatObject:
+		if max.value <= 0 {
+			return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidArgument, max.Inspect())
+		}
+
+		return t.vm.initFloatObject(r.rng.Float64() * max.value)
+	case *RangeObject:
+		min, limit := max.Start, max.End
+
+		if min > limit {
+			min, limit = limit, min
+		}
+
+		return t.vm.InitIntegerObject(min + r.rng.Intn(limit-min+1))
+	default:
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Integer, Float or Range", args[0].Class().Name)
+	}
+}
+
+// bytes returns random bytes of the length given as the argument
+func (r *RandomObject) bytes(t *Thread, args []Object, sourceLine int) Object {
+	if len(args) != 1 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+	}
+
+	n, ok := args[0].(*IntegerObject)
+
+	if !ok {
+		return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	length, err := n.intValue(t, sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	if length < 0 {
+		return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, length)
+	}
+
+	b := make([]byte, length)
+	r.mutex.Lock()
+	r.rng.Read(b)
+	r.mutex.Unlock()
+
+	return t.vm.initBinaryStringObject(string(b))
+}
+
+// intn returns a random int in [0, n)
+func (r *RandomObject) intn(n int) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rng.Intn(n)
+}
+
+// shuffle shuffles the elements in place
+func (r *RandomObject) shuffle(elems []Object) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.rng.Shuffle(len(elems), func(i, j int) {
+		elems[i], elems[j] = elems[j], elems[i]
+	})
+}
+
+func (r *RandomObject) getSeed() int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.seed
+}
+
+// reseed resets the generator with the seed and returns the previous seed
+func (r *RandomObject) reseed(seed int64) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previous := r.seed
+	r.seed = seed
+	r.rng = rand.New(rand.NewSource(seed))
+	return previous
+}
+
+// randomOf returns the generator given as the `random` option, or the default generator
+func randomOf(t *Thread, options Object, sourceLine int) (*RandomObject, *Error) {
+	h, ok := options.(*HashObject)
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.HashClass, options.Class().Name)
+	}
+
+	r := t.vm.random
+
+	for _, key := range h.sortedKeys() {
+		if key != "random" {
+			return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+		}
+
+		if r, ok = h.Pairs[key].(*RandomObject); !ok {
+			return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.RandomClass, h.Pairs[key].Class().Name)
+		}
+	}
+
+	return r, nil
+}
+
+// randomSeedOf returns the Integer seed given as the optional argument, or a random seed
+func randomSeedOf(t *Thread, args []Object, sourceLine int) (int64, *Error) {
+	if len(args) > 1 {
+		return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+	}
+
+	if len(args) == 0 {
+		return newRandomSeed(), nil
+	}
+
+	seed, ok := args[0].(*IntegerObject)
+
+	if !ok {
+		return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	if seed.bigValue != nil {
+		return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidArgument, seed.Inspect())
+	}
+
+	return int64(seed.value), nil
+}
+
+// newRandomSeed returns a seed read from the secure random source
+func newRandomSeed() int64 {
+	var b [8]byte
+	crand.Read(b[:])
+
+	// Keeps the seed positive so that it's easy to pass to `goby -seed`
+	return int64(binary.LittleEndian.Uint64(b[:]) >> 1)
+}
@@ -0,0 +1,122 @@
+package vm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/goby-lang/goby/compiler/parser"
+)
+
+func TestRandomMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Random.new(42).seed`, 42},
+		{`Random.new(42).rand`, 0.3730283610466326},
+		{`
+		r = Random.new(42)
+		[r.rand, r.rand(10), r.rand(2.5), r.rand(1..6)]
+		`, []interface{}{0.3730283610466326, 7, 1.510234628896605, 1}},
+		{`
+		a = Random.new(7)
+		b = Random.new(7)
+		[a.rand(1000), a.rand(1000), a.bytes(8)] == [b.rand(1000), b.rand(1000), b.bytes(8)]
+		`, true},
+		{`
+		r = Random.new
+		n = r.rand(3..5)
+		n >= 3 && n <= 5
+		`, true},
+		{`
+		r = Random.new
+		n = r.rand(5..3)
+		n >= 3 && n <= 5
+		`, true},
+		{`Random.new.rand(1)`, 0},
+		{`Random.new.bytes(0)`, ""},
+		{`Random.new.inspect`, "#<Random>"},
+		{`
+		Random.srand(42)
+		Random.rand
+		`, 0.3730283610466326},
+		{`
+		Random.srand(42)
+		Random.srand(43)
+		`, 42},
+		{`
+		Random.srand(42)
+		[Random.seed, rand]
+		`, []interface{}{42, 0.3730283610466326}},
+		{`
+		Random.srand(1)
+		a = [rand(100), rand(1..6), Random.bytes(4)]
+		Random.srand(1)
+		a == [rand(100), rand(1..6), Random.bytes(4)]
+		`, true},
+		{`Random.new_seed >= 0`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRandomMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Random.new("a")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Random.new(1, 2)`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
+		{`Random.new(1).rand(0)`, "ArgumentError: Invalid argument - 0", 1},
+		{`Random.new(1).rand(-1.5)`, "ArgumentError: Invalid argument - -1.5", 1},
+		{`Random.new(1).rand("a")`, "TypeError: Expect argument to be Integer, Float or Range. got: String", 1},
+		{`Random.new(1).rand(1, 2)`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
+		{`Random.new(1).bytes`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`Random.new(1).bytes(-1)`, "ArgumentError: Expect argument to be positive value. got: -1", 1},
+		{`Random.seed(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`Random.srand(1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRandomWithSeed(t *testing.T) {
+	input := `
+	[Random.seed, rand, rand(100), rand(1..6), [1, 2, 3, 4, 5].shuffle, [1, 2, 3, 4, 5].sample(2)].to_s
+	`
+	dir, _ := os.Getwd()
+	var results []string
+
+	for i := 0; i < 2; i++ {
+		v, err := New(dir, []string{}, WithSeed(1234))
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		v.mode = parser.TestMode
+		results = append(results, v.testEval(t, input, getFilename()).(*StringObject).value)
+	}
+
+	if results[0] != results[1] {
+		t.Errorf("Expect the runs with the same seed to be the same. got: %s and %s", results[0], results[1])
+	}
+
+	v := initTestVM()
+	evaluated := v.testEval(t, `Random.srand(1234)
+	[Random.seed, rand, rand(100), rand(1..6), [1, 2, 3, 4, 5].shuffle, [1, 2, 3, 4, 5].sample(2)].to_s
+	`, getFilename())
+
+	if evaluated.(*StringObject).value != results[0] {
+		t.Errorf("Expect Random.srand to seed like WithSeed. got: %s and %s", evaluated.(*StringObject).value, results[0])
+	}
+}
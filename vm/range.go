@@ -246,7 +246,12 @@ var builtinRangeInstanceMethods = []*BuiltinMethodObject{
 
 			ro := receiver.(*RangeObject)
 
-			value := args[0].(*IntegerObject).value
+			value, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
 			ascendRangeBool := ro.Start <= ro.End && value >= ro.Start && value <= ro.End
 			descendRangeBool := ro.End <= ro.Start && value <= ro.Start && value >= ro.End
 
@@ -378,7 +383,12 @@ var builtinRangeInstanceMethods = []*BuiltinMethodObject{
 			}
 
 			ro := receiver.(*RangeObject)
-			step := args[0].(*IntegerObject).value
+			step, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
 			if step <= 0 {
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, step)
 			}
@@ -489,9 +499,9 @@ func (ro *RangeObject) Inspect() string {
 	return ro.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (ro *RangeObject) ToJSON(t *Thread) string {
-	return ro.ToString()
+	return jsonString(ro.ToString())
 }
 
 // Value returns range object's string format
@@ -140,9 +140,9 @@ func (r *RegexpObject) Inspect() string {
 	return r.ToString()
 }
 
-// ToJSON just delegates to ToString
+// ToJSON returns the quoted ToString
 func (r *RegexpObject) ToJSON(t *Thread) string {
-	return "\"" + r.ToString() + "\""
+	return jsonString(r.ToString())
 }
 
 // equal checks if the string values between receiver and argument are equal
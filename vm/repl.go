@@ -6,13 +6,9 @@ import (
 )
 
 // InitForREPL does following things:
-// - Initialize instruction sets' index tables
 // - Set vm to REPL mode
 // - Create and push main object frame
 func (vm *VM) InitForREPL() {
-	vm.SetClassISIndexTable("REPL")
-	vm.SetMethodISIndexTable("REPL")
-
 	// REPL should maintain a base call frame so that the whole program won't exit
 	cf := newNormalCallFrame(&instructionSet{name: "REPL base"}, "REPL", 1)
 	cf.self = vm.mainObj
@@ -26,14 +22,6 @@ func (vm *VM) REPLExec(sets []*bytecode.InstructionSet) {
 	p.vm = vm
 	p.transferInstructionSets(sets)
 
-	for setType, table := range p.setTable {
-		for name, is := range table {
-			vm.isTables[setType][name] = is
-		}
-	}
-
-	vm.blockTables[p.filename] = p.blockTable
-
 	oldFrame := vm.mainThread.callFrameStack.pop()
 	cf := newNormalCallFrame(p.program, p.filename, oldFrame.SourceLine())
 	cf.self = vm.mainObj
@@ -0,0 +1,236 @@
+package vm
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// SecureRandom generates random values for tokens, keys and salts with Go's `crypto/rand` package,
+// which reads from the operating system's secure random source unlike `Object#rand`.
+//
+// ```ruby
+// require "securerandom"
+//
+// SecureRandom.hex            # => "4c8c0e8f0e0f8b6ae5b0b4d9d5f54a7e"
+// SecureRandom.uuid           # => "2d931510-d99f-494a-8c67-87feb05e1594"
+// SecureRandom.bytes(32)      # => 32 random bytes as a binary String
+// SecureRandom.random_number(6) # => 4
+// ```
+//
+// The length of the random bytes is 16 by default.
+//
+// - `SecureRandom.new` is not supported.
+
+// secureRandomLength is the default number of random bytes
+const secureRandomLength = 16
+
+// Class methods --------------------------------------------------------
+var builtinSecureRandomClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the base64 encoded random bytes of the length.
+		//
+		// ```ruby
+		// SecureRandom.base64 # => "rmK1nmOUvAZ86sFpEbSHhA=="
+		// ```
+		//
+		// @param length [Integer]
+		// @return [String]
+		Name: "base64",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			b, err := secureRandomBytes(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(base64.StdEncoding.EncodeToString(b))
+
+		},
+	},
+	{
+		// Returns the random bytes of the length as a binary String.
+		//
+		// ```ruby
+		// key = SecureRandom.bytes(32)
+		// ```
+		//
+		// @param length [Integer]
+		// @return [String]
+		Name: "bytes",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			b, err := secureRandomBytes(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.initBinaryStringObject(string(b))
+
+		},
+	},
+	{
+		// Returns the hex encoded random bytes of the length, so the String is twice as long as the length.
+		//
+		// ```ruby
+		// SecureRandom.hex(4) # => "e2a0f1b7"
+		// ```
+		//
+		// @param length [Integer]
+		// @return [String]
+		Name: "hex",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			b, err := secureRandomBytes(t, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(hex.EncodeToString(b))
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Returns a random Integer between 0 and the given Integer, excluding the Integer itself.
+		// Without the argument, returns a random Float between 0.0 and 1.0.
+		//
+		// ```ruby
+		// SecureRandom.random_number(100) # => 42
+		// SecureRandom.random_number      # => 0.5967359517934695
+		// ```
+		//
+		// @param max [Integer]
+		// @return [Integer, Float]
+		Name: "random_number",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			if len(args) == 0 {
+				// A Float has 53 bits of precision
+				n, err := rand.Int(rand.Reader, big.NewInt(1<<53))
+
+				if err != nil {
+					return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+				}
+
+				return t.vm.initFloatObject(float64(n.Int64()) / (1 << 53))
+			}
+
+			max, ok := args[0].(*IntegerObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+			}
+
+			bigMax := max.bigValue
+
+			if bigMax == nil {
+				bigMax = big.NewInt(int64(max.value))
+			}
+
+			if bigMax.Sign() <= 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Expect argument to be positive value. got: %s", max.ToString())
+			}
+
+			n, err := rand.Int(rand.Reader, bigMax)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			}
+
+			return t.vm.initIntegerObjectFromBigInt(n)
+
+		},
+	},
+	{
+		// Returns a random version 4 UUID.
+		//
+		// ```ruby
+		// SecureRandom.uuid # => "2d931510-d99f-494a-8c67-87feb05e1594"
+		// ```
+		//
+		// @return [String]
+		Name: "uuid",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			b := make([]byte, 16)
+
+			if _, err := rand.Read(b); err != nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			}
+
+			// Sets the version to 4 and the variant to RFC 4122
+			b[6] = b[6]&0x0f | 0x40
+			b[8] = b[8]&0x3f | 0x80
+
+			return t.vm.InitStringObject(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]))
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initSecureRandomClass(vm *VM) {
+	class := vm.initializeClass("SecureRandom")
+	class.setBuiltinMethods(builtinSecureRandomClassMethods, true)
+	vm.objectClass.setClassConstant(class)
+}
+
+// Other helper functions -----------------------------------------------
+
+// secureRandomBytes returns the random bytes of the length given as the optional argument
+func secureRandomBytes(t *Thread, args []Object, sourceLine int) ([]byte, *Error) {
+	if len(args) > 1 {
+		return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+	}
+
+	length := secureRandomLength
+
+	if len(args) == 1 {
+		n, ok := args[0].(*IntegerObject)
+
+		if !ok {
+			return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		value, err := n.intValue(t, sourceLine)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if value < 0 {
+			return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, value)
+		}
+
+		length = value
+	}
+
+	b := make([]byte, length)
+
+	if _, err := rand.Read(b); err != nil {
+		return nil, t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+	}
+
+	return b, nil
+}
@@ -0,0 +1,73 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestSecureRandomMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`require "securerandom";SecureRandom.hex.length`, 32},
+		{`require "securerandom";SecureRandom.hex(4).length`, 8},
+		{`require "securerandom";SecureRandom.hex(0)`, ""},
+		{`require "securerandom";SecureRandom.hex == SecureRandom.hex`, false},
+		{`
+		require "hex"
+		require "securerandom"
+		Hex.encode(SecureRandom.bytes(32)).length
+		`, 64},
+		{`require "securerandom";SecureRandom.base64.length`, 24},
+		{`
+		require "securerandom"
+		uuid = SecureRandom.uuid
+		[uuid.length, uuid[14], uuid.split("-").map do |s| s.length end]
+		`, []interface{}{36, "4", []interface{}{8, 4, 4, 4, 12}}},
+		{`
+		require "securerandom"
+		n = SecureRandom.random_number(6)
+		n >= 0 && n < 6
+		`, true},
+		{`require "securerandom";SecureRandom.random_number(1)`, 0},
+		{`
+		require "securerandom"
+		f = SecureRandom.random_number
+		f.class.name == "Float" && f >= 0.0 && f < 1.0
+		`, true},
+		{`
+		require "crypto"
+		SecureRandom.hex(2).length
+		`, 4},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSecureRandomMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "securerandom";SecureRandom.new`, "NoMethodError: Undefined Method 'new' for SecureRandom", 1},
+		{`require "securerandom";SecureRandom.hex(1, 2)`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
+		{`require "securerandom";SecureRandom.hex("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`require "securerandom";SecureRandom.bytes(-1)`, "ArgumentError: Expect argument to be positive value. got: -1", 1},
+		{`require "securerandom";SecureRandom.bytes(2 ** 70)`, "RangeError: Integer too big to convert into int. got: 1180591620717411303424", 1},
+		{`require "securerandom";SecureRandom.hex(0 - 2 ** 70)`, "RangeError: Integer too big to convert into int. got: -1180591620717411303424", 1},
+		{`require "securerandom";SecureRandom.uuid(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`require "securerandom";SecureRandom.random_number(0)`, "ArgumentError: Expect argument to be positive value. got: 0", 1},
+		{`require "securerandom";SecureRandom.random_number(1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
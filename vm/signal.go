@@ -0,0 +1,342 @@
+package vm
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Signal is a module for handling the signals sent to the program.
+// A trapped signal runs its block on a new thread, with the signal number as the block's argument.
+//
+// ```ruby
+// Signal.trap("TERM") do |signo|
+//   server.shutdown
+//   exit
+// end
+//
+// Signal.trap("HUP", "IGNORE")
+// ```
+//
+// A signal that isn't trapped terminates the program after the blocks registered by `at_exit` are run.
+//
+// - `Signal.new` is not supported.
+
+// signals are the names of the signals that can be trapped or sent by `Process.kill`, without the "SIG" prefix
+var signals = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"ILL":  syscall.SIGILL,
+	"TRAP": syscall.SIGTRAP,
+	"ABRT": syscall.SIGABRT,
+	"BUS":  syscall.SIGBUS,
+	"FPE":  syscall.SIGFPE,
+	"KILL": syscall.SIGKILL,
+	"SEGV": syscall.SIGSEGV,
+	"PIPE": syscall.SIGPIPE,
+	"ALRM": syscall.SIGALRM,
+	"TERM": syscall.SIGTERM,
+}
+
+// exitSignals are the signals that run the `at_exit` blocks before terminating the program
+var exitSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+
+// signalHandler dispatches the signals received by the vm
+type signalHandler struct {
+	mutex   sync.Mutex
+	channel chan os.Signal
+	traps   map[os.Signal]func()
+}
+
+// Class methods --------------------------------------------------------
+var builtinSignalClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns a Hash of the signal names and numbers. "EXIT" is the program's exit, which runs the `at_exit` blocks.
+		//
+		// ```ruby
+		// Signal.list["TERM"] # => 15
+		// ```
+		//
+		// @return [Hash]
+		Name: "list",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			pairs := map[string]Object{"EXIT": t.vm.InitIntegerObject(0)}
+
+			for name, sig := range signals {
+				pairs[name] = t.vm.InitIntegerObject(int(sig))
+			}
+
+			return t.vm.InitHashObject(pairs)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Runs the block on a new thread when the signal is received. The signal is a name like "TERM" or "SIGTERM",
+		// or a number. Instead of a block, a command can be given:
+		//
+		// - "DEFAULT": terminates the program after the `at_exit` blocks are run
+		// - "IGNORE": ignores the signal
+		// - "EXIT": exits the program with status 0, after the `at_exit` blocks are run
+		//
+		// Trapping "EXIT" is the same as `at_exit`.
+		//
+		// ```ruby
+		// Signal.trap("INT") do
+		//   puts("Interrupted")
+		// end
+		//
+		// Signal.trap("INT", "DEFAULT")
+		// ```
+		//
+		// @param signal [String], command [String]
+		// @return [Null]
+		Name: "trap",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			aLen := len(args)
+
+			if aLen < 1 || aLen > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, aLen)
+			}
+
+			if name, ok := args[0].(*StringObject); ok && strings.ToUpper(name.value) == "EXIT" {
+				if blockFrame == nil {
+					return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+				}
+
+				t.vm.atExit(blockFrame)
+				t.callFrameStack.pop()
+				return NULL
+			}
+
+			sig, err := signalOf(t, args[0], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			if sig == syscall.SIGKILL {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.CantTrapSignal, args[0].ToString())
+			}
+
+			if aLen == 1 {
+				if blockFrame == nil {
+					return t.vm.InitErrorObject(errors.InternalError, sourceLine, errors.CantYieldWithoutBlockFormat)
+				}
+
+				t.vm.trapSignal(sig, func() {
+					t.vm.runBlock(blockFrame, t.vm.InitIntegerObject(int(sig)))
+				})
+
+				// The block is yielded by another thread, so we need to pop this frame manually
+				t.callFrameStack.pop()
+				return NULL
+			}
+
+			if blockFrame != nil {
+				t.callFrameStack.pop()
+			}
+
+			command, ok := args[1].(*StringObject)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.StringClass, args[1].Class().Name)
+			}
+
+			switch command.value {
+			case "DEFAULT", "SYSTEM_DEFAULT":
+				t.vm.trapSignal(sig, nil)
+			case "IGNORE":
+				t.vm.ignoreSignal(sig)
+			case "EXIT":
+				t.vm.trapSignal(sig, func() {
+					t.vm.exit(0)
+				})
+			default:
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidSignalCommand, command.value)
+			}
+
+			return NULL
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initSignalModule() *RClass {
+	m := vm.initializeModule(classes.SignalModule)
+	m.setBuiltinMethods(builtinSignalClassMethods, true)
+
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// RunExitHooks runs the blocks registered by `at_exit` in the reverse order of registration.
+// It should be called when the program finishes; `exit` and the signals that terminate the program call it as well.
+func (vm *VM) RunExitHooks() {
+	for {
+		vm.exitMutex.Lock()
+		n := len(vm.exitHooks)
+
+		if n == 0 {
+			vm.exitMutex.Unlock()
+			return
+		}
+
+		hook := vm.exitHooks[n-1]
+		vm.exitHooks = vm.exitHooks[:n-1]
+		vm.exitMutex.Unlock()
+
+		hook()
+	}
+}
+
+// atExit registers the block to be run when the program exits, including when it's terminated by a signal
+func (vm *VM) atExit(blockFrame *normalCallFrame) {
+	vm.addExitHook(func() {
+		vm.runBlock(blockFrame)
+	})
+
+	vm.listenExitSignals()
+}
+
+func (vm *VM) addExitHook(hook func()) {
+	vm.exitMutex.Lock()
+	defer vm.exitMutex.Unlock()
+
+	vm.exitHooks = append(vm.exitHooks, hook)
+}
+
+// exit runs the exit hooks and exits the program with the given status code.
+// The program should always be exited through it, so that nothing is skipped.
+func (vm *VM) exit(code int) {
+	vm.RunExitHooks()
+	os.Exit(code)
+}
+
+// runBlock yields the block on a new thread. Errors raised in the block are reported to the standard error,
+// since there's no caller to handle them.
+func (vm *VM) runBlock(blockFrame *normalCallFrame, args ...Object) {
+	t := vm.newThread()
+
+	defer func() {
+		switch err := recover().(type) {
+		case nil:
+		case *Error:
+			fmt.Fprintln(vm.stderr, err.Message())
+		default:
+			panic(err)
+		}
+	}()
+
+	t.builtinMethodYield(blockFrame, args...)
+}
+
+// trapSignal sets the function called when the signal is received. A nil function restores the default behavior.
+func (vm *VM) trapSignal(sig os.Signal, fn func()) {
+	h := &vm.signalHandler
+	h.mutex.Lock()
+
+	if h.traps == nil {
+		h.traps = map[os.Signal]func(){}
+	}
+
+	if fn == nil {
+		delete(h.traps, sig)
+	} else {
+		h.traps[sig] = fn
+	}
+
+	h.mutex.Unlock()
+	vm.listenSignal(sig)
+}
+
+func (vm *VM) ignoreSignal(sig os.Signal) {
+	h := &vm.signalHandler
+	h.mutex.Lock()
+	delete(h.traps, sig)
+	h.mutex.Unlock()
+
+	signal.Ignore(sig)
+}
+
+// listenSignal makes the vm receive the signal, starting to dispatch the received signals at the first call
+func (vm *VM) listenSignal(sig os.Signal) {
+	h := &vm.signalHandler
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.channel == nil {
+		h.channel = make(chan os.Signal, 1)
+		go vm.dispatchSignals(h.channel)
+	}
+
+	signal.Notify(h.channel, sig)
+}
+
+// listenExitSignals makes the signals like INT and TERM run the exit hooks before terminating the program
+func (vm *VM) listenExitSignals() {
+	for _, sig := range exitSignals {
+		vm.listenSignal(sig)
+	}
+}
+
+// dispatchSignals calls the trapped signals' functions. Other signals exit the program through `exit`,
+// which runs the exit hooks, with the status code 128 + the signal number like a shell does.
+func (vm *VM) dispatchSignals(c chan os.Signal) {
+	for sig := range c {
+		vm.signalHandler.mutex.Lock()
+		fn, ok := vm.signalHandler.traps[sig]
+		vm.signalHandler.mutex.Unlock()
+
+		if ok {
+			go fn()
+			continue
+		}
+
+		// A second signal received while the exit hooks are running terminates the program at once
+		signal.Reset(sig)
+		vm.exit(128 + int(sig.(syscall.Signal)))
+	}
+}
+
+// signalOf returns the signal specified by a name like "TERM" or "SIGTERM", or a number
+func signalOf(t *Thread, obj Object, sourceLine int) (syscall.Signal, *Error) {
+	switch s := obj.(type) {
+	case *IntegerObject:
+		value, err := s.intValue(t, sourceLine)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return syscall.Signal(value), nil
+	case *StringObject:
+		sig, ok := signals[strings.TrimPrefix(strings.ToUpper(s.value), "SIG")]
+
+		if !ok {
+			return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownSignal, s.value)
+		}
+
+		return sig, nil
+	default:
+		return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, "String or Integer", obj.Class().Name)
+	}
+}
@@ -0,0 +1,151 @@
+package vm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/goby-lang/goby/compiler/parser"
+)
+
+func TestSignalMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Signal.list["TERM"]`, 15},
+		{`Signal.list["EXIT"]`, 0},
+		{`
+		c = Channel.new
+		Signal.trap("HUP") do |signo|
+		  c.deliver(signo)
+		end
+		Process.kill("SIGHUP", Process.pid)
+		c.receive
+		`, 1},
+		{`
+		c = Channel.new
+		Signal.trap(1) do
+		  c.deliver("trapped")
+		end
+		Signal.trap("HUP", "IGNORE")
+		Signal.trap("HUP") do
+		  c.deliver("trapped again")
+		end
+		Process.kill("HUP", Process.pid)
+		c.receive
+		`, "trapped again"},
+		{`Signal.trap("ALRM", "DEFAULT")`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSignalMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Signal.new`, "NoMethodError: Undefined Method 'new' for Signal", 1},
+		{`Signal.trap`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`Signal.trap("FOO", "IGNORE")`, "ArgumentError: Unknown signal - FOO", 1},
+		{`Signal.trap(1.5, "IGNORE")`, "TypeError: Expect argument #1 to be String or Integer. got: Float", 1},
+		{`Signal.trap("KILL", "IGNORE")`, "ArgumentError: Can't trap reserved signal - KILL", 1},
+		{`Signal.trap("HUP", "FOO")`, "ArgumentError: Invalid signal command - FOO", 1},
+		{`Signal.trap("HUP", 1)`, "TypeError: Expect argument #2 to be String. got: Integer", 1},
+		{`Signal.trap("HUP")`, "InternalError: Can't yield without a block", 1},
+		{`at_exit`, "InternalError: Can't yield without a block", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestAtExit(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{`
+		at_exit do
+		  puts("foo")
+		end
+
+		Signal.trap("EXIT") do
+		  puts("bar")
+		end
+
+		puts("main")
+		`, "main\nbar\nfoo\n", ""},
+		{`
+		at_exit do
+		  puts("foo")
+		end
+
+		at_exit do
+		  at_exit do
+		    puts("nested")
+		  end
+
+		  raise(ArgumentError, "bar")
+		end
+		`, "nested\nfoo\n", "ArgumentError: \"bar\"\n"},
+	}
+
+	for i, tt := range tests {
+		var out, errOut bytes.Buffer
+		dir, _ := os.Getwd()
+		v, err := New(dir, []string{}, WithOutput(&out), WithErrorOutput(&errOut))
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		v.mode = parser.TestMode
+		v.testEval(t, tt.input, getFilename())
+		v.checkCFP(t, i, 0)
+		v.RunExitHooks()
+
+		if out.String() != tt.expectedOutput {
+			t.Errorf("At case %d expect output to be %q. got: %q", i, tt.expectedOutput, out.String())
+		}
+
+		if errOut.String() != tt.expectedError {
+			t.Errorf("At case %d expect error output to be %q. got: %q", i, tt.expectedError, errOut.String())
+		}
+	}
+}
+
+func TestAtExitFlushesFiles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	v := initTestVM()
+	v.testEval(t, `
+	f = File.new("/tmp/goby/at_exit.txt", "w")
+	at_exit do
+	  f.write("bye")
+	end
+	`, getFilename())
+	v.RunExitHooks()
+
+	content, err := ioutil.ReadFile("/tmp/goby/at_exit.txt")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if string(content) != "bye" {
+		t.Errorf("Expect the file's content to be %q. got: %q", "bye", string(content))
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
 	"path/filepath"
 	"unicode"
 
@@ -47,7 +46,7 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 				path := args[0].(*StringObject).value
 				method := args[1].(*StringObject).value
 
-				router.HandleFunc(path, newHandler(t, blockFrame)).Methods(method)
+				router.HandleFunc(path, newHandler(t, receiver, blockFrame)).Methods(method)
 
 				return receiver
 
@@ -80,28 +79,33 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 					case *StringObject:
 						port = p.value
 					case *IntegerObject:
-						port = strconv.Itoa(p.value)
+						value, err := p.intValue(t, sourceLine)
+
+						if err != nil {
+							return err
+						}
+
+						port = strconv.Itoa(value)
 					default:
 						fmt.Printf("Unexpected type %s for port setting\n", portVar.Class().Name)
 					}
 				}
 
-				log.Println("SimpleServer start listening on port: " + port)
+				logServer(t, server, "SimpleServer start listening on port: "+port)
 
-				c := make(chan os.Signal, 1)
-				signal.Notify(c, os.Interrupt)
+				// Interrupting the server runs the `at_exit` blocks, unless the signal is trapped by `Signal.trap`
+				t.vm.addExitHook(func() {
+					thread := t.vm.newThread()
+					logServer(&thread, server, "SimpleServer gracefully stopped")
+				})
 
-				go func() {
-					for range c {
-						log.Println("SimpleServer gracefully stopped")
-						os.Exit(0)
-					}
-				}()
+				t.vm.listenExitSignals()
 
 				fileRoot, serveStatic := server.InstanceVariables.get("@file_root")
 
 				router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					log.Printf("%s %s %s %d\n", r.Method, r.URL.Path, r.Proto, 404)
+					thread := t.vm.newThread()
+					logServerAccess(&thread, server, r, http.StatusNotFound)
 				})
 
 				if serveStatic && fileRoot.Class() != t.vm.objectClass.getClassConstant(classes.NullClass) {
@@ -143,7 +147,7 @@ func initSimpleServerClass(vm *VM) {
 
 // Other helper functions -----------------------------------------------
 
-func newHandler(t *Thread, blockFrame *normalCallFrame) func(http.ResponseWriter, *http.Request) {
+func newHandler(t *Thread, server Object, blockFrame *normalCallFrame) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Go creates one goroutine per request, so we also need to create a new Goby thread for every request.
 		thread := t.vm.newThread()
@@ -152,12 +156,24 @@ func newHandler(t *Thread, blockFrame *normalCallFrame) func(http.ResponseWriter
 		req := initRequest(t, w, r)
 		result := thread.builtinMethodYield(blockFrame, req, res)
 
-		if err, ok := result.(*Error); ok {
-			log.Printf("Error: %s", err.message)
+		err, ok := result.(*Error)
+
+		if !ok {
+			err = checkResponseStatus(&thread, res)
+		}
+
+		if err != nil {
+			if l := serverLogger(server); l != nil {
+				l.log(&thread, logError, "Error", map[string]Object{"error": t.vm.InitStringObject(err.message)}, 0)
+			} else {
+				log.Printf("Error: %s", err.message)
+			}
+
 			res.InstanceVariableSet("@status", t.vm.InitIntegerObject(500))
 		}
 
-		setupResponse(w, r, res)
+		status := setupResponse(w, r, res)
+		logServerAccess(&thread, server, r, status)
 	}
 }
 
@@ -200,7 +216,19 @@ func initRequest(t *Thread, w http.ResponseWriter, req *http.Request) *RObject {
 	return reqObj
 }
 
-func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
+// checkResponseStatus returns an error if the status set to the response doesn't fit in an int
+func checkResponseStatus(t *Thread, res *RObject) *Error {
+	status, ok := res.InstanceVariableGet("@status")
+
+	if !ok {
+		return nil
+	}
+
+	_, err := status.(*IntegerObject).intValue(t, 0)
+	return err
+}
+
+func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) int {
 	r := &response{}
 
 	resStatus, ok := res.InstanceVariableGet("@status")
@@ -233,7 +261,44 @@ func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
 	w.WriteHeader(r.status)
 
 	io.WriteString(w, r.body)
-	log.Printf("%s %s %s %d\n", req.Method, req.URL.Path, req.Proto, r.status)
+
+	return r.status
+}
+
+// serverLogger returns the Logger set to the server's `logger`, or nil
+func serverLogger(server Object) *LoggerObject {
+	l, _ := server.InstanceVariableGet("@logger")
+	logger, _ := l.(*LoggerObject)
+
+	return logger
+}
+
+// logServer writes the message to the server's logger at the "info" level, or to the standard logger of Go
+// if the server's logger isn't set
+func logServer(t *Thread, server Object, message string) {
+	if l := serverLogger(server); l != nil {
+		l.log(t, logInfo, message, map[string]Object{}, 0)
+		return
+	}
+
+	log.Println(message)
+}
+
+// logServerAccess writes an access log of the request with the fields of method, path, proto and status
+func logServerAccess(t *Thread, server Object, req *http.Request, status int) {
+	l := serverLogger(server)
+
+	if l == nil {
+		log.Printf("%s %s %s %d\n", req.Method, req.URL.Path, req.Proto, status)
+		return
+	}
+
+	l.log(t, logInfo, "Request", map[string]Object{
+		"method": t.vm.InitStringObject(req.Method),
+		"path":   t.vm.InitStringObject(req.URL.Path),
+		"proto":  t.vm.InitStringObject(req.Proto),
+		"status": t.vm.InitIntegerObject(status),
+	}, 0)
 }
 
 func toSnakeCase(in string) string {
@@ -2,6 +2,7 @@ package vm
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 	"unicode"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
 // StringObject represents string instances.
@@ -25,14 +28,47 @@ import (
 // f = :symbol
 // ```
 //
+// A String is a sequence of bytes with an encoding, which is either UTF-8 or binary (ASCII-8BIT).
+// String literals are UTF-8, where characters are Unicode code points, and `length`, `[]`, `slice`
+// and `each_char` work on characters. Binary strings are returned by the methods that read raw bytes,
+// like `File#read` with a length, `Base64.decode` and `SecureRandom.bytes`,
+// and their characters are single bytes, so network protocols and file formats can be handled byte by byte.
+//
+// ```ruby
+// s = "héllo"
+// s.length                 # => 5
+// s.bytesize               # => 6
+// s.encoding               # => "UTF-8"
+//
+// b = s.force_encoding("BINARY")
+// b.length                 # => 6
+// b[1]                     # => "\xC3"
+// b.force_encoding("UTF-8") == s # => true
+// ```
+//
 // **Note:**
 //
 // - Currently, manipulations are based upon Golang's Unicode manipulations.
-// - Currently, UTF-8 encoding is assumed based upon Golang's string manipulation, but the encoding is not actually specified(TBD).
 // - `String.new` is not supported.
 type StringObject struct {
 	*BaseObj
 	value string
+	// binary is true if the string is binary, which means its characters are bytes
+	binary bool
+}
+
+// The names of the supported encodings
+const (
+	stringEncodingUTF8   = "UTF-8"
+	stringEncodingBinary = "ASCII-8BIT"
+)
+
+// unicodeNormalizationForms are the forms of `unicode_normalize`
+var unicodeNormalizationForms = map[string]norm.Form{
+	"nfc":  norm.NFC,
+	"nfd":  norm.NFD,
+	"nfkc": norm.NFKC,
+	"nfkd": norm.NFKD,
 }
 
 // Class methods --------------------------------------------------------
@@ -96,6 +132,8 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		// "first" + "-second" # => "first-second"
 		// ```
 		//
+		// The result is binary if either of the strings is binary.
+		//
 		// @param string [String]
 		// @return [String]
 		Name: "+",
@@ -106,7 +144,12 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			return t.vm.InitStringObject(receiver.Value().(string) + args[0].Value().(string))
+			left := receiver.(*StringObject)
+			right := args[0].(*StringObject)
+			result := t.vm.InitStringObject(left.value + right.value)
+			result.binary = left.binary || right.binary
+
+			return result
 		},
 	},
 	{
@@ -126,7 +169,11 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			right := args[0].Value().(int)
+			right, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
 
 			if right < 0 {
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeSecondValue, right)
@@ -139,7 +186,7 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				result += left.value
 			}
 
-			return t.vm.InitStringObject(result)
+			return left.derive(t.vm, result)
 
 		},
 	},
@@ -268,6 +315,7 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		// "Hello"[-6]       # => nil
 		// "Hello😊"[5]      # => "😊"
 		// "Hello😊"[-1]     # => "😊"
+		// "Hello😊".b[-1]   # => "\x8A"
 		// ```
 		//
 		// @param index [Integer]
@@ -278,28 +326,32 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
 			}
 
-			str := receiver.(*StringObject).value
+			str := receiver.(*StringObject)
+			chars := str.chars()
+			strLength := len(chars)
 			i := args[0]
 
 			switch index := i.(type) {
 			case *IntegerObject:
-				indexValue := index.value
+				indexValue, err := index.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
 
 				if indexValue < 0 {
-					strLength := utf8.RuneCountInString(str)
 					if -indexValue > strLength {
 						return NULL
 					}
-					return t.vm.InitStringObject(string([]rune(str)[strLength+indexValue]))
+					return str.derive(t.vm, chars[strLength+indexValue])
 				}
 
-				if len(str) > indexValue {
-					return t.vm.InitStringObject(string([]rune(str)[indexValue]))
+				if strLength > indexValue {
+					return str.derive(t.vm, chars[indexValue])
 				}
 
 				return NULL
 			case *RangeObject:
-				strLength := utf8.RuneCountInString(str)
 				start := index.Start
 				end := index.End
 
@@ -323,7 +375,11 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 					end = strLength - 1
 				}
 
-				return t.vm.InitStringObject(string([]rune(str)[start : end+1]))
+				if end < start {
+					return str.derive(t.vm, "")
+				}
+
+				return str.derive(t.vm, strings.Join(chars[start:end+1], ""))
 			default:
 				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
 			}
@@ -359,7 +415,11 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			indexValue := args[0].Value().(int)
+			indexValue, err := args[0].(*IntegerObject).intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
 			replaceStrValue := args[1].Value().(string)
 
 			str := receiver.(*StringObject).value
@@ -387,6 +447,176 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns a binary copy of the string, whose characters are bytes.
+		//
+		// ```ruby
+		// "é".b        # => "\xC3\xA9"
+		// "é".b.length # => 2
+		// ```
+		//
+		// @return [String]
+		Name: "b",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.initBinaryStringObject(receiver.(*StringObject).value)
+
+		},
+	},
+	{
+		// Returns an Array of the bytes of the string as Integers.
+		//
+		// ```ruby
+		// "Goby".bytes # => [71, 111, 98, 121]
+		// "é".bytes    # => [195, 169]
+		// ```
+		//
+		// @return [Array]
+		Name: "bytes",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			str := receiver.(*StringObject).value
+			elems := []Object{}
+
+			for i := 0; i < len(str); i++ {
+				elems = append(elems, t.vm.InitIntegerObject(int(str[i])))
+			}
+
+			return t.vm.InitArrayObject(elems)
+
+		},
+	},
+	{
+		// Returns the length of the string in bytes.
+		//
+		// ```ruby
+		// "Goby".bytesize # => 4
+		// "😊".bytesize   # => 4
+		// ```
+		//
+		// @return [Integer]
+		Name: "bytesize",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(len(receiver.(*StringObject).value))
+
+		},
+	},
+	{
+		// Returns the part of the string specified in bytes, by an index, an index with a length, or a Range.
+		// Negative indexes count from the end. Returns nil if the start is out of the string.
+		// The result has the same encoding as the receiver, so it may be an invalid UTF-8 string.
+		//
+		// ```ruby
+		// "Hello".byteslice(1)     # => "e"
+		// "Hello".byteslice(1, 3)  # => "ell"
+		// "Hello".byteslice(-3..-1) # => "llo"
+		// "Hello".byteslice(6, 1)  # => nil
+		// "héllo".byteslice(0, 3)  # => "hé"
+		// ```
+		//
+		// @param start [Integer, Range], length [Integer]
+		// @return [String]
+		Name: "byteslice",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			str := receiver.(*StringObject)
+			size := len(str.value)
+			var start, end int
+
+			switch index := args[0].(type) {
+			case *IntegerObject:
+				value, err := index.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				start = value
+				end = start + 1
+
+				if len(args) == 2 {
+					length, ok := args[1].(*IntegerObject)
+
+					if !ok {
+						return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.IntegerClass, args[1].Class().Name)
+					}
+
+					lengthValue, err := length.intValue(t, sourceLine)
+
+					if err != nil {
+						return err
+					}
+
+					if lengthValue < 0 {
+						return NULL
+					}
+
+					if start < 0 {
+						start += size
+					}
+
+					end = start + lengthValue
+				} else {
+					if start < 0 {
+						start += size
+					}
+
+					if start >= size {
+						return NULL
+					}
+
+					end = start + 1
+				}
+			case *RangeObject:
+				if len(args) == 2 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+				}
+
+				start = index.Start
+				end = index.End
+
+				if start < 0 {
+					start += size
+				}
+
+				if end < 0 {
+					end += size
+				}
+
+				end++
+			default:
+				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, "Integer or Range", args[0].Class().Name)
+			}
+
+			if start < 0 || start > size {
+				return NULL
+			}
+
+			if end > size {
+				end = size
+			}
+
+			if end < start {
+				end = start
+			}
+
+			return str.derive(t.vm, str.value[start:end])
+
+		},
+	},
 	{
 		// Returns a new String with the first character converted to uppercase.
 		// Non case-sensitive characters will be remained untouched.
@@ -411,6 +641,83 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Compares the strings ignoring case with Unicode case folding like `<=>`,
+		// and returns -1, 0 or 1.
+		//
+		// ```ruby
+		// "Goby".casecmp("GOBY")   # => 0
+		// "a".casecmp("B")         # => -1
+		// "Straße".casecmp("STRASSE") # => 0
+		// ```
+		//
+		// @param string [String]
+		// @return [Integer]
+		Name: "casecmp",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			left := foldCase(receiver.(*StringObject).value)
+			right := foldCase(args[0].(*StringObject).value)
+
+			return t.vm.InitIntegerObject(strings.Compare(left, right))
+
+		},
+	},
+	{
+		// Returns true if the strings are equal ignoring case with Unicode case folding.
+		//
+		// ```ruby
+		// "Goby".casecmp?("gOBY")     # => true
+		// "Straße".casecmp?("STRASSE") # => true
+		// "ΣΑΣ".casecmp?("σας")       # => true
+		// ```
+		//
+		// @param string [String]
+		// @return [Boolean]
+		Name: "casecmp?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			return toBooleanObject(foldCase(receiver.(*StringObject).value) == foldCase(args[0].(*StringObject).value))
+
+		},
+	},
+	{
+		// Returns an Array of the characters, which are bytes if the string is binary.
+		//
+		// ```ruby
+		// "Goby😊".chars # => ["G", "o", "b", "y", "😊"]
+		// "é".b.chars    # => ["\xC3", "\xA9"]
+		// ```
+		//
+		// @return [Array]
+		Name: "chars",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return receiver.(*StringObject).charsArray(t.vm)
+
+		},
+	},
 	{
 		// Returns a string with the last character chopped.
 		//
@@ -432,6 +739,44 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns an Array of the Unicode code points of the characters as Integers,
+		// or of the bytes if the string is binary. Raises an ArgumentError if the string isn't valid UTF-8.
+		//
+		// ```ruby
+		// "Goby".codepoints # => [71, 111, 98, 121]
+		// "é😊".codepoints  # => [233, 128522]
+		// ```
+		//
+		// @return [Array]
+		Name: "codepoints",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			str := receiver.(*StringObject)
+			elems := []Object{}
+
+			for _, char := range str.chars() {
+				if str.binary {
+					elems = append(elems, t.vm.InitIntegerObject(int(char[0])))
+					continue
+				}
+
+				r, size := utf8.DecodeRuneInString(char)
+
+				if r == utf8.RuneError && size == 1 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidByteSequence, stringEncodingUTF8)
+				}
+
+				elems = append(elems, t.vm.InitIntegerObject(int(r)))
+			}
+
+			return t.vm.InitArrayObject(elems)
+
+		},
+	},
 	{
 		// Returns a string which is concatenate with the input string or character.
 		//
@@ -454,8 +799,12 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return typeErr
 			}
 
-			str := receiver.(*StringObject).value
-			return t.vm.InitStringObject(str + args[0].Value().(string))
+			left := receiver.(*StringObject)
+			right := args[0].(*StringObject)
+			result := t.vm.InitStringObject(left.value + right.value)
+			result.binary = left.binary || right.binary
+
+			return result
 
 		},
 	},
@@ -473,10 +822,7 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		Name: "count",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-			str := receiver.(*StringObject).value
-
-			// Support UTF-8 Encoding
-			return t.vm.InitIntegerObject(utf8.RuneCountInString(str))
+			return t.vm.InitIntegerObject(receiver.(*StringObject).length())
 
 		},
 	},
@@ -625,8 +971,8 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitStringObject(str)
 			}
 
-			for _, char := range []rune(str) {
-				t.builtinMethodYield(blockFrame, t.vm.InitStringObject(string(char)))
+			for _, char := range receiver.(*StringObject).chars() {
+				t.builtinMethodYield(blockFrame, receiver.(*StringObject).derive(t.vm, char))
 			}
 
 			return t.vm.InitStringObject(str)
@@ -691,6 +1037,25 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns the name of the string's encoding, which is "UTF-8" or "ASCII-8BIT" for binary strings.
+		//
+		// ```ruby
+		// "Goby".encoding   # => "UTF-8"
+		// "Goby".b.encoding # => "ASCII-8BIT"
+		// ```
+		//
+		// @return [String]
+		Name: "encoding",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(receiver.(*StringObject).encoding())
+
+		},
+	},
 	{
 		// Returns true if receiver string end with the argument string
 		//
@@ -761,6 +1126,46 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns a copy of the string with the given encoding, without changing the bytes.
+		// The encoding is "UTF-8", or "BINARY" or "ASCII-8BIT" for a binary string, and is case-insensitive.
+		// Unlike Ruby, the receiver isn't changed.
+		//
+		// ```ruby
+		// data = socket_data.force_encoding("BINARY")
+		// data.length # => the number of bytes
+		//
+		// "\xC3\xA9".force_encoding("UTF-8") # => "é"
+		// ```
+		//
+		// @param encoding [String]
+		// @return [String]
+		Name: "force_encoding",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			name := args[0].(*StringObject).value
+			binary, ok := isBinaryEncoding(name)
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownEncoding, name)
+			}
+
+			result := t.vm.InitStringObject(receiver.(*StringObject).value)
+			result.binary = binary
+
+			return result
+
+		},
+	},
 	{
 		// Checks if the specified string is included in the receiver.
 		//
@@ -826,7 +1231,12 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.StringClass, args[1].Class().Name)
 			}
 
-			indexValue := index.value
+			indexValue, err := index.intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
 			str := receiver.(*StringObject).value
 			strLength := utf8.RuneCountInString(str)
 
@@ -850,22 +1260,20 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
-		// Returns the character length of self.
+		// Returns the character length of self, which is the byte length for binary strings. See also `bytesize`.
 		//
 		// ```ruby
 		// "zero".length # => 4
 		// "".length     # => 0
 		// "😊".length   # => 1
+		// "😊".b.length # => 4
 		// ```
 		//
 		// @return [Integer]
 		Name: "length",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-			str := receiver.(*StringObject).value
-
-			// Support UTF-8 Encoding
-			return t.vm.InitIntegerObject(utf8.RuneCountInString(str))
+			return t.vm.InitIntegerObject(receiver.(*StringObject).length())
 
 		},
 	},
@@ -899,7 +1307,11 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.IntegerClass, args[0].Class().Name)
 			}
 
-			strLengthValue := strLength.value
+			strLengthValue, err := strLength.intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
 
 			var padStrValue string
 			if aLen == 1 {
@@ -1108,15 +1520,15 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		Name: "reverse",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-			str := receiver.(*StringObject).value
+			str := receiver.(*StringObject)
+			chars := str.chars()
 
-			var revert string
-			for i := utf8.RuneCountInString(str) - 1; i >= 0; i-- {
-				revert += string([]rune(str)[i])
+			var revert strings.Builder
+			for i := len(chars) - 1; i >= 0; i-- {
+				revert.WriteString(chars[i])
 			}
 
-			// Support UTF-8 Encoding
-			return t.vm.InitStringObject(revert)
+			return str.derive(t.vm, revert.String())
 
 		},
 	},
@@ -1150,7 +1562,11 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 1, classes.IntegerClass, args[0].Class().Name)
 			}
 
-			strLengthValue := strLength.value
+			strLengthValue, err := strLength.intValue(t, sourceLine)
+
+			if err != nil {
+				return err
+			}
 
 			var padStrValue string
 			if aLen == 1 {
@@ -1188,7 +1604,56 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
-		// Returns the character length of self.
+		// Returns a copy of the string with each invalid UTF-8 byte replaced with the given string,
+		// which is "�" (the replacement character) by default. Binary strings are returned as they are.
+		//
+		// ```ruby
+		// data.force_encoding("UTF-8").scrub      # => "caf�"
+		// data.force_encoding("UTF-8").scrub("?") # => "caf?"
+		// ```
+		//
+		// @param replacement [String]
+		// @return [String]
+		Name: "scrub",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			replacement := string(utf8.RuneError)
+
+			if len(args) == 1 {
+				r, ok := args[0].(*StringObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+				}
+
+				replacement = r.value
+			}
+
+			str := receiver.(*StringObject)
+
+			if str.binary {
+				return str.derive(t.vm, str.value)
+			}
+
+			var result strings.Builder
+
+			for _, char := range str.chars() {
+				if r, size := utf8.DecodeRuneInString(char); r == utf8.RuneError && size == 1 {
+					char = replacement
+				}
+
+				result.WriteString(char)
+			}
+
+			return t.vm.InitStringObject(result.String())
+
+		},
+	},
+	{
+		// Returns the character length of self, which is the byte length for binary strings. See also `bytesize`.
 		//
 		// ```ruby
 		// "zero".size  # => 4
@@ -1200,10 +1665,7 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		Name: "size",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
 
-			str := receiver.(*StringObject).value
-
-			// Support UTF-8 Encoding
-			return t.vm.InitIntegerObject(utf8.RuneCountInString(str))
+			return t.vm.InitIntegerObject(receiver.(*StringObject).length())
 
 		},
 	},
@@ -1252,10 +1714,11 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
 			}
 
-			str := receiver.(*StringObject).value
-			strLength := utf8.RuneCountInString(str)
+			str := receiver.(*StringObject)
+			chars := str.chars()
+			strLength := len(chars)
 
-			// All Case Support UTF-8 Encoding
+			// Binary strings are sliced by bytes
 			slice := args[0]
 			switch slice.(type) {
 			case *RangeObject:
@@ -1265,48 +1728,53 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 					if ro.Start > strLength {
 						return NULL
 					} else if ro.Start > ro.End {
-						return t.vm.InitStringObject("")
+						return str.derive(t.vm, "")
 					}
-					return t.vm.InitStringObject(string([]rune(str)[ro.Start : ro.End+1]))
+					return str.derive(t.vm, strings.Join(chars[ro.Start:ro.End+1], ""))
 				case ro.Start < 0 && ro.End >= 0:
 					positiveStart := strLength + ro.Start
 					if -ro.Start > strLength {
 						return NULL
 					} else if positiveStart > ro.End {
-						return t.vm.InitStringObject("")
+						return str.derive(t.vm, "")
 					}
-					return t.vm.InitStringObject(string([]rune(str)[positiveStart : ro.End+1]))
+					return str.derive(t.vm, strings.Join(chars[positiveStart:ro.End+1], ""))
 				case ro.Start >= 0 && ro.End < 0:
 					positiveEnd := strLength + ro.End
 					if ro.Start > strLength {
 						return NULL
 					} else if positiveEnd < 0 || ro.Start > positiveEnd {
-						return t.vm.InitStringObject("")
+						return str.derive(t.vm, "")
 					}
-					return t.vm.InitStringObject(string([]rune(str)[ro.Start : positiveEnd+1]))
+					return str.derive(t.vm, strings.Join(chars[ro.Start:positiveEnd+1], ""))
 				default:
 					positiveStart := strLength + ro.Start
 					positiveEnd := strLength + ro.End
 					if positiveStart < 0 {
 						return NULL
 					} else if positiveStart > positiveEnd {
-						return t.vm.InitStringObject("")
+						return str.derive(t.vm, "")
 					}
-					return t.vm.InitStringObject(string([]rune(str)[positiveStart : positiveEnd+1]))
+					return str.derive(t.vm, strings.Join(chars[positiveStart:positiveEnd+1], ""))
 				}
 
 			case *IntegerObject:
-				iv := slice.(*IntegerObject).value
+				iv, err := slice.(*IntegerObject).intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
 				if iv < 0 {
 					if -iv > strLength {
 						return NULL
 					}
-					return t.vm.InitStringObject(string([]rune(str)[strLength+iv]))
+					return str.derive(t.vm, chars[strLength+iv])
 				}
 				if iv > strLength-1 {
 					return NULL
 				}
-				return t.vm.InitStringObject(string([]rune(str)[iv]))
+				return str.derive(t.vm, chars[iv])
 
 			default:
 				return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, "Range or Integer", slice.Class().Name)
@@ -1441,15 +1909,7 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
 			}
 
-			str := receiver.(*StringObject)
-			strLength := utf8.RuneCountInString(str.value)
-			e := []Object{}
-
-			for i := 0; i < strLength; i++ {
-				e = append(e, t.vm.InitStringObject(string([]rune(str.value)[i])))
-			}
-
-			return t.vm.InitArrayObject(e)
+			return receiver.(*StringObject).charsArray(t.vm)
 
 		},
 	},
@@ -1538,47 +1998,46 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 		},
 	},
 	{
-		// Returns the result of converting self to Integer.
-		// Passing a non-numerical string returns a 0 value.
+		// Returns the result of converting self to Integer. An optional base from 2 to 36 can be passed,
+		// which is 10 by default. Leading whitespace and a sign are allowed, as well as a `0b`, `0o` or `0x`
+		// prefix that matches the base. Parsing stops at the first invalid digit,
+		// so a non-numerical string returns a 0 value.
 		//
 		// ```ruby
 		// "123".to_i       # => 123
 		// "3d print".to_i  # => 3
 		// "  321".to_i     # => 321
 		// "some text".to_i # => 0
+		// "ff".to_i(16)    # => 255
+		// "0b1010".to_i(2) # => 10
+		// "-777".to_i(8)   # => -511
 		// ```
 		//
+		// @param base [Integer]
 		// @return [Integer]
 		Name: "to_i",
 		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
-			if len(args) != 0 {
-				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
 			}
 
-			str := receiver.(*StringObject).value
-			parsedStr, err := strconv.ParseInt(str, 10, 0)
+			base := 10
 
-			if err == nil {
-				return t.vm.InitIntegerObject(int(parsedStr))
-			}
+			if len(args) == 1 {
+				b, ok := args[0].(*IntegerObject)
 
-			var digits string
-			for _, char := range str {
-				if unicode.IsDigit(char) {
-					digits += string(char)
-				} else if unicode.IsSpace(char) && len(digits) == 0 {
-					// do nothing; allow trailing spaces
-				} else {
-					break
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 				}
-			}
 
-			if len(digits) > 0 {
-				parsedStr, _ = strconv.ParseInt(digits, 10, 0)
-				return t.vm.InitIntegerObject(int(parsedStr))
+				if b.bigValue != nil || b.value < 2 || b.value > 36 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.InvalidRadix, b.Value())
+				}
+
+				base = b.value
 			}
 
-			return t.vm.InitIntegerObject(0)
+			return t.vm.initIntegerObjectFromBigInt(parseIntegerPrefix(receiver.(*StringObject).value, base))
 
 		},
 	},
@@ -1617,6 +2076,57 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 			return t.vm.InitStringObject(str.Inspect())
 		},
 	},
+	{
+		// Returns a copy of the string in the given Unicode normalization form, which is "nfc" by default,
+		// "nfd", "nfkc" or "nfkd". Raises an ArgumentError if the string is binary.
+		//
+		// ```ruby
+		// decomposed = "é".unicode_normalize("nfd")
+		// decomposed.length                   # => 2
+		// decomposed.unicode_normalize.length # => 1
+		// "ﬁ".unicode_normalize("nfkc")       # => "fi"
+		// ```
+		//
+		// @param form [String]
+		// @return [String]
+		Name: "unicode_normalize",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			str := receiver.(*StringObject)
+			form, err := unicodeNormalizationFormOf(t, str, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return t.vm.InitStringObject(form.String(str.value))
+
+		},
+	},
+	{
+		// Returns true if the string is in the given Unicode normalization form, which is "nfc" by default,
+		// "nfd", "nfkc" or "nfkd". Raises an ArgumentError if the string is binary.
+		//
+		// ```ruby
+		// "é".unicode_normalized?                                 # => true
+		// "é".unicode_normalize("nfd").unicode_normalized?        # => false
+		// "é".unicode_normalize("nfd").unicode_normalized?("nfd") # => true
+		// ```
+		//
+		// @param form [String]
+		// @return [Boolean]
+		Name: "unicode_normalized?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			str := receiver.(*StringObject)
+			form, err := unicodeNormalizationFormOf(t, str, args, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return toBooleanObject(form.IsNormalString(str.value))
+
+		},
+	},
 	{
 		// Returns a new String with all characters is upcase.
 		//
@@ -1634,6 +2144,29 @@ var builtinStringInstanceMethods = []*BuiltinMethodObject{
 
 		},
 	},
+	{
+		// Returns true if the string is valid in its encoding. Binary strings are always valid,
+		// and UTF-8 strings are invalid if they contain bytes that aren't UTF-8.
+		//
+		// ```ruby
+		// "Goby😊".valid_encoding?            # => true
+		// "😊".byteslice(0, 2).valid_encoding? # => false
+		// "😊".b.byteslice(0, 2).valid_encoding? # => true
+		// ```
+		//
+		// @return [Boolean]
+		Name: "valid_encoding?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			str := receiver.(*StringObject)
+
+			return toBooleanObject(str.binary || utf8.ValidString(str.value))
+
+		},
+	},
 }
 
 // Internal functions ===================================================
@@ -1655,6 +2188,13 @@ func (vm *VM) initStringClass() *RClass {
 	return sc
 }
 
+// initBinaryStringObject creates a binary StringObject
+func (vm *VM) initBinaryStringObject(value string) *StringObject {
+	s := vm.InitStringObject(value)
+	s.binary = true
+	return s
+}
+
 // Polymorphic helper functions -----------------------------------------
 
 // Value returns the object
@@ -1667,9 +2207,25 @@ func (s *StringObject) ToString() string {
 	return fmt.Sprintf(`%s`, s.value)
 }
 
-// Inspect wraps ToString with double quotes
+// Inspect wraps ToString with double quotes. The non-ASCII bytes of a binary string are escaped like "\xFF".
 func (s *StringObject) Inspect() string {
-	return fmt.Sprintf(`"%s"`, escapeSpecialChars(escapeBackslash(s.ToString())))
+	str := escapeSpecialChars(escapeBackslash(s.ToString()))
+
+	if s.binary {
+		var escaped strings.Builder
+
+		for i := 0; i < len(str); i++ {
+			if str[i] < utf8.RuneSelf {
+				escaped.WriteByte(str[i])
+			} else {
+				fmt.Fprintf(&escaped, `\x%02X`, str[i])
+			}
+		}
+
+		str = escaped.String()
+	}
+
+	return fmt.Sprintf(`"%s"`, str)
 }
 
 func (s *StringObject) equalTo(compared Object) bool {
@@ -1701,3 +2257,151 @@ func (s *StringObject) ToJSON(t *Thread) string {
 func (s *StringObject) equal(e *StringObject) bool {
 	return s.value == e.value
 }
+
+// derive returns a new String of the value, which has the same encoding as s
+func (s *StringObject) derive(vm *VM, value string) *StringObject {
+	if s.binary {
+		return vm.initBinaryStringObject(value)
+	}
+
+	return vm.InitStringObject(value)
+}
+
+// chars returns the characters of the string, which are bytes if the string is binary.
+// Each invalid byte of a UTF-8 string is a character as is.
+func (s *StringObject) chars() []string {
+	chars := []string{}
+
+	for i := 0; i < len(s.value); {
+		size := 1
+
+		if !s.binary {
+			_, size = utf8.DecodeRuneInString(s.value[i:])
+		}
+
+		chars = append(chars, s.value[i:i+size])
+		i += size
+	}
+
+	return chars
+}
+
+// charsArray returns an Array of the characters
+func (s *StringObject) charsArray(vm *VM) *ArrayObject {
+	elems := []Object{}
+
+	for _, char := range s.chars() {
+		elems = append(elems, s.derive(vm, char))
+	}
+
+	return vm.InitArrayObject(elems)
+}
+
+// length returns the number of the characters
+func (s *StringObject) length() int {
+	if s.binary {
+		return len(s.value)
+	}
+
+	return utf8.RuneCountInString(s.value)
+}
+
+// encoding returns the name of the string's encoding
+func (s *StringObject) encoding() string {
+	if s.binary {
+		return stringEncodingBinary
+	}
+
+	return stringEncodingUTF8
+}
+
+// isBinaryEncoding returns true if the name is of the binary encoding, or false if it's of UTF-8.
+// The name is case-insensitive, and ok is false if the encoding isn't supported.
+func isBinaryEncoding(name string) (binary bool, ok bool) {
+	switch strings.ToUpper(name) {
+	case stringEncodingUTF8, "UTF8":
+		return false, true
+	case stringEncodingBinary, "BINARY":
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// foldCase returns the string with Unicode case folding, which is for comparing strings ignoring case
+func foldCase(str string) string {
+	return cases.Fold().String(str)
+}
+
+// unicodeNormalizationFormOf returns the normalization form given as the optional argument, which is NFC by default
+func unicodeNormalizationFormOf(t *Thread, str *StringObject, args []Object, sourceLine int) (norm.Form, *Error) {
+	if len(args) > 1 {
+		return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+	}
+
+	name := "nfc"
+
+	if len(args) == 1 {
+		s, ok := args[0].(*StringObject)
+
+		if !ok {
+			return 0, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+		}
+
+		name = s.value
+	}
+
+	form, ok := unicodeNormalizationForms[name]
+
+	if !ok {
+		return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownNormalizationForm, name)
+	}
+
+	if str.binary {
+		return 0, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "Can't normalize a %s string", stringEncodingBinary)
+	}
+
+	return form, nil
+}
+
+// parseIntegerPrefix parses the longest valid integer at the start of str in the given base,
+// and returns 0 if there's none.
+func parseIntegerPrefix(str string, base int) *big.Int {
+	str = strings.TrimLeftFunc(str, unicode.IsSpace)
+	negative := false
+
+	if len(str) > 0 && (str[0] == '-' || str[0] == '+') {
+		negative = str[0] == '-'
+		str = str[1:]
+	}
+
+	prefixes := map[int]string{2: "0b", 8: "0o", 16: "0x"}
+
+	if prefix, ok := prefixes[base]; ok && len(str) > 2 && strings.EqualFold(str[:2], prefix) {
+		str = str[2:]
+	}
+
+	end := 0
+
+	for end < len(str) {
+		digit, err := strconv.ParseInt(str[end:end+1], base, 0)
+
+		if err != nil || int(digit) >= base {
+			break
+		}
+
+		end++
+	}
+
+	result, ok := new(big.Int).SetString(str[:end], base)
+
+	if !ok {
+		return new(big.Int)
+	}
+
+	if negative {
+		result.Neg(result)
+	}
+
+	return result
+}
@@ -0,0 +1,711 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// StringIOObject is an in-memory IO, which can be read and written like a `File`.
+// It's useful for testing code that takes a file, or for building a string with `puts` and `print`.
+//
+// ```ruby
+// io = StringIO.new
+// io.puts("Hello")
+// io.print("Goby", "!")
+// io.string # => "Hello\nGoby!"
+//
+// io = StringIO.new("first\nsecond\n")
+// io.gets   # => "first\n"
+// io.each_line do |line|
+//   puts(line)
+// end
+// ```
+//
+// It can be passed to `JSON.each` and `CSV.parse`, or assigned to `$stdout` and given to `Logger.new`
+// to capture the output:
+//
+// ```ruby
+// require "logger"
+//
+// io = StringIO.new
+// Logger.new(io).info("started")
+// io.string # => "time=2018-05-26T16:55:13Z level=INFO msg=started\n"
+// ```
+//
+// Like `File`, the position is shared between reading and writing, and writing in the middle of the string
+// overwrites the existing bytes.
+type StringIOObject struct {
+	*BaseObj
+	buffer bytes.Buffer
+	pos    int
+	closed bool
+}
+
+// Class methods --------------------------------------------------------
+var builtinStringIOClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns a new StringIO with the given string, or an empty one. The position starts at the beginning.
+		//
+		// ```ruby
+		// StringIO.new("abc").read # => "abc"
+		// ```
+		//
+		// @param string [String]
+		// @return [StringIO]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			s := t.vm.initStringIOObject("")
+
+			if len(args) == 1 {
+				str, ok := args[0].(*StringObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+				}
+
+				s.buffer.WriteString(str.value)
+			}
+
+			return s
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinStringIOInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Writes the object like `write`, and returns the StringIO so that the calls can be chained.
+		//
+		// ```ruby
+		// (StringIO.new << "a" << 1).string # => "a1"
+		// ```
+		//
+		// @param object [Object]
+		// @return [StringIO]
+		Name: "<<",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			if err := receiver.(*StringIOObject).writeObjects(t, args, sourceLine); err != nil {
+				return err
+			}
+
+			return receiver
+
+		},
+	},
+	{
+		// Closes the StringIO. Reading or writing a closed StringIO raises an IOError,
+		// but its string can still be retrieved with `string`.
+		//
+		// @return [Null]
+		Name: "close",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			receiver.(*StringIOObject).closed = true
+			return NULL
+
+		},
+	},
+	{
+		// Returns true if the StringIO is closed.
+		//
+		// @return [Boolean]
+		Name: "closed?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return toBooleanObject(receiver.(*StringIOObject).closed)
+
+		},
+	},
+	{
+		// Yields each line from the current position, including the trailing newline.
+		// Returns a lazy enumerator of the lines without a block.
+		//
+		// ```ruby
+		// StringIO.new("a\nb\n").each_line do |line|
+		//   puts(line)
+		// end
+		//
+		// StringIO.new("a\nb\n").each_line.first(1) # => ["a\n"]
+		// ```
+		//
+		// @return [StringIO]
+		Name: "each_line",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				if blockFrame != nil {
+					t.callFrameStack.pop()
+				}
+
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			if blockFrame == nil {
+				return t.lineEnumerator(receiver, sourceLine)
+			}
+
+			s := receiver.(*StringIOObject)
+			yielded := false
+
+			for {
+				// The block may close the StringIO
+				if err := s.checkOpen(t, sourceLine); err != nil {
+					if !yielded {
+						t.callFrameStack.pop()
+					}
+
+					return err
+				}
+
+				line, ok := s.readLine()
+
+				if !ok {
+					break
+				}
+
+				t.builtinMethodYield(blockFrame, t.vm.InitStringObject(line))
+				yielded = true
+			}
+
+			if !yielded {
+				t.callFrameStack.pop()
+			}
+
+			return receiver
+
+		},
+	},
+	{
+		// Returns true if there's nothing left to read from the current position.
+		//
+		// ```ruby
+		// io = StringIO.new("a\nb\n")
+		// while !io.eof? do
+		//   puts(io.gets)
+		// end
+		// ```
+		//
+		// @return [Boolean]
+		Name: "eof?",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			s := receiver.(*StringIOObject)
+
+			if err := s.checkOpen(t, sourceLine); err != nil {
+				return err
+			}
+
+			return toBooleanObject(s.pos >= s.buffer.Len())
+
+		},
+	},
+	{
+		// Does nothing, since nothing is buffered. It's defined so that a StringIO can be used in place of a `File`.
+		//
+		// @return [StringIO]
+		Name: "flush",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return receiver
+
+		},
+	},
+	{
+		// Reads the next line including the trailing newline, or returns nil at the end of the string.
+		//
+		// ```ruby
+		// io = StringIO.new("first\nsecond")
+		// io.gets # => "first\n"
+		// io.gets # => "second"
+		// io.gets # => nil
+		// ```
+		//
+		// @return [String]
+		Name: "gets",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			s := receiver.(*StringIOObject)
+
+			if err := s.checkOpen(t, sourceLine); err != nil {
+				return err
+			}
+
+			line, ok := s.readLine()
+
+			if !ok {
+				return NULL
+			}
+
+			return t.vm.InitStringObject(line)
+
+		},
+	},
+	{
+		// Returns the current position in bytes.
+		//
+		// ```ruby
+		// io = StringIO.new("first\nsecond")
+		// io.gets
+		// io.pos # => 6
+		// ```
+		//
+		// @return [Integer]
+		Name: "pos",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(receiver.(*StringIOObject).pos)
+
+		},
+	},
+	{
+		// Writes the given objects like `write`, converting non-string objects with `to_s`.
+		//
+		// ```ruby
+		// io = StringIO.new
+		// io.print("Loading", "...")
+		// io.string # => "Loading..."
+		// ```
+		//
+		// @return [Null]
+		Name: "print",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if err := receiver.(*StringIOObject).writeObjects(t, args, sourceLine); err != nil {
+				return err
+			}
+
+			return NULL
+
+		},
+	},
+	{
+		// Writes the given objects with a trailing line feed each, converting non-string objects with `to_s`.
+		//
+		// ```ruby
+		// io = StringIO.new
+		// io.puts("foo", "bar")
+		// io.string # => "foo\nbar\n"
+		// ```
+		//
+		// @return [Null]
+		Name: "puts",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s := receiver.(*StringIOObject)
+
+			if err := s.checkOpen(t, sourceLine); err != nil {
+				return err
+			}
+
+			s.Write([]byte(linesOf(args)))
+			return NULL
+
+		},
+	},
+	{
+		// Returns the rest of the string from the current position, and moves the position to the end.
+		// If a length is given, reads at most that many bytes as a binary String instead,
+		// and returns nil at the end of the string.
+		//
+		// ```ruby
+		// io = StringIO.new("Hello, Goby!")
+		// io.read(5) # => "Hello"
+		// io.read    # => ", Goby!"
+		// io.read    # => ""
+		// io.read(1) # => nil
+		// ```
+		//
+		// @param length [Integer]
+		// @return [String]
+		Name: "read",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 1, len(args))
+			}
+
+			s := receiver.(*StringIOObject)
+
+			if err := s.checkOpen(t, sourceLine); err != nil {
+				return err
+			}
+
+			rest := s.rest()
+
+			if len(args) == 1 {
+				n, ok := args[0].(*IntegerObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+				}
+
+				length, err := n.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				if length < 0 {
+					return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.NegativeValue, length)
+				}
+
+				if len(rest) == 0 && length > 0 {
+					return NULL
+				}
+
+				if length < len(rest) {
+					rest = rest[:length]
+				}
+			}
+
+			s.pos += len(rest)
+
+			if len(args) == 1 {
+				return t.vm.initBinaryStringObject(string(rest))
+			}
+
+			return t.vm.InitStringObject(string(rest))
+
+		},
+	},
+	{
+		// Reads the next line like `gets`, but raises an EOFError at the end of the string.
+		//
+		// ```ruby
+		// StringIO.new("first\n").readline # => "first\n"
+		// StringIO.new("").readline        # => EOFError
+		// ```
+		//
+		// @return [String]
+		Name: "readline",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			s := receiver.(*StringIOObject)
+
+			if err := s.checkOpen(t, sourceLine); err != nil {
+				return err
+			}
+
+			line, ok := s.readLine()
+
+			if !ok {
+				return t.vm.InitErrorObject(errors.EOFError, sourceLine, errors.EndOfFile)
+			}
+
+			return t.vm.InitStringObject(line)
+
+		},
+	},
+	{
+		// Moves the position back to the start of the string.
+		//
+		// @return [Integer]
+		Name: "rewind",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			receiver.(*StringIOObject).pos = 0
+			return t.vm.InitIntegerObject(0)
+
+		},
+	},
+	{
+		// Moves the position to the given offset in bytes. The offset is relative to the start of the string
+		// by default, or to `File::SEEK_CUR` or `File::SEEK_END` if it's given as the second argument.
+		// Writing after seeking past the end fills the gap with null bytes.
+		//
+		// ```ruby
+		// io = StringIO.new("Hello, Goby!")
+		// io.seek(-5, File::SEEK_END)
+		// io.read # => "Goby!"
+		// ```
+		//
+		// @param offset [Integer]
+		// @param whence [Integer]
+		// @return [Integer]
+		Name: "seek",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			offset, whence := 0, io.SeekStart
+
+			for i, arg := range args {
+				n, ok := arg.(*IntegerObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, i+1, classes.IntegerClass, arg.Class().Name)
+				}
+
+				value, err := n.intValue(t, sourceLine)
+
+				if err != nil {
+					return err
+				}
+
+				if i == 0 {
+					offset = value
+				} else {
+					whence = value
+				}
+			}
+
+			s := receiver.(*StringIOObject)
+
+			if err := s.checkOpen(t, sourceLine); err != nil {
+				return err
+			}
+
+			_, err := s.Seek(int64(offset), whence)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitIntegerObject(0)
+
+		},
+	},
+	{
+		// Returns the size of the string in bytes.
+		//
+		// ```ruby
+		// StringIO.new("Hello").size # => 5
+		// ```
+		//
+		// @return [Integer]
+		Name: "size",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitIntegerObject(receiver.(*StringIOObject).buffer.Len())
+
+		},
+	},
+	{
+		// Returns the whole string regardless of the current position.
+		//
+		// ```ruby
+		// io = StringIO.new
+		// io.write("Hello")
+		// io.string # => "Hello"
+		// ```
+		//
+		// @return [String]
+		Name: "string",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(receiver.(*StringIOObject).buffer.String())
+
+		},
+	},
+	{
+		// Writes the given objects at the current position, converting non-string objects with `to_s`,
+		// and returns the number of bytes written.
+		//
+		// ```ruby
+		// io = StringIO.new("Hello, Ruby!")
+		// io.seek(7)
+		// io.write("Goby") # => 4
+		// io.string        # => "Hello, Goby!"
+		// ```
+		//
+		// @return [Integer]
+		Name: "write",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			s := receiver.(*StringIOObject)
+			pos := s.pos
+
+			if err := s.writeObjects(t, args, sourceLine); err != nil {
+				return err
+			}
+
+			return t.vm.InitIntegerObject(s.pos - pos)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initStringIOObject(str string) *StringIOObject {
+	s := &StringIOObject{BaseObj: NewBaseObject(vm.TopLevelClass(classes.StringIOClass))}
+	s.buffer.WriteString(str)
+	return s
+}
+
+func (vm *VM) initStringIOClass() *RClass {
+	sc := vm.initializeClass(classes.StringIOClass)
+	sc.setBuiltinMethods(builtinStringIOClassMethods, true)
+	sc.setBuiltinMethods(builtinStringIOInstanceMethods, false)
+	return sc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the object's name as the string format
+func (s *StringIOObject) ToString() string {
+	return "#<StringIO>"
+}
+
+// Inspect delegates to ToString
+func (s *StringIOObject) Inspect() string {
+	return s.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (s *StringIOObject) ToJSON(t *Thread) string {
+	return jsonString(s.ToString())
+}
+
+// Value returns the StringIO itself, which is an io.ReadWriteSeeker
+func (s *StringIOObject) Value() interface{} {
+	return s
+}
+
+// Other helper functions -----------------------------------------------
+
+// Read reads from the current position like io.Reader
+func (s *StringIOObject) Read(p []byte) (int, error) {
+	rest := s.rest()
+
+	if len(rest) == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, rest)
+	s.pos += n
+	return n, nil
+}
+
+// Write writes at the current position like io.Writer, overwriting the existing bytes and extending the buffer
+// when needed
+func (s *StringIOObject) Write(p []byte) (int, error) {
+	if gap := s.pos - s.buffer.Len(); gap > 0 {
+		s.buffer.Write(make([]byte, gap))
+	}
+
+	n := copy(s.buffer.Bytes()[s.pos:], p)
+	s.buffer.Write(p[n:])
+	s.pos += len(p)
+	return len(p), nil
+}
+
+// Seek sets the current position like io.Seeker
+func (s *StringIOObject) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = int64(s.pos) + offset
+	case io.SeekEnd:
+		pos = int64(s.buffer.Len()) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+
+	if pos < 0 {
+		return 0, fmt.Errorf("invalid offset: %d", offset)
+	}
+
+	s.pos = int(pos)
+	return pos, nil
+}
+
+// rest returns the bytes after the current position
+func (s *StringIOObject) rest() []byte {
+	if s.pos >= s.buffer.Len() {
+		return nil
+	}
+
+	return s.buffer.Bytes()[s.pos:]
+}
+
+// readLine returns the next line including the trailing newline, and false at the end of the string
+func (s *StringIOObject) readLine() (string, bool) {
+	rest := s.rest()
+
+	if len(rest) == 0 {
+		return "", false
+	}
+
+	if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[:i+1]
+	}
+
+	s.pos += len(rest)
+	return string(rest), true
+}
+
+// writeObjects writes the objects' string formats, or raw values of String objects
+func (s *StringIOObject) writeObjects(t *Thread, objects []Object, sourceLine int) *Error {
+	if err := s.checkOpen(t, sourceLine); err != nil {
+		return err
+	}
+
+	var data strings.Builder
+
+	for _, obj := range objects {
+		switch obj := obj.(type) {
+		case *StringObject:
+			data.WriteString(obj.value)
+		default:
+			data.WriteString(obj.ToString())
+		}
+	}
+
+	s.Write([]byte(data.String()))
+	return nil
+}
+
+// checkOpen returns an IOError if the StringIO is closed
+func (s *StringIOObject) checkOpen(t *Thread, sourceLine int) *Error {
+	if s.closed {
+		return t.vm.InitErrorObject(errors.IOError, sourceLine, "closed stream")
+	}
+
+	return nil
+}
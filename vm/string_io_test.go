@@ -0,0 +1,226 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestStringIOReadAndWrite(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`StringIO.new.string`, ""},
+		{`StringIO.new("abc").read`, "abc"},
+		{`StringIO.new("abc").size`, 3},
+		{`StringIO.new.inspect`, "#<StringIO>"},
+		{`
+		io = StringIO.new
+		io.puts("Hello", 1)
+		io.print("Goby", "!")
+		io.string
+		`, "Hello\n1\nGoby!"},
+		{`
+		io = StringIO.new
+		io.write("a", 1, nil, "\n")
+		`, 3},
+		{`(StringIO.new << "a" << 1).string`, "a1"},
+		{`
+		io = StringIO.new
+		io.write("Hello")
+		io.read
+		`, ""},
+		{`
+		io = StringIO.new
+		io.write("Hello")
+		io.rewind
+		io.read
+		`, "Hello"},
+		{`
+		io = StringIO.new("Hello, Goby!")
+		io.read(5) + "|" + io.read
+		`, "Hello|, Goby!"},
+		{`
+		io = StringIO.new("abc")
+		io.read
+		io.read(1)
+		`, nil},
+		{`StringIO.new("abc").read(0)`, ""},
+		{`StringIO.new("héllo").read(2).encoding`, "ASCII-8BIT"},
+		{`StringIO.new("héllo").read.encoding`, "UTF-8"},
+		{`StringIO.new("").read(0)`, ""},
+		{`
+		io = StringIO.new("first\nsecond")
+		[io.gets, io.pos, io.gets, io.gets]
+		`, []interface{}{"first\n", 6, "second", nil}},
+		{`
+		io = StringIO.new("a\nb\n")
+		lines = []
+		while !io.eof? do
+		  lines.push(io.readline)
+		end
+		lines
+		`, []interface{}{"a\n", "b\n"}},
+		{`
+		io = StringIO.new("Hello, Ruby!")
+		io.seek(7)
+		io.write("Goby")
+		io.string
+		`, "Hello, Goby!"},
+		{`
+		io = StringIO.new("Hello, Goby!")
+		io.seek(-5, File::SEEK_END)
+		io.read
+		`, "Goby!"},
+		{`
+		io = StringIO.new("Hello, Goby!")
+		io.read(2)
+		io.seek(3, File::SEEK_CUR)
+		io.read(2)
+		`, ", "},
+		{`
+		io = StringIO.new("ab")
+		io.seek(4)
+		io.write("c")
+		io.string.size
+		`, 5},
+		{`
+		io = StringIO.new("héllo")
+		io.read(3)
+		`, "hé"},
+		{`
+		io = StringIO.new("abc")
+		io.close
+		[io.closed?, io.string]
+		`, []interface{}{true, "abc"}},
+		{`StringIO.new.flush.closed?`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringIOEachLine(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		lines = []
+		StringIO.new("a\nb\nc").each_line do |line|
+		  lines.push(line)
+		end
+		lines
+		`, []interface{}{"a\n", "b\n", "c"}},
+		{`
+		out = StringIO.new
+		$stdout = out
+		io = StringIO.new("first\nsecond\n")
+		io.gets
+		io.each_line do |line|
+		  puts(line.size)
+		end
+		$stdout = STDOUT
+		out.string
+		`, "7\n"},
+		{`
+		io = StringIO.new("skip\na\nb\n")
+		io.gets
+		io.each_line.map do |line|
+		  line.upcase
+		end.first(5)
+		`, []interface{}{"A\n", "B\n"}},
+		{`StringIO.new("").each_line.first(1)`, []interface{}{}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringIOAsFile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "json"
+		names = []
+		JSON.each(StringIO.new("{\"name\":\"a\"}\n{\"name\":\"b\"}\n")) do |v|
+		  names.push(v["name"])
+		end
+		names
+		`, []interface{}{"a", "b"}},
+		{`
+		require "csv"
+		io = StringIO.new("a,b\n1,2\n")
+		CSV.parse(io, { headers: true })[0]["b"]
+		`, "2"},
+		{`
+		require "csv"
+		io = StringIO.new("skip\n1,2\n")
+		io.gets
+		CSV.parse(io)
+		`, []interface{}{[]interface{}{"1", "2"}}},
+		{`
+		require "logger"
+		io = StringIO.new
+		logger = Logger.new(io)
+		logger.info("started")
+		io.string.include?("level=INFO msg=started\n")
+		`, true},
+		{`
+		io = StringIO.new
+		$stdout = io
+		puts("captured")
+		print(1, 2)
+		$stdout = STDOUT
+		io.string
+		`, "captured\n12"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringIOFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`StringIO.new(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`StringIO.new("a", "b")`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
+		{`StringIO.new.read("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`StringIO.new.read(-1)`, "ArgumentError: Expect argument to be positive value. got: -1", 1},
+		{`StringIO.new.readline`, "EOFError: End of file reached", 1},
+		{`StringIO.new.seek("1")`, "TypeError: Expect argument #1 to be Integer. got: String", 1},
+		{`StringIO.new.seek(-1)`, "IOError: invalid offset: -1", 1},
+		{`StringIO.new.seek(0, 5)`, "IOError: invalid whence: 5", 1},
+		{`StringIO.new.gets(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`io = StringIO.new("a"); io.close; io.read`, "IOError: closed stream", 1},
+		{`io = StringIO.new; io.close; io.puts("a")`, "IOError: closed stream", 1},
+		{`io = StringIO.new; io.close; io.write("a")`, "IOError: closed stream", 1},
+		{`io = StringIO.new("a"); io.close; io.each_line do |line| end`, "IOError: closed stream", 1},
+		{`io = StringIO.new("a\nb"); io.each_line do |line| io.close end`, "IOError: closed stream", 1},
+		{`StringIO.new("a").each_line(1) do |line| end`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`require "json"; io = StringIO.new; io.close; JSON.each(io) do |v| end`, "IOError: closed stream", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
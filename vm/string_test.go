@@ -1272,6 +1272,14 @@ func TestStringConversion(t *testing.T) {
 		{`" \t123".to_i`, 123},
 		{`"123string123".to_i`, 123},
 		{`"string123".to_i`, 0},
+		{`"-42".to_i`, -42},
+		{`"ff".to_i(16)`, 255},
+		{`"0xFF".to_i(16)`, 255},
+		{`"0b1010".to_i(2)`, 10},
+		{`"  -777".to_i(8)`, -511},
+		{`"1012".to_i(2)`, 5},
+		{`"zz".to_i(36)`, 1295},
+		{`"10000000000000000000000".to_i.to_s`, "10000000000000000000000"},
 		{`"123.5".to_f`, 123.5},
 		{`".5".to_f`, 0.5},
 		{`"  123.5".to_f`, 123.5},
@@ -1368,7 +1376,10 @@ func TestStringConversionFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`"str".to_a(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
 		{`"str".to_d(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
-		{`"str".to_i(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`"str".to_i(1)`, "ArgumentError: Invalid radix. got: 1", 1},
+		{`"str".to_i(37)`, "ArgumentError: Invalid radix. got: 37", 1},
+		{`"str".to_i("2")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`"str".to_i(2, 8)`, "ArgumentError: Expect 1 or less argument(s). got: 2", 1},
 		{`"str".to_f(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
 		{`"str".to_s(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
 		{`"1.1.1".to_f`, "ArgumentError: Invalid numeric string. got: 1.1.1", 1},
@@ -1514,3 +1525,160 @@ ds += " Lo"
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestStringByteMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".bytesize`, 4},
+		{`"héllo😊".bytesize`, 10},
+		{`"".bytesize`, 0},
+		{`"Goby".bytes`, []interface{}{71, 111, 98, 121}},
+		{`"é".bytes`, []interface{}{195, 169}},
+		{`"Hello".byteslice(1)`, "e"},
+		{`"Hello".byteslice(-1)`, "o"},
+		{`"Hello".byteslice(5)`, nil},
+		{`"Hello".byteslice(1, 3)`, "ell"},
+		{`"Hello".byteslice(3, 10)`, "lo"},
+		{`"Hello".byteslice(5, 1)`, ""},
+		{`"Hello".byteslice(6, 1)`, nil},
+		{`"Hello".byteslice(-3, 2)`, "ll"},
+		{`"Hello".byteslice(1, -1)`, nil},
+		{`"Hello".byteslice(1..3)`, "ell"},
+		{`"Hello".byteslice(-3..-1)`, "llo"},
+		{`"Hello".byteslice(3..1)`, ""},
+		{`"héllo".byteslice(0, 3)`, "hé"},
+		{`"héllo".byteslice(0, 2).valid_encoding?`, false},
+		{`"héllo".byteslice(0, 2).encoding`, "UTF-8"},
+		{`"héllo".b.byteslice(0, 2).encoding`, "ASCII-8BIT"},
+		{`"Goby😊".chars`, []interface{}{"G", "o", "b", "y", "😊"}},
+		{`"".chars`, []interface{}{}},
+		{`"é😊".codepoints`, []interface{}{233, 128522}},
+		{`"é".b.codepoints`, []interface{}{195, 169}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringEncodingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".encoding`, "UTF-8"},
+		{`"Goby".b.encoding`, "ASCII-8BIT"},
+		{`"Goby".force_encoding("BINARY").encoding`, "ASCII-8BIT"},
+		{`"Goby".force_encoding("ascii-8bit").encoding`, "ASCII-8BIT"},
+		{`"Goby".b.force_encoding("utf-8").encoding`, "UTF-8"},
+		{`
+		s = "Goby"
+		s.force_encoding("BINARY")
+		s.encoding
+		`, "UTF-8"},
+		{`"héllo😊".b.length`, 10},
+		{`"héllo😊".b.size`, 10},
+		{`"héllo😊".b.force_encoding("UTF-8").length`, 6},
+		{`"héllo".b == "héllo"`, true},
+		{`"é".b.chars.map do |c| c.bytesize end`, []interface{}{1, 1}},
+		{`"é".b[0].encoding`, "ASCII-8BIT"},
+		{`"é".b[0].bytes`, []interface{}{195}},
+		{`"é".b[0..1].force_encoding("UTF-8")`, "é"},
+		{`"é".b.slice(1).bytes`, []interface{}{169}},
+		{`"é".b.reverse.bytes`, []interface{}{169, 195}},
+		{`("a".b + "é").encoding`, "ASCII-8BIT"},
+		{`("a" + "é".b).length`, 3},
+		{`"a".concat("é".b).encoding`, "ASCII-8BIT"},
+		{`("é".b * 2).length`, 4},
+		{`"é".b.inspect`, `"\xC3\xA9"`},
+		{`"a\"é".b.inspect`, `"a\"\xC3\xA9"`},
+		{`"Goby".b.inspect`, `"Goby"`},
+		{`"Goby😊".valid_encoding?`, true},
+		{`"😊".byteslice(0, 2).valid_encoding?`, false},
+		{`"😊".b.byteslice(0, 2).valid_encoding?`, true},
+		{`("caf" + "é".byteslice(0, 1)).scrub`, "caf�"},
+		{`("caf" + "é".byteslice(0, 1)).scrub("?")`, "caf?"},
+		{`("caf" + "😊".byteslice(0, 2)).scrub("?")`, "caf??"},
+		{`"café".scrub`, "café"},
+		{`"é".b.scrub.bytesize`, 2},
+		{`"😊".byteslice(0, 2).length`, 2},
+		{`("a" + "😊".byteslice(0, 2) + "b")[3]`, "b"},
+		{`"😊"[2]`, nil},
+		{`"Hello"[3..1]`, ""},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringUnicodeMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"é".unicode_normalize`, "é"},
+		{`"é".unicode_normalize.length`, 1},
+		{`"é".unicode_normalize("nfd")`, "é"},
+		{`"é".unicode_normalize("nfd").length`, 2},
+		{`"ﬁ".unicode_normalize("nfkc")`, "fi"},
+		{`"ﬁ".unicode_normalize("nfc")`, "ﬁ"},
+		{`"é".unicode_normalize("nfkd").bytesize`, 3},
+		{`"é".unicode_normalized?`, true},
+		{`"é".unicode_normalized?`, false},
+		{`"é".unicode_normalized?("nfd")`, true},
+		{`"Goby".casecmp("GOBY")`, 0},
+		{`"a".casecmp("B")`, -1},
+		{`"b".casecmp("A")`, 1},
+		{`"Straße".casecmp("STRASSE")`, 0},
+		{`"Goby".casecmp?("gOBY")`, true},
+		{`"Straße".casecmp?("strasse")`, true},
+		{`"ΣΑΣ".casecmp?("σας")`, true},
+		{`"Goby".casecmp?("Ruby")`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringEncodingMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"a".bytesize(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`"a".byteslice`, "ArgumentError: Expect 1 to 2 argument(s). got: 0", 1},
+		{`"a".byteslice("1")`, "TypeError: Expect argument #1 to be Integer or Range. got: String", 1},
+		{`"a".byteslice(1, "1")`, "TypeError: Expect argument #2 to be Integer. got: String", 1},
+		{`"a".byteslice(1..2, 1)`, "ArgumentError: Expect 1 argument(s). got: 2", 1},
+		{`"a".force_encoding("Shift_JIS")`, "ArgumentError: Unknown encoding name - Shift_JIS", 1},
+		{`"a".force_encoding(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"😊".byteslice(0, 2).codepoints`, "ArgumentError: Invalid byte sequence in UTF-8", 1},
+		{`"a".unicode_normalize("nfx")`, "ArgumentError: Unknown normalization form - nfx", 1},
+		{`"a".unicode_normalize(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"a".b.unicode_normalize`, "ArgumentError: Can't normalize a ASCII-8BIT string", 1},
+		{`"a".casecmp(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"a".casecmp?`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`"a".scrub(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,101 @@
+package vm
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Tempfile creates files with random names for scratch data, which are removed automatically
+// when they're created with a block.
+//
+// ```ruby
+// require "tempfile"
+//
+// Tempfile.create("report") do |f|
+//   f.write("scratch")
+//   f.name # => "/tmp/report123456789"
+// end # the file is closed and removed here
+//
+// f = Tempfile.create("data*.csv") # => <File: /tmp/data123456789.csv>, which should be removed by the caller
+// ```
+//
+// - `Tempfile.new` is not supported.
+
+// Class methods --------------------------------------------------------
+var builtinTempfileClassMethods = []*BuiltinMethodObject{
+	{
+		// Creates a new file with a random name in the temporary directory, or in the given directory,
+		// and opens it for reading and writing. The name starts with the prefix, or the prefix's last "*"
+		// is replaced by the random part, so that a suffix like an extension can be kept.
+		// If a block is given, the file is yielded instead, and closed and removed after the block is executed.
+		// The block's result is returned.
+		//
+		// ```ruby
+		// Tempfile.create("upload", "/var/tmp") do |f|
+		//   f.puts("Hello")
+		//   f.rewind
+		//   f.read # => "Hello\n"
+		// end
+		// ```
+		//
+		// @param prefix [String]
+		// @param dir [String]
+		// @return [File]
+		Name: "create",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			prefix, dir, errObj := tempArguments(t, args, sourceLine)
+			var file *os.File
+
+			if errObj == nil {
+				var err error
+				file, err = ioutil.TempFile(dir, prefix)
+
+				if err != nil {
+					errObj = t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+				}
+			}
+
+			if errObj != nil {
+				if blockFrame != nil {
+					t.callFrameStack.pop()
+				}
+
+				return errObj
+			}
+
+			f := t.vm.initFileObject(file)
+
+			if blockFrame == nil {
+				return f
+			}
+
+			// Closes and removes the file even if the block raises an error
+			defer func() {
+				f.close()
+				os.Remove(file.Name())
+			}()
+
+			return t.builtinMethodYield(blockFrame, f)
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initTempfileClass(vm *VM) {
+	class := vm.initializeClass("Tempfile")
+	class.setBuiltinMethods(builtinTempfileClassMethods, true)
+	vm.objectClass.setClassConstant(class)
+}
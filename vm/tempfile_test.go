@@ -0,0 +1,83 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestTempfileCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "tempfile"
+		f = Tempfile.create("report", "/tmp/goby")
+		f.write("Hello")
+		f.close
+		result = [File.split(f.name)[0], File.basename(f.name).start_with("report"), File.new(f.name).read]
+		File.delete(f.name)
+		result
+		`, []interface{}{"/tmp/goby/", true, "Hello"}},
+		{`
+		require "tempfile"
+		f = Tempfile.create("data*.csv", "/tmp/goby")
+		f.close
+		File.delete(f.name)
+		File.extname(f.name)
+		`, ".csv"},
+		{`
+		require "tempfile"
+		path = nil
+		result = Tempfile.create("scratch", "/tmp/goby") do |f|
+		  path = f.name
+		  f.puts("Hello")
+		  f.rewind
+		  f.read
+		end
+		[result, File.exist?(path)]
+		`, []interface{}{"Hello\n", false}},
+		{`
+		require "tempfile"
+		Tempfile.create do |f|
+		  f.name.start_with(Dir.tmpdir)
+		end
+		`, true},
+		{`
+		require "tempfile"
+		path = nil
+		Tempfile.create("closed", "/tmp/goby") do |f|
+		  path = f.name
+		  f.close
+		end
+		File.exist?(path)
+		`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTempfileCreateFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "tempfile";Tempfile.new`, "NoMethodError: Undefined Method 'new' for Tempfile", 1},
+		{`require "tempfile";Tempfile.create(1)`, "TypeError: Expect argument #1 to be String. got: Integer", 1},
+		{`require "tempfile";Tempfile.create("a", 1) do |f| end`, "TypeError: Expect argument #2 to be String. got: Integer", 1},
+		{`require "tempfile";Tempfile.create("a", "b", "c")`, "ArgumentError: Expect 2 or less argument(s). got: 3", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
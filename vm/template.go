@@ -0,0 +1,737 @@
+package vm
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goby-lang/goby/compiler"
+	"github.com/goby-lang/goby/compiler/parser"
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Template renders ERB-like templates into Strings. A template is compiled into Goby bytecode once,
+// and its code runs every time it's rendered with the locals given as a Hash.
+//
+// - `<%= expr %>` outputs the HTML escaped value of the expression.
+// - `<%== expr %>` outputs the value without escaping.
+// - `<% code %>` runs the code, like `if`, `each` or assignments.
+// - `<%# comment %>` is ignored, and `<%%` outputs `<%`.
+// - `<%-` strips the indentation before the tag, and `-%>` strips the line break after it.
+//
+// ```ruby
+// require "template"
+//
+// t = Template.new("<h1><%= title %></h1>\n<% items.each do |item| %>- <%= item %>\n<% end %>")
+// t.render({ title: "<Goby>", items: [1, 2] })
+// # => "<h1>&lt;Goby&gt;</h1>\n- 1\n- 2\n"
+// ```
+//
+// Templates can render other templates as partials with `render`, and be rendered inside a layout,
+// which outputs the rendered template with `content`:
+//
+// ```ruby
+// # views/layout.html: <html><body><%= content %></body></html>
+// # views/index.html:  <% users.each do |user| %><%= render("_user.html", { user: user }) %><% end %>
+// # views/_user.html:  <p><%= user %></p>
+//
+// Template.file("views/index.html").render({ users: ["Stan"] }, { layout: "layout.html" })
+// # => "<html><body><p>Stan</p></body></html>"
+// ```
+//
+// The paths of partials and layouts are relative to the directory of the template that renders them.
+// Template files are cached and only compiled again when they're modified.
+//
+// The templates run on `Template::Context` objects, which provide the helpers below:
+//
+// - `content`: the rendered template in a layout.
+// - `h(obj)`: the HTML escaped String of the object.
+// - `locals`: the Hash of the locals.
+// - `raw(obj)`: the String of the object that won't be escaped by `<%= %>`.
+// - `render(template, locals = {})`: the rendered partial, which won't be escaped by `<%= %>`.
+type TemplateObject struct {
+	*BaseObj
+	path    string
+	src     string
+	modTime time.Time
+	// programs are the compiled templates, keyed by the names of their locals
+	programs map[string]*instructionSet
+	mutex    sync.Mutex
+}
+
+// TemplateContextObject is the `self` of a template while it's being rendered
+type TemplateContextObject struct {
+	*BaseObj
+	template *TemplateObject
+	locals   *HashObject
+	content  Object
+	// safe are the Strings that aren't escaped by `<%= %>`, shared by the partials and the layout of a rendering
+	safe map[*StringObject]bool
+}
+
+// templateBuffer is the local variable collecting the output of a template. It's always the first local of the program.
+const templateBuffer = "_buf"
+
+// templateLocalName matches the locals that can be passed to templates
+var templateLocalName = regexp.MustCompile(`^[a-z_][a-zA-Z0-9_]*$`)
+
+// Class methods --------------------------------------------------------
+var builtinTemplateClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the template of the file, which is cached until the file is modified.
+		//
+		// ```ruby
+		// Template.file("views/index.html").render({ title: "Home" })
+		// ```
+		//
+		// @param path [String]
+		// @return [Template]
+		Name: "file",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			tmpl, err := t.vm.templateFile(args[0].(*StringObject).value, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return tmpl
+
+		},
+	},
+	{
+		// Compiles the template String. Raises `Template::SyntaxError` if a tag isn't closed or the code is invalid.
+		//
+		// ```ruby
+		// t = Template.new("Hello, <%= name %>!")
+		// t.render({ name: "Goby" }) # => "Hello, Goby!"
+		// ```
+		//
+		// @param source [String]
+		// @return [Template]
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			tmpl, err := t.vm.initTemplateObject(receiver.(*RClass), args[0].(*StringObject).value, "", sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			return tmpl
+
+		},
+	},
+}
+
+// Instance methods -----------------------------------------------------
+var builtinTemplateInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Returns the path of the template file, or nil if the template was created from a String.
+		//
+		// @return [String]
+		Name: "path",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			tmpl := receiver.(*TemplateObject)
+
+			if tmpl.path == "" {
+				return NULL
+			}
+
+			return t.vm.InitStringObject(tmpl.path)
+
+		},
+	},
+	{
+		// Renders the template with the locals. The keys of the Hash become the local variables of the template.
+		// With the `layout` option, which is a path or a Template, the rendered template is rendered again inside the layout.
+		//
+		// ```ruby
+		// t = Template.new("<p><%= body %></p>")
+		// t.render({ body: "Hi" })                                # => "<p>Hi</p>"
+		// t.render({ body: "Hi" }, { layout: "views/layout.html" })
+		// ```
+		//
+		// @param locals [Hash], options [Hash]
+		// @return [String]
+		Name: "render",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentLess, 2, len(args))
+			}
+
+			tmpl := receiver.(*TemplateObject)
+			locals, err := templateLocalsOf(t, args, 0, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			var layout *TemplateObject
+
+			if len(args) == 2 {
+				options, ok := args[1].(*HashObject)
+
+				if !ok {
+					return t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, 2, classes.HashClass, args[1].Class().Name)
+				}
+
+				for _, key := range options.sortedKeys() {
+					if key != "layout" {
+						return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.UnknownOption, key)
+					}
+
+					layout, err = tmpl.lookup(t, options.Pairs[key], sourceLine)
+
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			safe := map[*StringObject]bool{}
+			result := t.renderTemplate(tmpl, locals, nil, safe, sourceLine)
+
+			if layout == nil {
+				return result
+			}
+
+			if s, ok := result.(*StringObject); ok {
+				safe[s] = true
+				return t.renderTemplate(layout, locals, s, safe, sourceLine)
+			}
+
+			return result
+
+		},
+	},
+	{
+		// Returns the Goby source code the template is compiled into, which is useful for debugging.
+		//
+		// ```ruby
+		// Template.new("Hi <%= name %>").src # => "_buf = []; _buf.push(\"Hi \"); _buf.push(h(name))"
+		// ```
+		//
+		// @return [String]
+		Name: "src",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return t.vm.InitStringObject(templateBuffer + " = []" + receiver.(*TemplateObject).src)
+
+		},
+	},
+}
+
+// Instance methods of the contexts ---------------------------------------
+var builtinTemplateContextClassMethods = []*BuiltinMethodObject{
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+}
+
+var builtinTemplateContextInstanceMethods = []*BuiltinMethodObject{
+	{
+		// Returns the rendered template in a layout, or nil outside of layouts.
+		//
+		// ```ruby
+		// <html><body><%= content %></body></html>
+		// ```
+		//
+		// @return [String]
+		Name: "content",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			ctx := receiver.(*TemplateContextObject)
+
+			if ctx.content == nil {
+				return NULL
+			}
+
+			return ctx.content
+
+		},
+	},
+	{
+		// Returns the HTML escaped String of the object, which is what `<%= %>` outputs.
+		//
+		// ```ruby
+		// <%== h(title) %>
+		// ```
+		//
+		// @param object [Object]
+		// @return [String]
+		Name: "h",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			ctx := receiver.(*TemplateContextObject)
+
+			if s, ok := args[0].(*StringObject); ok && ctx.safe[s] {
+				return s
+			}
+
+			// The escaped String isn't escaped again by `<%= %>`
+			s := t.vm.InitStringObject(html.EscapeString(args[0].ToString()))
+			ctx.safe[s] = true
+			return s
+
+		},
+	},
+	{
+		// Returns the locals of the template.
+		//
+		// ```ruby
+		// <% if locals.has_key?("title") %><h1><%= title %></h1><% end %>
+		// ```
+		//
+		// @return [Hash]
+		Name: "locals",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 0 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 0, len(args))
+			}
+
+			return receiver.(*TemplateContextObject).locals
+
+		},
+	},
+	{
+		// Returns the String of the object, which won't be escaped by `<%= %>`.
+		//
+		// ```ruby
+		// <%= raw(markdown_html) %>
+		// ```
+		//
+		// @param object [Object]
+		// @return [String]
+		Name: "raw",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			// Marks a copy, so the String itself is still escaped elsewhere
+			s := t.vm.InitStringObject(args[0].ToString())
+			receiver.(*TemplateContextObject).safe[s] = true
+			return s
+
+		},
+	},
+	{
+		// Renders the partial, which is a path relative to the directory of the current template or a Template,
+		// with the locals. The result won't be escaped by `<%= %>`.
+		//
+		// ```ruby
+		// <% users.each do |user| %><%= render("_user.html", { user: user }) %><% end %>
+		// ```
+		//
+		// @param template [String, Template], locals [Hash]
+		// @return [String]
+		Name: "render",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) < 1 || len(args) > 2 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgumentRange, 1, 2, len(args))
+			}
+
+			ctx := receiver.(*TemplateContextObject)
+			partial, err := ctx.template.lookup(t, args[0], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			locals, err := templateLocalsOf(t, args, 1, sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			result := t.renderTemplate(partial, locals, nil, ctx.safe, sourceLine)
+
+			if s, ok := result.(*StringObject); ok {
+				ctx.safe[s] = true
+			}
+
+			return result
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initTemplateClass(vm *VM) {
+	class := vm.initializeClass(classes.TemplateClass)
+	class.setBuiltinMethods(builtinTemplateClassMethods, true)
+	class.setBuiltinMethods(builtinTemplateInstanceMethods, false)
+
+	context := vm.initializeClass("Context")
+	context.setBuiltinMethods(builtinTemplateContextClassMethods, true)
+	context.setBuiltinMethods(builtinTemplateContextInstanceMethods, false)
+	class.setClassConstant(context)
+
+	class.setClassConstant(vm.initializeClass("SyntaxError"))
+	vm.objectClass.setClassConstant(class)
+
+	vm.templatesMutex.Lock()
+	if vm.templates == nil {
+		vm.templates = map[string]*TemplateObject{}
+	}
+	vm.templatesMutex.Unlock()
+}
+
+// initTemplateObject compiles the template to check its syntax
+func (vm *VM) initTemplateObject(class *RClass, source, path string, sourceLine int) (*TemplateObject, *Error) {
+	src, err := compileTemplate(source)
+
+	if err != nil {
+		return nil, vm.InitErrorObject(errors.TemplateSyntaxError, sourceLine, "%s", err.Error())
+	}
+
+	tmpl := &TemplateObject{
+		BaseObj:  NewBaseObject(class),
+		path:     path,
+		src:      src,
+		programs: map[string]*instructionSet{},
+	}
+
+	if _, err := tmpl.program(vm, []string{}, sourceLine); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ToString returns the path of the template file
+func (tmpl *TemplateObject) ToString() string {
+	if tmpl.path == "" {
+		return "#<Template>"
+	}
+
+	return "#<Template: " + tmpl.path + ">"
+}
+
+// Inspect delegates to ToString
+func (tmpl *TemplateObject) Inspect() string {
+	return tmpl.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (tmpl *TemplateObject) ToJSON(t *Thread) string {
+	return jsonString(tmpl.ToString())
+}
+
+// Value returns the compiled Goby source code
+func (tmpl *TemplateObject) Value() interface{} {
+	return tmpl.src
+}
+
+// ToString returns the name of the class
+func (ctx *TemplateContextObject) ToString() string {
+	return "#<Template::Context>"
+}
+
+// Inspect delegates to ToString
+func (ctx *TemplateContextObject) Inspect() string {
+	return ctx.ToString()
+}
+
+// ToJSON returns the quoted ToString
+func (ctx *TemplateContextObject) ToJSON(t *Thread) string {
+	return jsonString(ctx.ToString())
+}
+
+// Value returns the template
+func (ctx *TemplateContextObject) Value() interface{} {
+	return ctx.template
+}
+
+// Other helper functions -----------------------------------------------
+
+// compileTemplate turns the template into Goby statements appending to the buffer. The statements are joined with
+// semicolons, and the line breaks of the template are moved between them, so the lines of the code are the lines of the template.
+func compileTemplate(source string) (string, error) {
+	var b strings.Builder
+	// newlines are the line breaks removed from the template, which are added before the next statement
+	newlines := 0
+	line := 1
+
+	emit := func(stmt string) {
+		b.WriteString("; " + strings.Repeat("\n", newlines) + stmt)
+		line += newlines + strings.Count(stmt, "\n")
+		newlines = 0
+	}
+
+	text := ""
+	emitText := func() {
+		if text != "" {
+			emit(templateBuffer + ".push(\"" + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text) + "\")")
+			newlines += strings.Count(text, "\n")
+			text = ""
+		}
+	}
+
+	for {
+		i := strings.Index(source, "<%")
+
+		if i < 0 {
+			text += source
+			break
+		}
+
+		text += source[:i]
+		source = source[i+2:]
+
+		if strings.HasPrefix(source, "%") {
+			text += "<%"
+			source = source[1:]
+			continue
+		}
+
+		j := strings.Index(source, "%>")
+
+		if j < 0 {
+			return "", fmt.Errorf("unclosed tag at line %d", line+newlines+strings.Count(text, "\n"))
+		}
+
+		tag := source[:j]
+		source = source[j+2:]
+
+		if strings.HasPrefix(tag, "-") {
+			tag = tag[1:]
+			// Strips the indentation only if the tag is the first thing on its line
+			trimmed := strings.TrimRight(text, " \t")
+
+			if trimmed == "" || strings.HasSuffix(trimmed, "\n") {
+				text = trimmed
+			}
+		}
+
+		emitText()
+
+		if strings.HasSuffix(tag, "-") {
+			tag = tag[:len(tag)-1]
+
+			if strings.HasPrefix(source, "\r\n") {
+				source = source[2:]
+				newlines++
+			} else if strings.HasPrefix(source, "\n") {
+				source = source[1:]
+				newlines++
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(tag, "#"):
+			newlines += strings.Count(tag, "\n")
+		case strings.HasPrefix(tag, "=="):
+			if code := strings.TrimSpace(tag[2:]); code != "" {
+				emit(templateBuffer + ".push(raw(" + code + "))")
+			}
+		case strings.HasPrefix(tag, "="):
+			if code := strings.TrimSpace(tag[1:]); code != "" {
+				emit(templateBuffer + ".push(h(" + code + "))")
+			}
+		default:
+			if code := strings.TrimSpace(tag); code != "" {
+				emit(code)
+			} else {
+				newlines += strings.Count(tag, "\n")
+			}
+		}
+	}
+
+	emitText()
+	return b.String(), nil
+}
+
+// program returns the template compiled with the locals, which are assigned at the beginning of the first line
+func (tmpl *TemplateObject) program(vm *VM, names []string, sourceLine int) (*instructionSet, *Error) {
+	key := strings.Join(names, ",")
+
+	tmpl.mutex.Lock()
+	defer tmpl.mutex.Unlock()
+
+	if program, ok := tmpl.programs[key]; ok {
+		return program, nil
+	}
+
+	prologue := templateBuffer + " = []"
+
+	for _, name := range names {
+		prologue += "; " + name + " = locals[\"" + name + "\"]"
+	}
+
+	sets, err := compiler.CompileToInstructions(prologue+tmpl.src, parser.NormalMode)
+
+	if err != nil {
+		return nil, vm.InitErrorObject(errors.TemplateSyntaxError, sourceLine, "%s", err.Error())
+	}
+
+	translator := newInstructionTranslator(tmpl.filename())
+	translator.vm = vm
+	translator.transferInstructionSets(sets)
+
+	tmpl.programs[key] = translator.program
+	return translator.program, nil
+}
+
+func (tmpl *TemplateObject) filename() filename {
+	if tmpl.path == "" {
+		return "(template)"
+	}
+
+	return tmpl.path
+}
+
+// lookup returns the Template, or the template of the path relative to the directory of the template file
+func (tmpl *TemplateObject) lookup(t *Thread, obj Object, sourceLine int) (*TemplateObject, *Error) {
+	switch obj := obj.(type) {
+	case *TemplateObject:
+		return obj, nil
+	case *StringObject:
+		path := obj.value
+
+		if tmpl.path != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(tmpl.path), path)
+		}
+
+		return t.vm.templateFile(path, sourceLine)
+	default:
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormat, classes.StringClass, obj.Class().Name)
+	}
+}
+
+// templateFile returns the cached template of the file, and compiles the file again if it's been modified
+func (vm *VM) templateFile(path string, sourceLine int) (*TemplateObject, *Error) {
+	abs, err := filepath.Abs(path)
+
+	if err != nil {
+		return nil, vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	fs, err := os.Stat(abs)
+
+	if err != nil {
+		return nil, vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	vm.templatesMutex.Lock()
+	defer vm.templatesMutex.Unlock()
+
+	if tmpl, ok := vm.templates[abs]; ok && tmpl.modTime.Equal(fs.ModTime()) {
+		return tmpl, nil
+	}
+
+	source, err := ioutil.ReadFile(abs)
+
+	if err != nil {
+		return nil, vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+	}
+
+	tmpl, e := vm.initTemplateObject(vm.objectClass.getClassConstant(classes.TemplateClass), string(source), path, sourceLine)
+
+	if e != nil {
+		return nil, e
+	}
+
+	tmpl.modTime = fs.ModTime()
+	vm.templates[abs] = tmpl
+	return tmpl, nil
+}
+
+// templateLocalsOf returns the optional Hash of the locals at the index of the arguments
+func templateLocalsOf(t *Thread, args []Object, index, sourceLine int) (*HashObject, *Error) {
+	if len(args) <= index {
+		return t.vm.InitHashObject(map[string]Object{}), nil
+	}
+
+	locals, ok := args[index].(*HashObject)
+
+	if !ok {
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.WrongArgumentTypeFormatNum, index+1, classes.HashClass, args[index].Class().Name)
+	}
+
+	for name := range locals.Pairs {
+		if !templateLocalName.MatchString(name) || name == templateBuffer {
+			return nil, t.vm.InitErrorObject(errors.ArgumentError, sourceLine, "%q can't be a local variable of templates", name)
+		}
+	}
+
+	return locals, nil
+}
+
+// renderTemplate runs the template on a new context and returns the output
+func (t *Thread) renderTemplate(tmpl *TemplateObject, locals *HashObject, content Object, safe map[*StringObject]bool, sourceLine int) Object {
+	program, err := tmpl.program(t.vm, locals.sortedKeys(), sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	ctx := &TemplateContextObject{
+		BaseObj:  NewBaseObject(tmpl.Class().getClassConstant("Context")),
+		template: tmpl,
+		locals:   locals,
+		content:  content,
+		safe:     safe,
+	}
+
+	cf := newNormalCallFrame(program, program.filename, 1)
+	cf.self = ctx
+	t.callFrameStack.push(cf)
+	t.startFromTopFrame()
+
+	var b strings.Builder
+
+	for _, elem := range cf.locals[0].Target.(*ArrayObject).Elements {
+		b.WriteString(elem.ToString())
+	}
+
+	return t.vm.InitStringObject(b.String())
+}
@@ -0,0 +1,194 @@
+package vm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTemplateRender(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`require "template";Template.new("Hello, <%= name %>!").render({ name: "Goby" })`, "Hello, Goby!"},
+		{`require "template";Template.new("plain text").render`, "plain text"},
+		{`require "template";Template.new("").render`, ""},
+		{`require "template";Template.new("<%= x %>").render({ x: "<a href=\"/\">Tom & Jerry</a>" })`, "&lt;a href=&#34;/&#34;&gt;Tom &amp; Jerry&lt;/a&gt;"},
+		{`require "template";Template.new("<%== x %>").render({ x: "<b>" })`, "<b>"},
+		{`require "template";Template.new("<%= raw(x) %><%= h(x) %>").render({ x: "<b>" })`, "<b>&lt;b&gt;"},
+		{`require "template";Template.new("<%= 1 + 2 %>|<%= nil %>|<%= [1, 2] %>").render`, "3||[1, 2]"},
+		{`require "template";Template.new("<%# comment %>a<%% b %>").render`, "a<% b %>"},
+		{`require "template";Template.new("\"quoted\" \\ <%= x %>").render({ x: 1 })`, "\"quoted\" \\ 1"},
+		{`
+		require "template"
+		t = Template.new("<% items.each do |item| %>- <%= item %>\n<% end %>")
+		t.render({ items: [1, 2, 3] })
+		`, "- 1\n- 2\n- 3\n"},
+		{`
+		require "template"
+		t = Template.new("<% if admin %>admin<% else %>guest<% end %>")
+		t.render({ admin: true }) + t.render({ admin: false })
+		`, "adminguest"},
+		{`
+		require "template"
+		t = Template.new("<ul>\n  <%- items.each do |item| -%>\n  <li><%= item %></li>\n  <%- end -%>\n</ul>")
+		t.render({ items: ["a", "b"] })
+		`, "<ul>\n  <li>a</li>\n  <li>b</li>\n</ul>"},
+		{`
+		require "template"
+		t = Template.new("<% total = a + b %><%= total * 2 %>")
+		t.render({ a: 1, b: 2 })
+		`, "6"},
+		{`
+		require "template"
+		t = Template.new("<%= x %>")
+		t.render({ x: 1 }) + t.render({ x: 2, y: 3 }) + t.render({ x: 4 })
+		`, "124"},
+		{`
+		require "template"
+		Template.new("<%= locals.keys.sort.join(',') %>").render({ b: 1, a: 2 })
+		`, "a,b"},
+		{`
+		require "template"
+		Template.new("Hi <%= name %>").src
+		`, "_buf = []; _buf.push(\"Hi \"); _buf.push(h(name))"},
+		{`require "template";Template.new("").path`, nil},
+		{`require "template";Template.new("").inspect`, "#<Template>"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTemplatePartialsAndLayouts(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "template"
+		Template.file("../test_fixtures/template_test/index.html").render({ users: ["Stan", "<b>"] })
+		`, "<p>Stan</p>\n<p>&lt;b&gt;</p>\n"},
+		{`
+		require "template"
+		t = Template.file("../test_fixtures/template_test/index.html")
+		t.render({ users: ["Stan"] }, { layout: "layout.html" })
+		`, "<html><body><p>Stan</p>\n</body></html>\n"},
+		{`
+		require "template"
+		layout = Template.new("[<%= content %>]")
+		Template.new("<%= x %>").render({ x: "<i>" }, { layout: layout })
+		`, "[&lt;i&gt;]"},
+		{`
+		require "template"
+		partial = Template.new("<%= x %>")
+		Template.new("<%= render(partial, { x: x }) %>").render({ x: "<i>", partial: partial })
+		`, "&lt;i&gt;"},
+		{`
+		require "template"
+		partial = Template.new("<%= x %>")
+		Template.new("<%= render(partial, { x: raw(x) }) %>").render({ x: "<i>", partial: partial })
+		`, "<i>"},
+		{`
+		require "template"
+		Template.new("<%= content %>").render
+		`, ""},
+		{`
+		require "template"
+		Template.file("../test_fixtures/template_test/layout.html").path
+		`, "../test_fixtures/template_test/layout.html"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTemplateFileCache(t *testing.T) {
+	setup()
+	defer teardown()
+
+	path := "/tmp/goby/template.html"
+
+	if err := ioutil.WriteFile(path, []byte("v1 <%= x %>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	v := initTestVM()
+	v.testEval(t, `require "template"`, getFilename())
+
+	first := v.testEval(t, `Template.file("/tmp/goby/template.html")`, getFilename())
+	VerifyExpected(t, 0, v.testEval(t, `Template.file("/tmp/goby/template.html").render({ x: 1 })`, getFilename()), "v1 1")
+
+	if second := v.testEval(t, `Template.file("/tmp/goby/template.html")`, getFilename()); second != first {
+		t.Fatal("Expect the unmodified template file to be cached")
+	}
+
+	if err := ioutil.WriteFile(path, []byte("v2 <%= x %>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Now().Add(time.Second)
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	VerifyExpected(t, 1, v.testEval(t, `Template.file("/tmp/goby/template.html").render({ x: 1 })`, getFilename()), "v2 1")
+}
+
+func TestTemplateRenderFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "template";Template.new`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`require "template";Template.new(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "template";Template.new("a <%= x")`, "Template::SyntaxError: unclosed tag at line 1", 1},
+		{`require "template";Template.new("a\nb\n<% if x")`, "Template::SyntaxError: unclosed tag at line 3", 1},
+		{`require "template";Template.new("<%= x( %>")`, "Template::SyntaxError: expected next token to be ), got EOF() instead. Line: 0", 1},
+		{`require "template";Template.file("/tmp/goby/not_existing")`, "IOError: stat /tmp/goby/not_existing: no such file or directory", 1},
+		{`require "template";Template.new("").render(1)`, "TypeError: Expect argument #1 to be Hash. got: Integer", 1},
+		{`require "template";Template.new("").render({ Name: 1 })`, "ArgumentError: \"Name\" can't be a local variable of templates", 1},
+		{`require "template";Template.new("").render({ _buf: 1 })`, "ArgumentError: \"_buf\" can't be a local variable of templates", 1},
+		{`require "template";Template.new("").render({}, { style: 1 })`, "ArgumentError: Unknown option - style", 1},
+		{`require "template";Template.new("").render({}, { layout: 1 })`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "template";Template.new("").render({}, 1)`, "TypeError: Expect argument #2 to be Hash. got: Integer", 1},
+		{`require "template";Template::Context.new`, "NoMethodError: Undefined Method 'new' for Context", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTemplateRenderErrorFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		// The frames of the templates are left on the stack with the error
+		{`require "template";Template.new("<%= nope %>").render`, "NoMethodError: Undefined Method 'nope' for #<Template::Context>", 3},
+		{`
+		require "template"
+		partial = Template.new("<%= 1 / 0 %>")
+		Template.new("<%= render(partial) %>").render({ partial: partial })
+		`, "ZeroDivisionError: Divided by 0", 5},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+	}
+}
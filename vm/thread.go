@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
-	"strings"
 
 	"github.com/goby-lang/goby/compiler"
 	"github.com/goby-lang/goby/compiler/bytecode"
@@ -14,6 +13,23 @@ import (
 
 const mainThreadID = 0
 
+// threadSafeObject is implemented by BaseObj, so every object can be made thread-safe via `thread_safe!`
+type threadSafeObject interface {
+	threadSafeLock() *reentrantLock
+	makeThreadSafe()
+}
+
+// isImmutableSingleton reports whether the object is `nil`, `true` or `false`, which are shared by every thread
+// and never locked by `thread_safe!`
+func isImmutableSingleton(obj Object) bool {
+	switch obj.(type) {
+	case *NullObject, *BooleanObject:
+		return true
+	default:
+		return false
+	}
+}
+
 // Thread is the context needed for a single thread of execution
 type Thread struct {
 	// a stack that holds call frames
@@ -29,6 +45,9 @@ type Thread struct {
 	id int64
 
 	vm *VM
+
+	// lastStatus is the status of the last child process waited in the thread, which is returned by `Process.last_status`
+	lastStatus *ProcessStatusObject
 }
 
 // VM returns the vm of the thread
@@ -40,46 +59,6 @@ func (t *Thread) isMainThread() bool {
 	return t.id == mainThreadID
 }
 
-func (t *Thread) getBlock(name string, filename filename) *instructionSet {
-	// The "name" here is actually an index of block
-	// for example <Block:1>'s name is "1"
-	is, ok := t.vm.blockTables[filename][name]
-
-	if !ok {
-		panic(fmt.Sprintf("Can't find block %s", name))
-	}
-
-	return is
-}
-
-func (t *Thread) getMethodIS(name string, filename filename) (*instructionSet, bool) {
-	iss, ok := t.vm.isTables[bytecode.MethodDef][name]
-
-	if !ok {
-		return nil, false
-	}
-
-	is := iss[t.vm.methodISIndexTables[filename].Data[name]]
-
-	t.vm.methodISIndexTables[filename].Data[name]++
-
-	return is, ok
-}
-
-func (t *Thread) getClassIS(name string, filename filename) *instructionSet {
-	iss, ok := t.vm.isTables[bytecode.ClassDef][name]
-
-	if !ok {
-		panic(fmt.Sprintf("Can't find class %s's instructions", name))
-	}
-
-	is := iss[t.vm.classISIndexTables[filename].Data[name]]
-
-	t.vm.classISIndexTables[filename].Data[name]++
-
-	return is
-}
-
 func (t *Thread) execGobyLib(libName string) (err error) {
 	libPath := filepath.Join(t.vm.libPath, libName)
 	err = t.execFile(libPath)
@@ -99,26 +78,47 @@ func (t *Thread) execFile(fpath string) (err error) {
 		return
 	}
 
-	oldMethodTable := isTable{}
-	oldClassTable := isTable{}
+	t.execInstructions(instructionSets, fpath)
+	return
+}
 
-	// Copy current file's instruction sets.
-	for name, is := range t.vm.isTables[bytecode.MethodDef] {
-		oldMethodTable[name] = is
-	}
+// execInstructions translates the given instruction sets and evaluates them on the thread.
+// Every translation owns its instruction sets, so files can be loaded by different threads concurrently.
+func (t *Thread) execInstructions(sets []*bytecode.InstructionSet, fn string) {
+	translator := newInstructionTranslator(fn)
+	translator.vm = t.vm
+	translator.transferInstructionSets(sets)
 
-	for name, is := range t.vm.isTables[bytecode.ClassDef] {
-		oldClassTable[name] = is
-	}
+	cf := newNormalCallFrame(translator.program, translator.filename, 1)
+	cf.self = t.vm.mainObj
+	t.callFrameStack.push(cf)
 
-	// This creates new execution environments for required file, including new instruction set table.
-	// So we need to copy old instruction sets and restore them later, otherwise current program's instruction set would be overwrite.
-	t.vm.ExecInstructions(instructionSets, fpath)
+	// here is the final destination of Goby errors at the VM level, and we don't deal with them at this point.
+	// we only decide how the user program should react to them.
+	// at vm level, we don't deal with the error itself
+	// we only decide how the program should react to it
+	defer func() {
+		switch err := recover().(type) {
+		// if the error is a true Go panic, Goby can't handle it properly and we should re-raise it again
+		// it means Goby can't handle it properly and we should just let it crash
+		case error:
+			panic(err)
+
+			// if the error is one of the Goby's errors, such as argument error, we need to handle it depending on the mode of execution.
+			// we need to handle it depends on the type of program execution
+		case *Error:
+
+			// REPLMode: We handle the error inside the igb package, so don't need to do anything here
+			// TestMode: We should preserve the vm as it is and inspect its state via test helpers, so don't need to do anything here either
+			// NormalMode (normal file execution): we should print our the error and exit the program
+			if t.vm.mode == parser.NormalMode {
+				fmt.Fprintln(t.vm.stderr, err.Message())
+				t.vm.exit(1)
+			}
+		}
+	}()
 
-	// Restore instruction sets.
-	t.vm.isTables[bytecode.MethodDef] = oldMethodTable
-	t.vm.isTables[bytecode.ClassDef] = oldClassTable
-	return
+	t.startFromTopFrame()
 }
 
 func (t *Thread) startFromTopFrame() {
@@ -275,19 +275,9 @@ func (t *Thread) builtinMethodYield(blockFrame *normalCallFrame, args ...Object)
 	return t.Stack.top().Target
 }
 
-func (t *Thread) retrieveBlock(fileName, blockFlag string, sourceLine int) (blockFrame *normalCallFrame) {
-	var blockName string
-	var hasBlock bool
-
+func (t *Thread) retrieveBlock(cf *normalCallFrame, blockFlag string) (blockFrame *normalCallFrame) {
 	if len(blockFlag) != 0 {
-		hasBlock = true
-		blockName = strings.Split(blockFlag, ":")[1]
-	}
-
-	if hasBlock {
-		block := t.getBlock(blockName, fileName)
-
-		c := newNormalCallFrame(block, fileName, sourceLine)
+		c := newNormalCallFrame(cf.definedSet(), cf.FileName(), cf.SourceLine())
 		c.isSourceBlock = true
 		c.isBlock = true
 		blockFrame = c
@@ -340,6 +330,26 @@ func (t *Thread) findAndCallMethod(receiver Object, methodName string, receiverP
 	}
 }
 
+// callMethod calls the receiver's method with the given arguments and returns the result
+func (t *Thread) callMethod(receiver Object, methodName string, sourceLine int, args ...Object) Object {
+	receiverPr := t.Stack.pointer
+	t.Stack.Push(&Pointer{Target: receiver})
+
+	for _, arg := range args {
+		t.Stack.Push(&Pointer{Target: arg})
+	}
+
+	var fileName string
+
+	// A thread created for running a block from Go has no call frames after the block returns
+	if cf := t.callFrameStack.top(); cf != nil {
+		fileName = cf.FileName()
+	}
+
+	t.findAndCallMethod(receiver, methodName, receiverPr, &bytecode.ArgSet{}, len(args), receiverPr+1, sourceLine, nil, fileName)
+	return t.Stack.Pop().Target
+}
+
 func (t *Thread) sendMethod(methodName string, argCount int, blockFrame *normalCallFrame, sourceLine int) {
 	if arr, ok := t.Stack.top().Target.(*ArrayObject); ok && arr.splat {
 		// Pop array
@@ -388,6 +398,14 @@ func (t *Thread) sendMethod(methodName string, argCount int, blockFrame *normalC
 func (t *Thread) evalBuiltinMethod(receiver Object, method *BuiltinMethodObject, receiverPtr, argCount int, argSet *bytecode.ArgSet, blockFrame *normalCallFrame, sourceLine int, fileName string) {
 	argPtr := receiverPtr + 1
 
+	// Objects marked by `thread_safe!` only allow one thread to call their builtin methods at a time
+	if obj, ok := receiver.(threadSafeObject); ok {
+		if lock := obj.threadSafeLock(); lock != nil {
+			lock.lock(t.id)
+			defer lock.unlock()
+		}
+	}
+
 	cf := newGoMethodCallFrame(
 		method.Fn,
 		receiver,
@@ -0,0 +1,232 @@
+package vm
+
+import (
+	"testing"
+)
+
+// The tests in this file run threaded Goby programs that share objects between threads.
+// They are meant to be run with the race detector:
+//
+//   go test -race -run ThreadSafe ./vm
+
+func TestThreadSafeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[].thread_safe?`, false},
+		{`[].thread_safe!.thread_safe?`, true},
+		{`{}.thread_safe!.thread_safe?`, true},
+		{`nil.thread_safe!.thread_safe?`, true},
+		{`true.thread_safe!.thread_safe?`, true},
+		{`false.thread_safe!`, false},
+		{`
+		a = [1, 2]
+		a.thread_safe!.object_id == a.object_id
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestThreadSafeMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[].thread_safe!(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+		{`[].thread_safe?(1)`, "ArgumentError: Expect 0 argument(s). got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestThreadSafeObjectsSharedBetweenThreads(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		a = [].thread_safe!
+		c = Channel.new
+
+		20.times do |i|
+		  thread do
+		    a.push(i)
+		    c.deliver(i)
+		  end
+		end
+
+		20.times do
+		  c.receive
+		end
+
+		a.length
+		`, 20},
+		{`
+		h = {}.thread_safe!
+		c = Channel.new
+
+		20.times do |i|
+		  thread do
+		    h[i.to_s] = i
+		    c.deliver(i)
+		  end
+		end
+
+		20.times do
+		  c.receive
+		end
+
+		h.length
+		`, 20},
+		{`
+		a = [1, 2, 3].thread_safe!
+		c = Channel.new
+
+		5.times do |i|
+		  thread do
+		    sum = 0
+		    a.each do |n|
+		      sum += a.first + n
+		    end
+		    a.push(sum)
+		    c.deliver(sum)
+		  end
+		end
+
+		5.times do
+		  c.receive
+		end
+
+		a.length
+		`, 8},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestThreadSafeInstanceVariablesSharedBetweenThreads(t *testing.T) {
+	code := `
+	class Counter
+	  def set(name, value)
+	    instance_variable_set(name, value)
+	  end
+	end
+
+	counter = Counter.new
+	c = Channel.new
+
+	20.times do |i|
+	  thread do
+	    counter.set("@v" + i.to_s, i)
+	    c.deliver(i)
+	  end
+	end
+
+	20.times do
+	  c.receive
+	end
+
+	counter.instance_variable_get("@v19")
+	`
+
+	v := initTestVM()
+	evaluated := v.testEval(t, code, getFilename())
+	VerifyExpected(t, 0, evaluated, 19)
+	v.checkCFP(t, 0, 0)
+	v.checkSP(t, 0, 1)
+}
+
+func TestThreadSafeDefinitions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		i = 0
+		while i < 3 do
+		  def foo
+		    10
+		  end
+		  i += 1
+		end
+		foo
+		`, 10},
+		{`
+		class Calculator
+		end
+
+		c = Channel.new
+
+		10.times do |i|
+		  thread do
+		    class Calculator
+		      def double(x)
+		        x * 2
+		      end
+		    end
+
+		    c.deliver(Calculator.new.double(i))
+		  end
+		end
+
+		sum = 0
+		10.times do
+		  sum += c.receive
+		end
+		sum
+		`, 90},
+		{`
+		c = Channel.new
+
+		thread do
+		  require_relative("../test_fixtures/require_test/foo")
+		  c.deliver(Foo.bar(5))
+		end
+
+		c.receive
+		`, 50},
+		{`
+		c = Channel.new
+
+		10.times do
+		  thread do
+		    require "concurrent/executor"
+		    c.deliver(Concurrent.object_id)
+		  end
+		end
+
+		ids = []
+		10.times do
+		  ids.push(c.receive)
+		end
+		ids.select do |id|
+		  id == ids.first
+		end.length
+		`, 10},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
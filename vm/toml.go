@@ -0,0 +1,251 @@
+package vm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// TOML parses TOML into Hashes, Arrays, Strings, Integers, Floats and Booleans, and dumps Hashes back out.
+// The implementation internally uses the pure-Go package `github.com/BurntSushi/toml`.
+//
+// ```ruby
+// require "toml"
+//
+// config = TOML.parse("title = \"blog\"\n\n[database]\nports = [5432, 5433]\n")
+// config["database"]["ports"] # => [5432, 5433]
+//
+// TOML.dump({ title: "blog", database: { port: 5432 } })
+// # => "title = \"blog\"\n\n[database]\n  port = 5432\n"
+// ```
+//
+// Dates and times are parsed as Strings. Since TOML has no null, nil can't be dumped.
+// Invalid TOML raises `TOML::SyntaxError` with the line number.
+//
+// - `TOML.new` is not supported.
+
+// Class methods --------------------------------------------------------
+var builtinTOMLClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the TOML of the Hash, whose keys are sorted.
+		//
+		// ```ruby
+		// TOML.dump({ name: "blog", tags: ["go", "ruby"] }) # => "name = \"blog\"\ntags = [\"go\", \"ruby\"]\n"
+		// ```
+		//
+		// @param hash [Hash]
+		// @return [String]
+		Name: "dump",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.HashClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			v, err := tomlValueOf(t, args[0], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			var b bytes.Buffer
+
+			if err := toml.NewEncoder(&b).Encode(v); err != nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			}
+
+			return t.vm.InitStringObject(b.String())
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Parses the TOML string into a Hash.
+		//
+		// ```ruby
+		// TOML.parse("[server]\nport = 8080\n") # => { server: { port: 8080 } }
+		// ```
+		//
+		// @param toml [String]
+		// @return [Hash]
+		Name: "parse",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			return t.parseTOML(args[0].(*StringObject).value, sourceLine)
+
+		},
+	},
+	{
+		// Parses the TOML file into a Hash.
+		//
+		// ```ruby
+		// config = TOML.parse_file("config/app.toml")
+		// ```
+		//
+		// @param path [String]
+		// @return [Hash]
+		Name: "parse_file",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			data, err := ioutil.ReadFile(args[0].(*StringObject).value)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.parseTOML(string(data), sourceLine)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initTOMLClass(vm *VM) {
+	class := vm.initializeClass("TOML")
+	class.setBuiltinMethods(builtinTOMLClassMethods, true)
+	class.setClassConstant(vm.initializeClass("SyntaxError"))
+	vm.objectClass.setClassConstant(class)
+}
+
+// Other helper functions -----------------------------------------------
+
+func (t *Thread) parseTOML(data string, sourceLine int) Object {
+	var v map[string]interface{}
+
+	if _, err := toml.Decode(data, &v); err != nil {
+		return t.vm.InitErrorObject(errors.TOMLSyntaxError, sourceLine, "%s", strings.TrimPrefix(err.Error(), "toml: "))
+	}
+
+	return t.vm.objectFromTOMLValue(v)
+}
+
+// objectFromTOMLValue converts the decoded value to an object
+func (vm *VM) objectFromTOMLValue(value interface{}) Object {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		pairs := map[string]Object{}
+
+		for k, elem := range v {
+			pairs[k] = vm.objectFromTOMLValue(elem)
+		}
+
+		return vm.InitHashObject(pairs)
+	case []map[string]interface{}:
+		elems := []Object{}
+
+		for _, elem := range v {
+			elems = append(elems, vm.objectFromTOMLValue(elem))
+		}
+
+		return vm.InitArrayObject(elems)
+	case []interface{}:
+		elems := []Object{}
+
+		for _, elem := range v {
+			elems = append(elems, vm.objectFromTOMLValue(elem))
+		}
+
+		return vm.InitArrayObject(elems)
+	case time.Time:
+		// Local dates and times have the locations named after their kinds
+		switch v.Location().String() {
+		case "date-local":
+			return vm.InitStringObject(v.Format("2006-01-02"))
+		case "time-local":
+			return vm.InitStringObject(v.Format("15:04:05.999999999"))
+		case "datetime-local":
+			return vm.InitStringObject(v.Format("2006-01-02T15:04:05.999999999"))
+		default:
+			return vm.InitStringObject(v.Format(time.RFC3339Nano))
+		}
+	default:
+		return vm.InitObjectFromGoType(value)
+	}
+}
+
+// tomlValueOf converts the object to a value that can be encoded. The encoder sorts the keys of maps.
+func tomlValueOf(t *Thread, obj Object, sourceLine int) (interface{}, *Error) {
+	switch obj := obj.(type) {
+	case *HashObject:
+		m := map[string]interface{}{}
+
+		for k, elem := range obj.Pairs {
+			v, err := tomlValueOf(t, elem, sourceLine)
+
+			if err != nil {
+				return nil, err
+			}
+
+			m[k] = v
+		}
+
+		return m, nil
+	case *ArrayObject:
+		elems := []interface{}{}
+
+		for _, elem := range obj.Elements {
+			v, err := tomlValueOf(t, elem, sourceLine)
+
+			if err != nil {
+				return nil, err
+			}
+
+			elems = append(elems, v)
+		}
+
+		return elems, nil
+	case *StringObject:
+		return obj.value, nil
+	case *IntegerObject:
+		if obj.bigValue == nil {
+			return int64(obj.value), nil
+		}
+
+		if obj.bigValue.IsInt64() {
+			return obj.bigValue.Int64(), nil
+		}
+	case *FloatObject:
+		return obj.value, nil
+	case *BooleanObject:
+		return obj.value, nil
+	}
+
+	return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.CantConvertTo, obj.Class().Name, "TOML")
+}
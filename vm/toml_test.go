@@ -0,0 +1,95 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestTOMLParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "toml"
+		config = TOML.parse("title = \"blog\"\n\n[database]\nports = [5432, 5433]\nenabled = true\nratio = 0.5\n")
+		[config["title"], config["database"]["ports"], config["database"]["enabled"], config["database"]["ratio"]]
+		`, []interface{}{"blog", []interface{}{5432, 5433}, true, 0.5}},
+		{`
+		require "toml"
+		config = TOML.parse("[[servers]]\nname = \"a\"\n\n[[servers]]\nname = \"b\"\n")
+		config["servers"].map do |s| s["name"] end
+		`, []interface{}{"a", "b"}},
+		{`
+		require "toml"
+		config = TOML.parse("point = { x = 1, y = 2 }\n[a.b]\nc = 3\n")
+		[config["point"]["y"], config["a"]["b"]["c"]]
+		`, []interface{}{2, 3}},
+		{`
+		require "toml"
+		config = TOML.parse("a = 1979-05-27T07:32:00Z\nb = 1979-05-27\nc = 07:32:00\nd = 1979-05-27T07:32:00\n")
+		[config["a"], config["b"], config["c"], config["d"]]
+		`, []interface{}{"1979-05-27T07:32:00Z", "1979-05-27", "07:32:00", "1979-05-27T07:32:00"}},
+		{`
+		require "toml"
+		TOML.parse("").length
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTOMLDump(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "toml"
+		TOML.dump({ title: "blog", tags: ["go", "ruby"], database: { port: 5432 } })
+		`, "tags = [\"go\", \"ruby\"]\ntitle = \"blog\"\n\n[database]\n  port = 5432\n"},
+		{`
+		require "toml"
+		TOML.dump({ servers: [{ name: "a" }, { name: "b" }] })
+		`, "[[servers]]\n  name = \"a\"\n\n[[servers]]\n  name = \"b\"\n"},
+		{`
+		require "toml"
+		h = { name: "blog", ratio: 2.5, ok: false, nested: { n: 1 } }
+		TOML.parse(TOML.dump(h)) == h
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTOMLMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "toml";TOML.new`, "NoMethodError: Undefined Method 'new' for TOML", 1},
+		{`require "toml";TOML.parse`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`require "toml";TOML.parse(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "toml";TOML.parse("a = 1\na = 2\n")`, "TOML::SyntaxError: line 2 (last key \"a\"): Key 'a' has already been defined.", 1},
+		{`require "toml";TOML.parse("a = \n")`, "TOML::SyntaxError: line 2 (last key \"a\"): expected value but found '\\n' instead", 1},
+		{`require "toml";TOML.parse_file("/tmp/goby/not_existing.toml")`, "IOError: open /tmp/goby/not_existing.toml: no such file or directory", 1},
+		{`require "toml";TOML.dump([1])`, "TypeError: Expect argument to be Hash. got: Array", 1},
+		{`require "toml";TOML.dump({ a: nil })`, "TypeError: Can't convert Null to TOML", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}
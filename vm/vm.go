@@ -2,7 +2,9 @@ package vm
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -23,27 +25,35 @@ const Version = "0.1.13"
 // DefaultLibPath is used for overriding vm.libpath build-time.
 var DefaultLibPath string
 
-type isIndexTable struct {
-	Data map[string]int
-}
-
-func newISIndexTable() *isIndexTable {
-	return &isIndexTable{Data: make(map[string]int)}
-}
-
-type isTable map[string][]*instructionSet
-
 type filename = string
 
 var standardLibraries = map[string]func(*VM){
-	"net/http":           initHTTPClass,
-	"net/simple_server":  initSimpleServerClass,
-	"uri":                initURIClass,
-	"json":               initJSONClass,
-	"concurrent/array":   initConcurrentArrayClass,
-	"concurrent/hash":    initConcurrentHashClass,
-	"concurrent/rw_lock": initConcurrentRWLockClass,
-	"spec":               initSpecClass,
+	"net/http":            initHTTPClass,
+	"net/simple_server":   initSimpleServerClass,
+	"toml":                initTOMLClass,
+	"uri":                 initURIClass,
+	"yaml":                initYAMLClass,
+	"json":                initJSONClass,
+	"concurrent/array":    initConcurrentArrayClass,
+	"concurrent/executor": initConcurrentExecutorClass,
+	"concurrent/hash":     initConcurrentHashClass,
+	"concurrent/rw_lock":  initConcurrentRWLockClass,
+	"csv":                 initCSVClass,
+	"digest":              initDigestClass,
+	"hmac":                initHMACClass,
+	"base64":              initBase64Class,
+	"hex":                 initHexClass,
+	"crypto":              initCryptoClass,
+	"securerandom":        initSecureRandomClass,
+	"tempfile":            initTempfileClass,
+	"template":            initTemplateClass,
+	"compress/gzip":       initGzipClass,
+	"compress/zlib":       initZlibClass,
+	"archive/tar":         initTarClass,
+	"archive/zip":         initZipClass,
+	"logger":              initLoggerClass,
+	"option_parser":       initOptionParserClass,
+	"spec":                initSpecClass,
 }
 
 // VM represents a stack based virtual machine.
@@ -51,14 +61,6 @@ type VM struct {
 	mainObj     *RObject
 	mainThread  Thread
 	objectClass *RClass
-	// a map holds different types of instruction set tables
-	isTables map[setType]isTable
-	// method instruction set table
-	methodISIndexTables map[filename]*isIndexTable
-	// class instruction set table
-	classISIndexTables map[filename]*isIndexTable
-	// block instruction set table
-	blockTables map[filename]map[string]*instructionSet
 	// fileDir indicates executed file's directory
 	fileDir string
 	// args are command line arguments
@@ -78,26 +80,73 @@ type VM struct {
 	libFiles []string
 
 	threadCount int64
+
+	// classDefinitionMutex makes sure a class defined by multiple threads at the same time is only created once
+	classDefinitionMutex sync.Mutex
+
+	// stdout and stderr are the writers behind `STDOUT` and `STDERR`
+	stdout io.Writer
+	stderr io.Writer
+
+	globals      map[string]Object
+	globalsMutex sync.RWMutex
+
+	// children are the processes started by `Process.spawn`, which are waited by `Process.wait`
+	children      map[int]*exec.Cmd
+	childrenMutex sync.Mutex
+
+	signalHandler signalHandler
+
+	// exitHooks are run when the program exits, see `RunExitHooks`
+	exitHooks []func()
+	exitMutex sync.Mutex
+
+	// random is the default generator used by `rand`, `Array#shuffle` and `Array#sample`, seeded with randomSeed
+	random     *RandomObject
+	randomSeed int64
+
+	// templates are the template files compiled by `Template.file`, keyed by their absolute paths
+	templates      map[string]*TemplateObject
+	templatesMutex sync.Mutex
+}
+
+// Option configures a vm created by New
+type Option func(*VM)
+
+// WithOutput makes the vm write its standard output, such as `puts` and `STDOUT.write`, to w instead of os.Stdout.
+func WithOutput(w io.Writer) Option {
+	return func(vm *VM) {
+		vm.stdout = w
+	}
+}
+
+// WithErrorOutput makes the vm write its standard error, such as `warn` and uncaught errors, to w instead of os.Stderr.
+func WithErrorOutput(w io.Writer) Option {
+	return func(vm *VM) {
+		vm.stderr = w
+	}
+}
+
+// WithSeed seeds the vm's default random generator, so that `rand`, `Array#shuffle` and `Array#sample`
+// generate the same values in every run.
+func WithSeed(seed int64) Option {
+	return func(vm *VM) {
+		vm.randomSeed = seed
+	}
 }
 
 // New initializes a vm to initialize state and returns it.
-func New(fileDir string, args []string) (vm *VM, e error) {
-	vm = &VM{args: args}
+func New(fileDir string, args []string, options ...Option) (vm *VM, e error) {
+	vm = &VM{args: args, stdout: os.Stdout, stderr: os.Stderr, globals: map[string]Object{}, randomSeed: newRandomSeed()}
+
+	for _, option := range options {
+		option(vm)
+	}
+
 	vm.mainThread.vm = vm
 	vm.threadCount++
 	vm.mode = parser.NormalMode
 
-	vm.methodISIndexTables = map[filename]*isIndexTable{
-		fileDir: newISIndexTable(),
-	}
-	vm.classISIndexTables = map[filename]*isIndexTable{
-		fileDir: newISIndexTable(),
-	}
-	vm.blockTables = make(map[filename]map[string]*instructionSet)
-	vm.isTables = map[setType]isTable{
-		bytecode.MethodDef: make(isTable),
-		bytecode.ClassDef:  make(isTable),
-	}
 	vm.fileDir = fileDir
 
 	err := vm.assignLibPath()
@@ -141,7 +190,6 @@ func (vm *VM) assignLibPath() (err error) {
 		// if GOBY_ROOT is not set, fallback to homebrew's path
 		gobyRoot = fmt.Sprintf("/usr/local/Cellar/goby/%s", Version)
 
-
 		// if it's not installed via homebrew, assume it's in development env and Goby's source is under GOPATH
 		if _, err := os.Stat(gobyRoot); err != nil {
 			path, _ := filepath.Abs(os.Getenv("GOPATH") + "/src/github.com/goby-lang/goby")
@@ -161,63 +209,18 @@ func (vm *VM) assignLibPath() (err error) {
 
 // ExecInstructions accepts a sequence of bytecodes and use vm to evaluate them.
 func (vm *VM) ExecInstructions(sets []*bytecode.InstructionSet, fn string) {
-	translator := newInstructionTranslator(fn)
-	translator.vm = vm
-	translator.transferInstructionSets(sets)
-
-	// Keep instruction set table updated after parsed new files.
-	// TODO: Find more efficient way to do this.
-	for setType, table := range translator.setTable {
-		for name, is := range table {
-			vm.isTables[setType][name] = is
-		}
-	}
-
-	vm.blockTables[translator.filename] = translator.blockTable
-	vm.SetClassISIndexTable(translator.filename)
-	vm.SetMethodISIndexTable(translator.filename)
-
-	cf := newNormalCallFrame(translator.program, translator.filename, 1)
-	cf.self = vm.mainObj
-	vm.mainThread.callFrameStack.push(cf)
-
-	// here is the final destination of Goby errors at the VM level, and we don't deal with them at this point.
-	// we only decide how the user program should react to them.
-	// at vm level, we don't deal with the error itself
-	// we only decide how the program should react to it
-	defer func() {
-		switch err := recover().(type) {
-		// if the error is a true Go panic, Goby can't handle it properly and we should re-raise it again
-		// it means Goby can't handle it properly and we should just let it crash
-		case error:
-			panic(err)
-
-			// if the error is one of the Goby's errors, such as argument error, we need to handle it depending on the mode of execution.
-			// we need to handle it depends on the type of program execution
-		case *Error:
-
-			// REPLMode: We handle the error inside the igb package, so don't need to do anything here
-			// TestMode: We should preserve the vm as it is and inspect its state via test helpers, so don't need to do anything here either
-			// NormalMode (normal file execution): we should print our the error and exit the program
-			if vm.mode == parser.NormalMode {
-				fmt.Fprintln(os.Stderr, err.Message())
-				os.Exit(1)
-			}
-		}
-	}()
-
-	vm.mainThread.startFromTopFrame()
+	vm.mainThread.execInstructions(sets, fn)
 }
 
-// SetClassISIndexTable adds new instruction set's index table to vm.classISIndexTables
-func (vm *VM) SetClassISIndexTable(fn filename) {
-	vm.classISIndexTables[fn] = newISIndexTable()
-}
+// SetClassISIndexTable does nothing.
+//
+// Deprecated: class instruction sets are resolved when the instructions are translated, so there's no index table to set.
+func (vm *VM) SetClassISIndexTable(fn string) {}
 
-// SetMethodISIndexTable adds new instruction set's index table to vm.methodISIndexTables
-func (vm *VM) SetMethodISIndexTable(fn filename) {
-	vm.methodISIndexTables[fn] = newISIndexTable()
-}
+// SetMethodISIndexTable does nothing.
+//
+// Deprecated: method instruction sets are resolved when the instructions are translated, so there's no index table to set.
+func (vm *VM) SetMethodISIndexTable(fn string) {}
 
 // main object singleton methods -----------------------------------------------------
 func builtinMainObjSingletonMethods() []*BuiltinMethodObject {
@@ -265,10 +268,16 @@ func (vm *VM) initConstants() {
 		vm.initChannelClass(),
 		vm.initGoClass(),
 		vm.initFileClass(),
+		vm.initStringIOClass(),
+		vm.initDirClass(),
 		vm.initRegexpClass(),
 		vm.initMatchDataClass(),
 		vm.initGoMapClass(),
 		vm.initDecimalClass(),
+		vm.initContextClass(),
+		vm.initProcessModule(),
+		vm.initSignalModule(),
+		vm.initRandomClass(),
 	}
 
 	// Init error classes
@@ -278,6 +287,10 @@ func (vm *VM) initConstants() {
 		vm.objectClass.setClassConstant(c)
 	}
 
+	// Math's and File's constants are Float and Integer objects, so they're initialized after the builtin classes
+	vm.objectClass.setClassConstant(vm.initMathModule())
+	vm.initFileConstants(vm.TopLevelClass(classes.FileClass))
+
 	// Init ARGV
 	args := []Object{}
 
@@ -285,7 +298,7 @@ func (vm *VM) initConstants() {
 		args = append(args, vm.InitStringObject(arg))
 	}
 
-	vm.objectClass.constants["ARGV"] = &Pointer{Target: vm.InitArrayObject(args)}
+	vm.objectClass.setConstant("ARGV", &Pointer{Target: vm.InitArrayObject(args)})
 
 	// Init ENV
 	envs := map[string]Object{}
@@ -295,10 +308,40 @@ func (vm *VM) initConstants() {
 		envs[pair[0]] = vm.InitStringObject(pair[1])
 	}
 
-	vm.objectClass.constants["ENV"] = &Pointer{Target: vm.InitHashObject(envs)}
-	vm.objectClass.constants["STDOUT"] = &Pointer{Target: vm.initFileObject(os.Stdout)}
-	vm.objectClass.constants["STDERR"] = &Pointer{Target: vm.initFileObject(os.Stderr)}
-	vm.objectClass.constants["STDIN"] = &Pointer{Target: vm.initFileObject(os.Stdin)}
+	vm.objectClass.setConstant("ENV", &Pointer{Target: vm.InitHashObject(envs)})
+
+	stdout := vm.initStandardFileObject(os.Stdout, vm.stdout)
+	stderr := vm.initStandardFileObject(os.Stderr, vm.stderr)
+	stdin := vm.initFileObject(os.Stdin)
+	vm.objectClass.setConstant("STDOUT", &Pointer{Target: stdout})
+	vm.objectClass.setConstant("STDERR", &Pointer{Target: stderr})
+	vm.objectClass.setConstant("STDIN", &Pointer{Target: stdin})
+
+	// `puts`, `print` and `warn` write to `$stdout` and `$stderr`, which can be reassigned to redirect the output
+	vm.setGlobal("$stdout", stdout)
+	vm.setGlobal("$stderr", stderr)
+	vm.setGlobal("$stdin", stdin)
+}
+
+// getGlobal returns the value of the given global variable, or NULL if it's not assigned yet
+func (vm *VM) getGlobal(name string) Object {
+	vm.globalsMutex.RLock()
+	defer vm.globalsMutex.RUnlock()
+
+	v, ok := vm.globals[name]
+
+	if !ok {
+		return NULL
+	}
+
+	return v
+}
+
+func (vm *VM) setGlobal(name string, value Object) {
+	vm.globalsMutex.Lock()
+	defer vm.globalsMutex.Unlock()
+
+	vm.globals[name] = value
 }
 
 // TopLevelClass returns a specified top-level class (stored under the Object constant)
@@ -309,41 +352,52 @@ func (vm *VM) TopLevelClass(cn string) *RClass {
 		return objClass
 	}
 
-	return objClass.constants[cn].Target.(*RClass)
+	constant, _ := objClass.getConstant(cn)
+	return constant.Target.(*RClass)
 }
 
-func (vm *VM) currentFilePath() string {
-	frame := vm.mainThread.callFrameStack.top()
+func (t *Thread) currentFilePath() string {
+	frame := t.callFrameStack.top()
 	return frame.FileName()
 }
 
-// loadConstant makes sure we don't create a class twice.
+// lookupOrDefineClass runs the given class lookup or definition exclusively among threads.
+func (vm *VM) lookupOrDefineClass(define func() *Pointer) *Pointer {
+	vm.classDefinitionMutex.Lock()
+	defer vm.classDefinitionMutex.Unlock()
+
+	return define()
+}
+
+// loadConstant makes sure we don't create a class twice, even when the class is loaded by multiple threads at the same time.
 func (vm *VM) loadConstant(name string, isModule bool) *RClass {
-	var c *RClass
-	var ptr *Pointer
+	ptr := vm.lookupOrDefineClass(func() *Pointer {
+		ptr, _ := vm.objectClass.getConstant(name)
 
-	ptr = vm.objectClass.constants[name]
+		if ptr == nil {
+			var c *RClass
+
+			if isModule {
+				c = vm.initializeClass(name)
+			} else {
+				c = vm.initializeModule(name)
+			}
 
-	if ptr == nil {
-		if isModule {
-			c = vm.initializeClass(name)
-		} else {
-			c = vm.initializeModule(name)
+			ptr = &Pointer{Target: c}
+			vm.objectClass.setConstant(c.Name, ptr)
 		}
 
-		vm.objectClass.setClassConstant(c)
-	} else {
-		c = ptr.Target.(*RClass)
-	}
+		return ptr
+	})
 
-	return c
+	return ptr.Target.(*RClass)
 }
 
-func (vm *VM) lookupConstant(cf callFrame, constName string) (constant *Pointer) {
+func (t *Thread) lookupConstant(cf callFrame, constName string) (constant *Pointer) {
 	var namespace *RClass
 	var hasNamespace bool
 
-	top := vm.mainThread.Stack.top()
+	top := t.Stack.top()
 
 	if top == nil {
 		hasNamespace = false
@@ -362,11 +416,11 @@ func (vm *VM) lookupConstant(cf callFrame, constName string) (constant *Pointer)
 	constant = cf.lookupConstantUnderAllScope(constName)
 
 	if constant == nil {
-		constant = vm.objectClass.constants[constName]
+		constant, _ = t.vm.objectClass.getConstant(constName)
 	}
 
 	if constName == classes.ObjectClass {
-		constant = &Pointer{Target: vm.objectClass}
+		constant = &Pointer{Target: t.vm.objectClass}
 	}
 
 	return
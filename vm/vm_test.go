@@ -1,7 +1,9 @@
 package vm
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -254,6 +256,77 @@ func TestAutoIncrementLocalVariable(t *testing.T) {
 	vm.checkSP(t, i, 1)
 }
 
+func TestVMOutputOptions(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput string
+		expectedError  string
+	}{
+		{`
+		puts("foo", 1)
+		print("bar", nil, "\n")
+		`, "foo\n1\nbar\n", ""},
+		{`
+		warn("deprecated")
+		$stderr.puts("foo", "bar")
+		STDERR.print("baz")
+		`, "", "deprecated\nfoo\nbar\nbaz"},
+		{`
+		STDOUT.write("foo", 1)
+		$stdout.puts("bar")
+		`, "foo1bar\n", ""},
+		{`
+		$stdout = STDERR
+		puts("foo")
+		$stdout = STDOUT
+		puts("bar")
+		`, "bar\n", "foo\n"},
+		{`
+		class Recorder
+		  attr_reader("lines")
+
+		  def initialize
+		    @lines = []
+		  end
+
+		  def write(str)
+		    @lines.push(str)
+		  end
+		end
+
+		r = Recorder.new
+		$stdout = r
+		puts("foo", "bar")
+		print("baz")
+		$stdout = STDOUT
+		puts(r.lines.to_s)
+		`, "[\"foo\\nbar\\n\", \"baz\"]\n", ""},
+	}
+
+	for i, tt := range tests {
+		var out, errOut bytes.Buffer
+		dir, _ := os.Getwd()
+		v, err := New(dir, []string{}, WithOutput(&out), WithErrorOutput(&errOut))
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		v.mode = parser.TestMode
+		v.testEval(t, tt.input, getFilename())
+
+		if out.String() != tt.expectedOutput {
+			t.Errorf("At case %d expect output to be %q. got: %q", i, tt.expectedOutput, out.String())
+		}
+
+		if errOut.String() != tt.expectedError {
+			t.Errorf("At case %d expect error output to be %q. got: %q", i, tt.expectedError, errOut.String())
+		}
+
+		v.checkCFP(t, i, 0)
+	}
+}
+
 func TestLoadingGobyLibraryFail(t *testing.T) {
 	vm := initTestVM()
 
@@ -0,0 +1,370 @@
+package vm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// YAML parses YAML into Hashes, Arrays, Strings, Integers, Floats, Booleans and nil, and dumps them back out.
+// The implementation internally uses the pure-Go package `gopkg.in/yaml.v3`.
+//
+// ```ruby
+// require "yaml"
+//
+// config = YAML.parse("server:\n  port: 8080\n  hosts: [a.example.com, b.example.com]\n")
+// config["server"]["port"]  # => 8080
+// config["server"]["hosts"] # => ["a.example.com", "b.example.com"]
+//
+// YAML.dump({ name: "blog", debug: false })
+// # => "debug: false\nname: blog\n"
+// ```
+//
+// Anchors, aliases and merge keys (`<<`) are supported. Timestamps are parsed as Strings, and keys are converted
+// to Strings since Hash keys are Strings in Goby.
+// Invalid YAML raises `YAML::SyntaxError` with the line number.
+//
+// - `YAML.new` is not supported.
+
+// Class methods --------------------------------------------------------
+var builtinYAMLClassMethods = []*BuiltinMethodObject{
+	{
+		// Returns the YAML of the object, whose Hash keys are sorted.
+		//
+		// ```ruby
+		// YAML.dump([1, "two", nil]) # => "- 1\n- two\n- null\n"
+		// ```
+		//
+		// @param object [Object]
+		// @return [String]
+		Name: "dump",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			node, err := yamlNodeOf(t, args[0], sourceLine)
+
+			if err != nil {
+				return err
+			}
+
+			var b bytes.Buffer
+			encoder := yaml.NewEncoder(&b)
+			encoder.SetIndent(2)
+
+			if err := encoder.Encode(node); err != nil {
+				return t.vm.InitErrorObject(errors.InternalError, sourceLine, err.Error())
+			}
+
+			encoder.Close()
+			return t.vm.InitStringObject(b.String())
+
+		},
+	},
+	{
+		Name: "new",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			return t.vm.InitNoMethodError(sourceLine, "new", receiver)
+
+		},
+	},
+	{
+		// Parses the first document of the YAML string. An empty document is nil.
+		//
+		// ```ruby
+		// YAML.parse("- 1\n- foo\n- true\n") # => [1, "foo", true]
+		// ```
+		//
+		// @param yaml [String]
+		// @return [Object]
+		Name: "parse",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			return t.parseYAML([]byte(args[0].(*StringObject).value), sourceLine)
+
+		},
+	},
+	{
+		// Parses the first document of the YAML file.
+		//
+		// ```ruby
+		// config = YAML.parse_file("config/app.yml")
+		// ```
+		//
+		// @param path [String]
+		// @return [Object]
+		Name: "parse_file",
+		Fn: func(receiver Object, sourceLine int, t *Thread, args []Object, blockFrame *normalCallFrame) Object {
+			if len(args) != 1 {
+				return t.vm.InitErrorObject(errors.ArgumentError, sourceLine, errors.WrongNumberOfArgument, 1, len(args))
+			}
+
+			typeErr := t.vm.checkArgTypes(args, sourceLine, classes.StringClass)
+
+			if typeErr != nil {
+				return typeErr
+			}
+
+			data, err := ioutil.ReadFile(args[0].(*StringObject).value)
+
+			if err != nil {
+				return t.vm.InitErrorObject(errors.IOError, sourceLine, err.Error())
+			}
+
+			return t.parseYAML(data, sourceLine)
+
+		},
+	},
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initYAMLClass(vm *VM) {
+	class := vm.initializeClass("YAML")
+	class.setBuiltinMethods(builtinYAMLClassMethods, true)
+	class.setClassConstant(vm.initializeClass("SyntaxError"))
+	vm.objectClass.setClassConstant(class)
+}
+
+// Other helper functions -----------------------------------------------
+
+func (t *Thread) parseYAML(data []byte, sourceLine int) Object {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return t.vm.InitErrorObject(errors.YAMLSyntaxError, sourceLine, "%s", strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+
+	obj, err := t.objectFromYAMLNode(&doc, yamlAnchors{}, sourceLine)
+
+	if err != nil {
+		return err
+	}
+
+	return obj
+}
+
+// yamlAnchors holds the objects converted from the anchored nodes. Aliases share the object instead of
+// converting the anchored node again, so that nested aliases can't expand a small document exponentially.
+// The object is nil while the anchored node is being converted.
+type yamlAnchors map[*yaml.Node]Object
+
+// objectFromYAMLNode converts the node and its children to objects
+func (t *Thread) objectFromYAMLNode(node *yaml.Node, anchors yamlAnchors, sourceLine int) (Object, *Error) {
+	if node.Anchor == "" {
+		return t.objectFromYAMLContent(node, anchors, sourceLine)
+	}
+
+	anchors[node] = nil
+	obj, err := t.objectFromYAMLContent(node, anchors, sourceLine)
+	anchors[node] = obj
+
+	return obj, err
+}
+
+func (t *Thread) objectFromYAMLContent(node *yaml.Node, anchors yamlAnchors, sourceLine int) (Object, *Error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return NULL, nil
+		}
+
+		return t.objectFromYAMLNode(node.Content[0], anchors, sourceLine)
+	case yaml.AliasNode:
+		obj, ok := anchors[node.Alias]
+
+		if !ok {
+			return t.objectFromYAMLNode(node.Alias, anchors, sourceLine)
+		}
+
+		if obj == nil {
+			return nil, t.vm.InitErrorObject(errors.YAMLSyntaxError, sourceLine, "line %d: anchor '%s' value contains itself", node.Line, node.Value)
+		}
+
+		return obj, nil
+	case yaml.SequenceNode:
+		elems := []Object{}
+
+		for _, n := range node.Content {
+			elem, err := t.objectFromYAMLNode(n, anchors, sourceLine)
+
+			if err != nil {
+				return nil, err
+			}
+
+			elems = append(elems, elem)
+		}
+
+		return t.vm.InitArrayObject(elems), nil
+	case yaml.MappingNode:
+		pairs := map[string]Object{}
+		// The keys given explicitly override the merged ones, regardless of their order
+		explicit := map[string]bool{}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, valueNode := node.Content[i], node.Content[i+1]
+			value, err := t.objectFromYAMLNode(valueNode, anchors, sourceLine)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge" {
+				merged := []Object{value}
+
+				if arr, ok := value.(*ArrayObject); ok {
+					merged = arr.Elements
+				}
+
+				for _, m := range merged {
+					h, ok := m.(*HashObject)
+
+					if !ok {
+						return nil, t.vm.InitErrorObject(errors.YAMLSyntaxError, sourceLine, "line %d: map merge requires map or sequence of maps as the value", key.Line)
+					}
+
+					for k, v := range h.Pairs {
+						if !explicit[k] {
+							pairs[k] = v
+						}
+					}
+				}
+
+				continue
+			}
+
+			k, err := t.objectFromYAMLNode(key, anchors, sourceLine)
+
+			if err != nil {
+				return nil, err
+			}
+
+			pairs[k.ToString()] = value
+			explicit[k.ToString()] = true
+		}
+
+		return t.vm.InitHashObject(pairs), nil
+	}
+
+	return t.objectFromYAMLScalar(node, sourceLine)
+}
+
+func (t *Thread) objectFromYAMLScalar(node *yaml.Node, sourceLine int) (Object, *Error) {
+	tag := node.ShortTag()
+
+	switch tag {
+	case "!!str", "!!timestamp", "!!binary":
+		return t.vm.InitStringObject(node.Value), nil
+	case "!!int", "!!float":
+		var i int
+
+		if tag == "!!int" && node.Decode(&i) == nil {
+			return t.vm.InitIntegerObject(i), nil
+		}
+
+		// Integers that don't fit in an int are resolved as floats, unless they're tagged explicitly
+		if tag == "!!int" || node.Style&yaml.TaggedStyle == 0 {
+			if b, ok := new(big.Int).SetString(strings.Replace(node.Value, "_", "", -1), 0); ok {
+				return t.vm.initIntegerObjectFromBigInt(b), nil
+			}
+		}
+	}
+
+	var v interface{}
+
+	if tag != "!!int" && node.Decode(&v) == nil {
+		return t.vm.InitObjectFromGoType(v), nil
+	}
+
+	return nil, t.vm.InitErrorObject(errors.YAMLSyntaxError, sourceLine, "line %d: invalid %s value %q", node.Line, tag, node.Value)
+}
+
+// yamlNodeOf converts the object to a node. Hash keys are sorted so that the output is stable.
+func yamlNodeOf(t *Thread, obj Object, sourceLine int) (*yaml.Node, *Error) {
+	switch obj := obj.(type) {
+	case *HashObject:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		keys := []string{}
+
+		for k := range obj.Pairs {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			value, err := yamlNodeOf(t, obj.Pairs[k], sourceLine)
+
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, value)
+		}
+
+		return node, nil
+	case *ArrayObject:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+		for _, elem := range obj.Elements {
+			value, err := yamlNodeOf(t, elem, sourceLine)
+
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, value)
+		}
+
+		return node, nil
+	case *StringObject:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: obj.value}, nil
+	case *IntegerObject:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: obj.ToString()}, nil
+	case *FloatObject:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: yamlFloat(obj.value)}, nil
+	case *BooleanObject:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(obj.value)}, nil
+	case *NullObject:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	default:
+		return nil, t.vm.InitErrorObject(errors.TypeError, sourceLine, errors.CantConvertTo, obj.Class().Name, "YAML")
+	}
+}
+
+// yamlFloat formats the float in the YAML way, like ".inf" and ".nan"
+func yamlFloat(f float64) string {
+	switch s := strconv.FormatFloat(f, 'g', -1, 64); s {
+	case "+Inf":
+		return ".inf"
+	case "-Inf":
+		return "-.inf"
+	case "NaN":
+		return ".nan"
+	default:
+		if !strings.ContainsAny(s, ".en") {
+			s += ".0"
+		}
+
+		return s
+	}
+}
@@ -0,0 +1,153 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestYAMLParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "yaml"
+		YAML.parse("- 1\n- foo\n- true\n- 1.5\n- ~\n- 2001-12-14\n- '42'\n")
+		`, []interface{}{1, "foo", true, 1.5, nil, "2001-12-14", "42"}},
+		{`
+		require "yaml"
+		config = YAML.parse("# comment\nserver:\n  port: 8080\n  hosts: [a.example.com, b.example.com]\n")
+		[config["server"]["port"], config["server"]["hosts"]]
+		`, []interface{}{8080, []interface{}{"a.example.com", "b.example.com"}}},
+		{`
+		require "yaml"
+		config = YAML.parse("base: &base\n  adapter: pg\n  pool: 5\ndev:\n  <<: *base\n  pool: 10\ntest:\n  pool: 1\n  <<: *base\n")
+		[config["dev"]["adapter"], config["dev"]["pool"], config["test"]["pool"]]
+		`, []interface{}{"pg", 10, 1}},
+		{`
+		require "yaml"
+		YAML.parse("script: |\n  echo foo\n  echo bar\nnote: >\n  folded\n  line\n")["script"]
+		`, "echo foo\necho bar\n"},
+		{`
+		require "yaml"
+		YAML.parse("1: one\ntrue: yes\n")["1"]
+		`, "one"},
+		{`
+		require "yaml"
+		YAML.parse("big: 123456789012345678901234567890\nhex: 0x1F")["big"].to_s
+		`, "123456789012345678901234567890"},
+		{`
+		require "yaml"
+		YAML.parse("hex: 0x1F")["hex"]
+		`, 31},
+		{`
+		require "yaml"
+		YAML.parse("")
+		`, nil},
+		{`
+		require "yaml"
+		YAML.parse("first: 1\n---\nsecond: 2\n")["first"]
+		`, 1},
+		{`
+		require "yaml"
+		config = YAML.parse("a: &a [1, 2]\nb: *a\n")
+		config["a"].object_id == config["b"].object_id
+		`, true},
+		{`
+		require "yaml"
+		doc = YAML.parse("a: &a [x, x, x, x, x, x, x, x, x, x]\nb: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a, *a]\nc: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b, *b]\nd: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c, *c]\ne: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d, *d]\nf: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e, *e]\ng: &g [*f, *f, *f, *f, *f, *f, *f, *f, *f, *f]\nh: &h [*g, *g, *g, *g, *g, *g, *g, *g, *g, *g]\ni: &i [*h, *h, *h, *h, *h, *h, *h, *h, *h, *h]\n")
+		doc["i"][9][9][9][9][9][9][9][9][9]
+		`, "x"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestYAMLDump(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "yaml"
+		YAML.dump({ name: "blog", debug: false, ports: [80, 443], ratio: 1.0, empty: nil })
+		`, "debug: false\nempty: null\nname: blog\nports:\n  - 80\n  - 443\nratio: 1.0\n"},
+		{`
+		require "yaml"
+		YAML.dump([1, "two", nil, { a: [] }])
+		`, "- 1\n- two\n- null\n- a: []\n"},
+		{`
+		require "yaml"
+		YAML.dump({ bool: "true", number: "42", multi: "a\nb" })
+		`, "bool: \"true\"\nmulti: |-\n  a\n  b\nnumber: \"42\"\n"},
+		{`
+		require "yaml"
+		h = { name: "blog", tags: ["a", "b"], nested: { n: 1, f: 2.5, ok: true } }
+		YAML.parse(YAML.dump(h)) == h
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestYAMLParseFile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "yaml"
+		File.open("/tmp/goby/config.yml", "w") do |f|
+		  f.write("database:\n  name: blog\n")
+		end
+
+		YAML.parse_file("/tmp/goby/config.yml")["database"]["name"]
+		`, "blog"},
+	}
+
+	setup()
+	defer teardown()
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		VerifyExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestYAMLMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "yaml";YAML.new`, "NoMethodError: Undefined Method 'new' for YAML", 1},
+		{`require "yaml";YAML.parse`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`require "yaml";YAML.parse(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`require "yaml";YAML.parse("a: 1\nb: c: d\n")`, "YAML::SyntaxError: line 2: mapping values are not allowed in this context", 1},
+		{`require "yaml";YAML.parse("a: 1\n\tb: 2\n")`, "YAML::SyntaxError: line 2: found a tab character that violates indentation", 1},
+		{`require "yaml";YAML.parse("a: !!int foo\n")`, "YAML::SyntaxError: line 1: invalid !!int value \"foo\"", 1},
+		{`require "yaml";YAML.parse("a:\n  <<: 1\n")`, "YAML::SyntaxError: line 2: map merge requires map or sequence of maps as the value", 1},
+		{`require "yaml";YAML.parse("a: &a\n  b: *a\n")`, "YAML::SyntaxError: line 2: anchor 'a' value contains itself", 1},
+		{`require "yaml";YAML.parse_file("/tmp/goby/not_existing.yml")`, "IOError: open /tmp/goby/not_existing.yml: no such file or directory", 1},
+		{`require "yaml";YAML.dump`, "ArgumentError: Expect 1 argument(s). got: 0", 1},
+		{`require "yaml";YAML.dump({ a: 1..2 })`, "TypeError: Can't convert Range to YAML", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkErrorMsg(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, tt.expectedCFP)
+		v.checkSP(t, i, 1)
+	}
+}